@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// SimulationResponse is returned by GET /assignments/simulate
+type SimulationResponse struct {
+	Pods        []string          `json:"pods"`        // Pods the jobs were spread across
+	Assignments map[string]string `json:"assignments"` // Pod each due job would be assigned to, keyed by job ID
+}
+
+// handleSimulateAssignment previews how due jobs would be assigned without changing them.
+// Pods default to the live, responsive pods, ?pods=a,b,c simulates a different set.
+func (s *Server) handleSimulateAssignment(w http.ResponseWriter, r *http.Request) {
+	var pods []string
+	if param := r.URL.Query().Get("pods"); param != "" {
+		for _, podID := range strings.Split(param, ",") {
+			if podID = strings.TrimSpace(podID); podID != "" {
+				pods = append(pods, podID)
+			}
+		}
+	} else {
+		live, err := s.podManager.ListPods(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, pod := range live {
+			if pod.Alive(leader.PodTTL(s.config)) {
+				pods = append(pods, pod.ID)
+			}
+		}
+	}
+
+	if len(pods) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no pods to simulate assignment with"))
+		return
+	}
+
+	assignments, err := s.scheduler.SimulateAssignment(r.Context(), pods)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, SimulationResponse{Pods: pods, Assignments: assignments})
+}
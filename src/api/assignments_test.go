@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestSimulateAssignment(t *testing.T) {
+	s, sched := newTestServer(t, testConfig())
+	sched.RegisterCommand(command.NewFuncCommand("work", "test command", "", nil))
+	now := time.Now()
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, StartTime: now.Add(-time.Hour)},
+		leader.PodInfo{ID: "gone-pod", LastSeen: now.Add(-time.Hour)},
+	)
+	first := storeLabeledJob(t, now.Add(-2*time.Second), nil)
+	second := storeLabeledJob(t, now.Add(-time.Second), nil)
+	before := testRedis.Dump()
+
+	var live SimulationResponse
+	decode(t, serve(t, s, http.MethodGet, "/assignments/simulate", nil), http.StatusOK, &live)
+	if !slices.Equal(live.Pods, []string{testPodID}) || live.Assignments[first.ID] != testPodID || live.Assignments[second.ID] != testPodID {
+		t.Fatalf("simulation over live pods = %+v, want both jobs on %s", live, testPodID)
+	}
+
+	var named SimulationResponse
+	decode(t, serve(t, s, http.MethodGet, "/assignments/simulate?pods=pod-a,%20pod-b", nil), http.StatusOK, &named)
+	if !slices.Equal(named.Pods, []string{"pod-a", "pod-b"}) || len(named.Assignments) != 2 ||
+		named.Assignments[first.ID] == named.Assignments[second.ID] {
+		t.Fatalf("simulation over named pods = %+v, want the jobs spread over pod-a and pod-b", named)
+	}
+
+	if rec := serve(t, s, http.MethodGet, "/assignments/simulate?pods=,", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("simulation without pods = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if testRedis.Dump() != before {
+		t.Fatal("simulating assignment changed Redis")
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// CommandInfo describes a registered command in GET /commands
+type CommandInfo struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	Params      []command.ParamSpec `json:"params,omitempty"` // Typed params, omitted for commands without a schema
+}
+
+// handleListCommands lists the registered commands with their param schemas, ordered by ID
+func (s *Server) handleListCommands(w http.ResponseWriter, r *http.Request) {
+	commands := s.scheduler.Commands()
+	infos := make([]CommandInfo, 0, len(commands))
+	for id, cmd := range commands {
+		schema, _ := command.ParamSchemaOf(cmd)
+		infos = append(infos, CommandInfo{ID: id, Description: cmd.Description(), Params: schema})
+	}
+	slices.SortFunc(infos, func(a, b CommandInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// handleGetCommandSchema returns the param schema of the command in the path, empty for commands without one
+func (s *Server) handleGetCommandSchema(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	cmd, exists := s.scheduler.GetCommand(id)
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown command: %s", id))
+		return
+	}
+	schema, _ := command.ParamSchemaOf(cmd)
+	if schema == nil {
+		schema = []command.ParamSpec{}
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// TriggerRequest is the optional body of POST /commands/{id}/trigger
+type TriggerRequest struct {
+	Params []string          `json:"params"` // Merged over the command's defaults, omit to use them as-is
+	Labels map[string]string `json:"labels"` // Added to the command's labels, overriding them on conflicting keys
+}
+
+// handleTriggerCommand enqueues a job of the command in the path that is due now
+func (s *Server) handleTriggerCommand(w http.ResponseWriter, r *http.Request) {
+	var req TriggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	job, err := s.scheduler.TriggerJob(r.Context(), r.PathValue("id"), req.Params, req.Labels)
+	writeEnqueued(w, job, err)
+}
+
+// CancelResponse is returned by POST /commands/{id}/cancel
+type CancelResponse struct {
+	Command   string `json:"command"`
+	Cancelled int    `json:"cancelled"` // Scheduled and assigned jobs cancelled, running jobs are left to finish
+}
+
+// handleCancelCommandJobs cancels the outstanding jobs of the command in the path. The
+// command does not have to be registered, so jobs of a decommissioned command can be cancelled.
+func (s *Server) handleCancelCommandJobs(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := command.ValidateCommandID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cancelled, err := s.scheduler.CancelJobsForCommand(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, CancelResponse{Command: id, Cancelled: cancelled})
+}
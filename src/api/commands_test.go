@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestCommandSchemaEndpoints(t *testing.T) {
+	s, sched := newTestServer(t, testConfig())
+	sched.RegisterCommand(command.NewPingCommand("localhost", 4, 1))
+	sched.RegisterCommand(command.NewFuncCommand("work", "test command", "", func(ctx context.Context, params []string) (string, error) {
+		return "", nil
+	}))
+
+	var schema []command.ParamSpec
+	decode(t, serve(t, s, http.MethodGet, "/commands/ping/schema", nil), http.StatusOK, &schema)
+	if len(schema) != 3 || schema[0].Name != "host" || schema[1].Type != command.ParamInt || schema[2].Type != command.ParamFloat {
+		t.Fatalf("ping schema = %+v, want host:string, count:int, interval:float", schema)
+	}
+
+	decode(t, serve(t, s, http.MethodGet, "/commands/work/schema", nil), http.StatusOK, &schema)
+	if len(schema) != 0 {
+		t.Fatalf("schema of a command without one = %+v, want empty", schema)
+	}
+
+	decode(t, serve(t, s, http.MethodGet, "/commands/missing/schema", nil), http.StatusNotFound, nil)
+
+	var commands []CommandInfo
+	decode(t, serve(t, s, http.MethodGet, "/commands", nil), http.StatusOK, &commands)
+	if len(commands) != 2 || commands[0].ID != "ping" || len(commands[0].Params) != 3 || commands[1].ID != "work" {
+		t.Fatalf("GET /commands = %+v, want ping with its schema and work", commands)
+	}
+}
+
+func TestTriggerCommand(t *testing.T) {
+	s, sched := newTestServer(t, testConfig())
+	sched.RegisterCommand(command.NewPingCommand("localhost", 4, 1))
+
+	tests := []struct {
+		name   string
+		body   any
+		status int
+		params []string
+	}{
+		{"defaults", nil, http.StatusCreated, []string{"google.com", "4", "1"}},
+		{"override", TriggerRequest{Params: []string{"example.com", "2", "0.5"}}, http.StatusCreated, []string{"example.com", "2", "0.5"}},
+		{"partial override", TriggerRequest{Params: []string{"example.com"}}, http.StatusCreated, []string{"example.com", "4", "1"}},
+		{"invalid param", TriggerRequest{Params: []string{"example.com", "four"}}, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRedis.Del(command.JobsSortedSetKey)
+			var job command.Job
+			recorder := serve(t, s, http.MethodPost, "/commands/ping/trigger", tt.body)
+			if tt.status != http.StatusCreated {
+				decode(t, recorder, tt.status, nil)
+				return
+			}
+			decode(t, recorder, tt.status, &job)
+			if !slices.Equal(job.Params, tt.params) {
+				t.Fatalf("job params = %q, want %q", job.Params, tt.params)
+			}
+			if !testRedis.Exists(fmt.Sprintf(command.JobDetailsKey, job.ID)) {
+				t.Fatal("triggered job was not stored")
+			}
+		})
+	}
+}
+
+func TestTriggerCommandLabels(t *testing.T) {
+	s, sched := newTestServer(t, testConfig())
+	sched.RegisterCommand(command.NewPingCommand("localhost", 4, 1))
+
+	var job command.Job
+	decode(t, serve(t, s, http.MethodPost, "/commands/ping/trigger", TriggerRequest{Labels: map[string]string{"team": "payments"}}), http.StatusCreated, &job)
+	if job.Labels["team"] != "payments" {
+		t.Fatalf("job labels = %v, want team:payments", job.Labels)
+	}
+
+	decode(t, serve(t, s, http.MethodPost, "/commands/missing/trigger", nil), http.StatusBadRequest, nil)
+}
+
+func TestCancelCommandJobs(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	// Jobs of commands that are no longer registered can still be cancelled
+	job := command.NewJob("work", nil, time.Now())
+	if err := job.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+
+	var response CancelResponse
+	decode(t, serve(t, s, http.MethodPost, "/commands/work/cancel", nil), http.StatusOK, &response)
+	if response.Command != "work" || response.Cancelled != 1 {
+		t.Fatalf("POST /commands/work/cancel = %+v, want the job of the unregistered command cancelled", response)
+	}
+
+	decode(t, serve(t, s, http.MethodPost, "/commands/bad.id/cancel", nil), http.StatusBadRequest, nil)
+}
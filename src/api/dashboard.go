@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// DashboardVersion is bumped whenever a field of DashboardResponse changes meaning or is removed
+const DashboardVersion = 1
+
+const (
+	// dashboardRecentJobs caps the recent failures and completions in the dashboard
+	dashboardRecentJobs = 10
+	// dashboardCompletedScan is how many of the latest completed jobs are read to find them
+	dashboardCompletedScan = 100
+)
+
+// DashboardResponse is returned by GET /dashboard. Fields are only added within a
+// version, clients should check Version before relying on the shape.
+type DashboardResponse struct {
+	Version           int                       `json:"version"`
+	GeneratedAt       time.Time                 `json:"generated_at"`
+	LeaderID          string                    `json:"leader_id"`          // Empty while no pod is alive
+	Pods              []leader.PodInfo          `json:"pods"`               // Live pods, as in GET /pods
+	JobCounts         map[command.JobStatus]int `json:"job_counts"`         // Pending jobs per status
+	RecentFailures    []*command.Job            `json:"recent_failures"`    // Latest failed jobs, newest first
+	RecentCompletions []*command.Job            `json:"recent_completions"` // Latest other finished jobs, newest first
+}
+
+// handleDashboard returns pods, the leader, job counts and recent jobs in one response.
+// Recent jobs come from the completed jobs retained with COMPLETED_JOB_RETENTION.
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pods, err := s.podManager.ListPods(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	dashboard := DashboardResponse{
+		Version:           DashboardVersion,
+		GeneratedAt:       time.Now(),
+		Pods:              pods,
+		JobCounts:         make(map[command.JobStatus]int),
+		RecentFailures:    []*command.Job{},
+		RecentCompletions: []*command.Job{},
+	}
+	for _, pod := range pods {
+		if pod.IsLeader {
+			dashboard.LeaderID = pod.ID
+		}
+	}
+
+	pending, completed, err := s.loadDashboardJobs(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, job := range pending {
+		dashboard.JobCounts[job.Status]++
+	}
+	for _, job := range completed {
+		switch {
+		case job.Status == command.Failed && len(dashboard.RecentFailures) < dashboardRecentJobs:
+			dashboard.RecentFailures = append(dashboard.RecentFailures, job)
+		case job.Status != command.Failed && len(dashboard.RecentCompletions) < dashboardRecentJobs:
+			dashboard.RecentCompletions = append(dashboard.RecentCompletions, job)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, dashboard)
+}
+
+// loadDashboardJobs returns the pending jobs and the latest completed jobs, newest first.
+// Job IDs and then all job details are each read in a single pipelined round-trip.
+func (s *Server) loadDashboardJobs(ctx context.Context) (pending, completed []*command.Job, err error) {
+	client := s.redisClient.GetClient()
+
+	pipe := client.Pipeline()
+	pendingIDs := pipe.ZRange(ctx, command.JobsSortedSetKey, 0, -1)
+	completedIDs := pipe.ZRevRange(ctx, command.CompletedJobsKey, 0, dashboardCompletedScan-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	pipe = client.Pipeline()
+	pendingGets := make([]*redis.StringCmd, 0, len(pendingIDs.Val()))
+	for _, id := range pendingIDs.Val() {
+		pendingGets = append(pendingGets, pipe.Get(ctx, fmt.Sprintf(command.JobDetailsKey, id)))
+	}
+	completedGets := make([]*redis.StringCmd, 0, len(completedIDs.Val()))
+	for _, id := range completedIDs.Val() {
+		completedGets = append(completedGets, pipe.Get(ctx, fmt.Sprintf(command.JobDetailsKey, id)))
+	}
+	// Expired details show up as redis.Nil on their own command and are skipped below
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, nil, fmt.Errorf("failed to fetch job details: %w", err)
+	}
+
+	return s.decodeJobs(pendingGets), s.decodeJobs(completedGets), nil
+}
+
+// decodeJobs decodes the results of pipelined job detail reads, skipping missing and undecodable jobs
+func (s *Server) decodeJobs(gets []*redis.StringCmd) []*command.Job {
+	jobs := make([]*command.Job, 0, len(gets))
+	for _, get := range gets {
+		data, err := get.Bytes()
+		if err != nil {
+			continue
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			s.logger.Warn("Skipping undecodable job", "key", get.Args()[1], "error", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs
+}
+
+// handleStatusReport returns a consistency report of the scheduler state
+func (s *Server) handleStatusReport(w http.ResponseWriter, r *http.Request) {
+	report, err := s.scheduler.StatusReport(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
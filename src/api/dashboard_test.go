@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+)
+
+// storeFinishedJob stores a job that finished at finishedAt with status and retains it as completed
+func storeFinishedJob(t *testing.T, finishedAt time.Time, status command.JobStatus) *command.Job {
+	t.Helper()
+	ctx := context.Background()
+	job := command.NewJob("work", nil, finishedAt.Add(-time.Minute))
+	job.Status, job.FinishedAt = status, &finishedAt
+	if err := job.UpdateInRedis(ctx, testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+	if err := testClient.GetClient().ZAdd(ctx, command.CompletedJobsKey, redis.Z{Score: command.JobScore(finishedAt), Member: job.ID}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestDashboardAggregatesSections(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	now := time.Now()
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, StartTime: now.Add(-time.Hour)},
+		leader.PodInfo{ID: "worker-pod", StartTime: now},
+	)
+
+	storeLabeledJob(t, now.Add(time.Minute), nil)
+	assigned := command.NewJob("work", nil, now.Add(2*time.Minute))
+	assigned.AssignedTo, assigned.Status = "worker-pod", command.Assigned
+	if err := assigned.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+	failed := storeFinishedJob(t, now.Add(-3*time.Minute), command.Failed)
+	older := storeFinishedJob(t, now.Add(-2*time.Minute), command.Success)
+	newer := storeFinishedJob(t, now.Add(-time.Minute), command.Success)
+
+	var dashboard DashboardResponse
+	decode(t, serve(t, s, http.MethodGet, "/dashboard", nil), http.StatusOK, &dashboard)
+
+	if dashboard.Version != DashboardVersion || dashboard.GeneratedAt.IsZero() {
+		t.Fatalf("dashboard version %d generated at %s, want version %d", dashboard.Version, dashboard.GeneratedAt, DashboardVersion)
+	}
+	if dashboard.LeaderID != testPodID || len(dashboard.Pods) != 2 {
+		t.Fatalf("dashboard leader %q with %d pods, want %s and 2 pods", dashboard.LeaderID, len(dashboard.Pods), testPodID)
+	}
+	if dashboard.JobCounts[command.Scheduled] != 1 || dashboard.JobCounts[command.Assigned] != 1 {
+		t.Fatalf("job counts %v, want 1 scheduled and 1 assigned", dashboard.JobCounts)
+	}
+	if len(dashboard.RecentFailures) != 1 || dashboard.RecentFailures[0].ID != failed.ID {
+		t.Fatalf("recent failures %v, want %s", dashboard.RecentFailures, failed.ID)
+	}
+	if completions := dashboard.RecentCompletions; len(completions) != 2 || completions[0].ID != newer.ID || completions[1].ID != older.ID {
+		t.Fatalf("recent completions %v, want %s then %s", completions, newer.ID, older.ID)
+	}
+}
+
+func TestStatusReport(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	storeFinishedJob(t, time.Now(), command.Success)
+
+	var report scheduler.StatusReport
+	decode(t, serve(t, s, http.MethodGet, "/status/report", nil), http.StatusOK, &report)
+	if !report.Healthy {
+		t.Fatalf("report of consistent state is unhealthy: %s", report.Summary())
+	}
+
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "dead-pod", LastSeen: time.Now().Add(-time.Hour)})
+	report = scheduler.StatusReport{}
+	decode(t, serve(t, s, http.MethodGet, "/status/report", nil), http.StatusOK, &report)
+	if report.Healthy || len(report.DeadPods) != 1 || report.DeadPods[0] != "dead-pod" {
+		t.Fatalf("report = %s listing dead pods %q, want dead-pod listed", report.Summary(), report.DeadPods)
+	}
+}
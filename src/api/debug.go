@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+)
+
+// resetPatterns are the job related keys removed by a debug reset. Pod
+// registry and leadership keys are left alone so the cluster keeps running.
+var resetPatterns = []string{
+	"scheduler:job:*",
+	"scheduler:job_output:*",
+	"schedulerx:job_lock:*",
+	"schedulerx:last_failed_pod:*",
+	"schedulerx:output_hash:*",
+	"schedulerx:result_cache:*",
+	"schedulerx:cooldown:*",
+	"schedulerx:corrupt",
+	"schedulerx:completed",
+	"schedulerx:assignment_cursor",
+}
+
+// errDebugDisabled is returned when debug endpoints are called without ENABLE_DEBUG_ENDPOINTS
+var errDebugDisabled = errors.New("debug endpoints are disabled, set ENABLE_DEBUG_ENDPOINTS=true to enable them")
+
+// handleDebugReset clears all jobs and their per-command state
+func (s *Server) handleDebugReset(w http.ResponseWriter, r *http.Request) {
+	if !s.config.EnableDebugEndpoints {
+		writeError(w, http.StatusForbidden, errDebugDisabled)
+		return
+	}
+
+	deleted, err := s.resetJobs(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.logger.Warn("Scheduler state reset via debug endpoint", "deleted_keys", deleted)
+	writeJSON(w, http.StatusOK, map[string]int{"deleted": deleted})
+}
+
+// handleDebugExport returns a snapshot of all jobs, pods and counters
+func (s *Server) handleDebugExport(w http.ResponseWriter, r *http.Request) {
+	if !s.config.EnableDebugEndpoints {
+		writeError(w, http.StatusForbidden, errDebugDisabled)
+		return
+	}
+
+	snapshot, err := s.scheduler.ExportState(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// handleDebugImport writes a snapshot produced by GET /debug/export
+func (s *Server) handleDebugImport(w http.ResponseWriter, r *http.Request) {
+	if !s.config.EnableDebugEndpoints {
+		writeError(w, http.StatusForbidden, errDebugDisabled)
+		return
+	}
+
+	var snapshot scheduler.Snapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid snapshot: %w", err))
+		return
+	}
+
+	result, err := s.scheduler.ImportState(r.Context(), &snapshot)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.logger.Warn("Scheduler state imported via debug endpoint", "jobs", result.Jobs, "completed", result.Completed, "pods", result.Pods)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// resetJobs deletes the job sorted set and every key matching resetPatterns
+func (s *Server) resetJobs(ctx context.Context) (int, error) {
+	client := s.redisClient.GetClient()
+
+	deleted, err := client.Del(ctx, command.JobsSortedSetKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job set: %w", err)
+	}
+
+	total := int(deleted)
+	for _, pattern := range resetPatterns {
+		n, err := deleteMatching(ctx, client, pattern)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// deleteMatching removes all keys matching pattern using SCAN to avoid blocking Redis
+func deleteMatching(ctx context.Context, client *redis.Client, pattern string) (int, error) {
+	deleted := 0
+	iter := client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := client.Del(ctx, iter.Val()).Err(); err != nil {
+			return deleted, fmt.Errorf("failed to delete key %s: %w", iter.Val(), err)
+		}
+		deleted++
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, fmt.Errorf("failed to scan keys %s: %w", pattern, err)
+	}
+	return deleted, nil
+}
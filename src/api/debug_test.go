@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+)
+
+// seedJobState stores a job with its lock and per-command state, returning the job
+func seedJobState(t *testing.T) *command.Job {
+	t.Helper()
+	job := command.NewJob("work", nil, time.Now())
+	if err := job.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+	testRedis.Set("schedulerx:job_lock:"+job.ID, testPodID)
+	testRedis.Set("schedulerx:last_failed_pod:work", testPodID)
+	return job
+}
+
+func TestDebugResetDisabledByDefault(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	job := seedJobState(t)
+
+	decode(t, serve(t, s, http.MethodPost, "/debug/reset", nil), http.StatusForbidden, nil)
+	for _, key := range []string{command.JobsSortedSetKey, "scheduler:job:" + job.ID, "schedulerx:job_lock:" + job.ID} {
+		if !testRedis.Exists(key) {
+			t.Fatalf("%s was deleted while debug endpoints are disabled", key)
+		}
+	}
+}
+
+func TestDebugResetClearsJobState(t *testing.T) {
+	config := testConfig()
+	config.EnableDebugEndpoints = true
+	s, _ := newTestServer(t, config)
+	job := seedJobState(t)
+
+	var result map[string]int
+	decode(t, serve(t, s, http.MethodPost, "/debug/reset", nil), http.StatusOK, &result)
+	if result["deleted"] != 4 {
+		t.Fatalf("reset deleted %d keys, want the job set, details, lock and last failed pod", result["deleted"])
+	}
+	for _, key := range []string{command.JobsSortedSetKey, "scheduler:job:" + job.ID, "schedulerx:job_lock:" + job.ID, "schedulerx:last_failed_pod:work"} {
+		if testRedis.Exists(key) {
+			t.Fatalf("%s survived the reset", key)
+		}
+	}
+	if !testRedis.Exists("schedulerx:pods") {
+		t.Fatal("reset removed the pod registry")
+	}
+}
+
+func TestDebugExportImportDisabledByDefault(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	decode(t, serve(t, s, http.MethodGet, "/debug/export", nil), http.StatusForbidden, nil)
+	decode(t, serve(t, s, http.MethodPost, "/debug/import", scheduler.Snapshot{}), http.StatusForbidden, nil)
+}
+
+func TestDebugExportImportRoundTrip(t *testing.T) {
+	config := testConfig()
+	config.EnableDebugEndpoints = true
+	source, _ := newTestServer(t, config)
+	job := seedJobState(t)
+
+	var snapshot scheduler.Snapshot
+	decode(t, serve(t, source, http.MethodGet, "/debug/export", nil), http.StatusOK, &snapshot)
+
+	// Import into a fresh Redis, the pod registry on the shared test Redis keeps this pod the leader
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	client := cache.NewClientFromRedis(rdb)
+	target := NewServer(client, testLogger(), config, testPodManager, scheduler.NewScheduler(client, testLogger(), config))
+
+	var result scheduler.ImportResult
+	decode(t, serve(t, target, http.MethodPost, "/debug/import", snapshot), http.StatusOK, &result)
+	if result.Jobs != 1 || result.Pods != 1 {
+		t.Fatalf("imported %+v, want 1 job and 1 pod", result)
+	}
+
+	ctx := context.Background()
+	if ids, _ := rdb.ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result(); len(ids) != 1 || ids[0] != job.ID {
+		t.Fatalf("job set after import is %v, want %s", ids, job.ID)
+	}
+	var restored command.Job
+	if err := client.GetJSON(ctx, fmt.Sprintf(command.JobDetailsKey, job.ID), &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Status != job.Status || !restored.ScheduledAt.Equal(job.ScheduledAt) {
+		t.Fatalf("job restored as %s at %s, want %s at %s", restored.Status, restored.ScheduledAt, job.Status, job.ScheduledAt)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// forwardedByHeader marks requests forwarded to the leader, they are never forwarded again.
+// Anyone can set it, so it only stops forwarding loops and never lets a follower act as leader.
+const forwardedByHeader = "X-Schedulerx-Forwarded-By"
+
+// leaderOnly wraps an admin handler so it only runs on the leader. Followers
+// proxy the request to the leader's registered address instead of acting locally.
+func (s *Server) leaderOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info, err := s.podManager.LeaderInfo(r.Context())
+		if errors.Is(err, leader.ErrNoLeader) {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		podID := s.podManager.GetPodID()
+		if info.ID == podID {
+			next(w, r)
+			return
+		}
+
+		// Leadership moved while the request was forwarded, the client may retry
+		if r.Header.Get(forwardedByHeader) != "" {
+			writeError(w, http.StatusServiceUnavailable, fmt.Errorf("request forwarded to %s, which is not the leader", podID))
+			return
+		}
+
+		address, err := s.podManager.GetLeaderAddress(r.Context())
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		s.logger.Info("Forwarding request to leader", "path", r.URL.Path, "leader_id", info.ID, "address", address)
+		proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: address})
+		r.Header.Set(forwardedByHeader, podID)
+		proxy.ServeHTTP(w, r)
+	}
+}
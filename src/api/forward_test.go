@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// leaderOnlyRecorder returns a leader-only handler and a pointer reporting whether it ran locally.
+// The leader is elected as the oldest live pod, so tests give it an earlier start time.
+func leaderOnlyRecorder(s *Server) (http.HandlerFunc, *bool) {
+	ran := false
+	return s.leaderOnly(func(w http.ResponseWriter, r *http.Request) {
+		ran = true
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}), &ran
+}
+
+func TestLeaderOnlyForwardsFromFollower(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	var forwardedBy string
+	leaderServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwardedBy = r.Header.Get(forwardedByHeader)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "handled by leader"})
+	}))
+	defer leaderServer.Close()
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID},
+		leader.PodInfo{ID: "leader-pod", StartTime: time.Now().Add(-time.Hour), Address: strings.TrimPrefix(leaderServer.URL, "http://")},
+	)
+
+	handler, ran := leaderOnlyRecorder(s)
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodPost, "/debug/reset", nil))
+
+	if *ran {
+		t.Fatal("follower handled a leader-only request itself")
+	}
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "handled by leader") {
+		t.Fatalf("forwarded request answered %d: %s", recorder.Code, recorder.Body)
+	}
+	if forwardedBy != testPodID {
+		t.Fatalf("leader saw %s %q, want %q", forwardedByHeader, forwardedBy, testPodID)
+	}
+}
+
+func TestLeaderOnlyDoesNotTrustForwardedHeader(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	setTestPods(t, leader.PodInfo{ID: testPodID}, leader.PodInfo{ID: "leader-pod", StartTime: time.Now().Add(-time.Hour), Address: "127.0.0.1:1"})
+
+	handler, ran := leaderOnlyRecorder(s)
+	request := httptest.NewRequest(http.MethodPost, "/debug/reset", nil)
+	request.Header.Set(forwardedByHeader, "attacker")
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if *ran {
+		t.Fatal("follower ran a leader-only request carrying a forged forwarded header")
+	}
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("forged forwarded request answered %d, want 503", recorder.Code)
+	}
+}
+
+func TestLeaderOnlyRunsOnLeader(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+
+	handler, ran := leaderOnlyRecorder(s)
+	request := httptest.NewRequest(http.MethodPost, "/debug/reset", nil)
+	request.Header.Set(forwardedByHeader, "follower-pod")
+	handler(httptest.NewRecorder(), request)
+
+	if !*ran {
+		t.Fatal("leader did not handle a request forwarded to it")
+	}
+}
+
+func TestAdminRoutesForwardFromFollower(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	var forwarded []string
+	leaderServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = append(forwarded, r.Method+" "+r.URL.Path)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "handled by leader"})
+	}))
+	defer leaderServer.Close()
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID},
+		leader.PodInfo{ID: "leader-pod", StartTime: time.Now().Add(-time.Hour), Address: strings.TrimPrefix(leaderServer.URL, "http://")},
+	)
+
+	routes := []string{
+		"/pods/leader-pod/pause",
+		"/pods/leader-pod/resume",
+		"/commands/echo/trigger",
+		"/commands/echo/cancel",
+		"/jobs",
+	}
+	for _, path := range routes {
+		recorder := serve(t, s, http.MethodPost, path, map[string]string{"command": "echo"})
+		if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "handled by leader") {
+			t.Errorf("POST %s answered %d on a follower: %s", path, recorder.Code, recorder.Body)
+		}
+	}
+	if len(forwarded) != len(routes) {
+		t.Fatalf("leader received %q, want every admin request forwarded", forwarded)
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+)
+
+// EnqueueRequest is the body of POST /jobs
+type EnqueueRequest struct {
+	Command      string   `json:"command"`
+	Params       []string `json:"params"`       // Merged over the command's defaults, omit to use them as-is
+	DelaySeconds int64    `json:"delaySeconds"` // Run the job this long from now, 0 runs it right away
+}
+
+// handleEnqueueJob creates an ad-hoc job, due now or after delaySeconds
+func (s *Server) handleEnqueueJob(w http.ResponseWriter, r *http.Request) {
+	var req EnqueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.DelaySeconds < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("delaySeconds must not be negative"))
+		return
+	}
+
+	job, err := s.scheduler.EnqueueAfter(r.Context(), req.Command, req.Params, time.Duration(req.DelaySeconds)*time.Second)
+	writeEnqueued(w, job, err)
+}
+
+// writeEnqueued responds with a job just enqueued, or with the status matching the error enqueuing it
+func writeEnqueued(w http.ResponseWriter, job *command.Job, err error) {
+	switch {
+	case errors.Is(err, scheduler.ErrInvalidJob):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, scheduler.ErrJobExists):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, scheduler.ErrQueueFull):
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusCreated, job)
+	}
+}
+
+// handleListJobs lists pending jobs, optionally filtered by ?label=key:value (repeatable) and ?status=
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseLabelSelector(r.URL.Query()["label"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	status := command.JobStatus(r.URL.Query().Get("status"))
+
+	jobs, err := s.loadPendingJobs(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	matched := make([]*command.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if status != "" && job.Status != status {
+			continue
+		}
+		if !job.MatchesLabels(selector) {
+			continue
+		}
+		matched = append(matched, job)
+	}
+	writeJSON(w, http.StatusOK, matched)
+}
+
+// handleGetJob returns the stored details of a single job
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := command.ValidateJobID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var job command.Job
+	data, err := s.redisClient.GetClient().Get(r.Context(), fmt.Sprintf(command.JobDetailsKey, id)).Bytes()
+	if err == redis.Nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", id))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to get job %s: %w", id, err))
+		return
+	}
+	if err := json.Unmarshal(data, &job); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to unmarshal job %s: %w", id, err))
+		return
+	}
+	// With SEPARATE_JOB_OUTPUT the details of finished jobs carry no output, load it on demand
+	if job.Output == "" && job.Status.IsTerminal() {
+		output, err := command.GetJobOutput(r.Context(), s.redisClient.GetClient(), id)
+		if err != nil && err != redis.Nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		job.Output = output
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// JobOutputResponse is the body of GET /jobs/{id}/output
+type JobOutputResponse struct {
+	ID     string `json:"id"`
+	Output string `json:"output"`
+}
+
+// handleGetJobOutput returns only the output of a job, wherever it is stored
+func (s *Server) handleGetJobOutput(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := command.ValidateJobID(id); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	output, err := command.GetJobOutput(r.Context(), s.redisClient.GetClient(), id)
+	if err == redis.Nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s not found", id))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, JobOutputResponse{ID: id, Output: output})
+}
+
+// loadPendingJobs returns the details of every job in the sorted set, ordered by scheduled time.
+// Members whose details have expired or cannot be decoded are skipped.
+func (s *Server) loadPendingJobs(ctx context.Context) ([]*command.Job, error) {
+	client := s.redisClient.GetClient()
+
+	ids, err := client.ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	jobs := make([]*command.Job, 0, len(ids))
+	for _, id := range ids {
+		data, err := client.Get(ctx, fmt.Sprintf(command.JobDetailsKey, id)).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", id, err)
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			s.logger.Warn("Skipping undecodable job", "job_id", id, "error", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// parseLabelSelector turns key:value pairs into a label selector
+func parseLabelSelector(pairs []string) (map[string]string, error) {
+	selector := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q, expected key:value", pair)
+		}
+		selector[key] = value
+	}
+	return selector, nil
+}
+
+// handleListCompletedJobs lists retained finished jobs, optionally within ?from= and ?to= (RFC 3339)
+func (s *Server) handleListCompletedJobs(w http.ResponseWriter, r *http.Request) {
+	var from, to time.Time
+	for name, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", name, err))
+			return
+		}
+		*target = parsed
+	}
+
+	jobs, err := s.scheduler.CompletedJobs(r.Context(), from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jobs)
+}
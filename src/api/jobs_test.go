@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// storeLabeledJob stores a pending "work" job carrying labels
+func storeLabeledJob(t *testing.T, scheduledAt time.Time, labels map[string]string) *command.Job {
+	t.Helper()
+	job := command.NewJob("work", nil, scheduledAt)
+	job.Labels = labels
+	if err := job.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestListJobsFiltersByLabels(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	now := time.Now()
+	payments := storeLabeledJob(t, now, map[string]string{"team": "payments", "env": "prod"})
+	staging := storeLabeledJob(t, now.Add(time.Second), map[string]string{"team": "payments", "env": "staging"})
+	storeLabeledJob(t, now.Add(2*time.Second), map[string]string{"team": "search", "env": "prod"})
+	storeLabeledJob(t, now.Add(3*time.Second), nil)
+
+	var all []command.Job
+	decode(t, serve(t, s, http.MethodGet, "/jobs", nil), http.StatusOK, &all)
+	if len(all) != 4 {
+		t.Fatalf("GET /jobs returned %d jobs, want all 4", len(all))
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"?label=team:payments", []string{payments.ID, staging.ID}},
+		{"?label=team:payments&label=env:prod", []string{payments.ID}},
+		{"?label=team:billing", []string{}},
+	}
+	for _, tt := range tests {
+		var jobs []command.Job
+		decode(t, serve(t, s, http.MethodGet, "/jobs"+tt.query, nil), http.StatusOK, &jobs)
+		ids := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Fatalf("GET /jobs%s = %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+
+	decode(t, serve(t, s, http.MethodGet, "/jobs?label=team", nil), http.StatusBadRequest, nil)
+}
+
+func TestGetJobReturnsLabels(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	stored := storeLabeledJob(t, time.Now(), map[string]string{"team": "payments"})
+
+	var job command.Job
+	decode(t, serve(t, s, http.MethodGet, "/jobs/"+stored.ID, nil), http.StatusOK, &job)
+	if job.Labels["team"] != "payments" {
+		t.Fatalf("GET /jobs/%s labels = %v, want team:payments", stored.ID, job.Labels)
+	}
+}
+
+func TestGetJobReturnsAttempts(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	stored := command.NewJob("work", nil, time.Now())
+	stored.AssignedTo = testPodID
+	stored.Start()
+	stored.Fail(errors.New("timed out"))
+	if err := stored.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+
+	var job command.Job
+	decode(t, serve(t, s, http.MethodGet, "/jobs/"+stored.ID, nil), http.StatusOK, &job)
+	if len(job.Attempts) != 1 || job.Attempts[0].PodID != testPodID || job.Attempts[0].Error != "timed out" {
+		t.Fatalf("GET /jobs/%s attempts = %+v, want the failed attempt on %s", stored.ID, job.Attempts, testPodID)
+	}
+}
+
+func TestEnqueueJobRejectedWhenQueueIsFull(t *testing.T) {
+	config := testConfig()
+	config.MaxQueuedJobs = 1
+	s, sched := newTestServer(t, config)
+	sched.RegisterCommand(command.NewFuncCommand("work", "test command", "", nil))
+
+	decode(t, serve(t, s, http.MethodPost, "/jobs", EnqueueRequest{Command: "work", DelaySeconds: 60}), http.StatusCreated, nil)
+	decode(t, serve(t, s, http.MethodPost, "/jobs", EnqueueRequest{Command: "work", DelaySeconds: 120}), http.StatusServiceUnavailable, nil)
+}
+
+func TestEnqueueJobWithDelay(t *testing.T) {
+	s, sched := newTestServer(t, testConfig())
+	sched.RegisterCommand(command.NewFuncCommand("work", "test command", "", nil))
+
+	before := time.Now().Truncate(time.Millisecond)
+	var job command.Job
+	decode(t, serve(t, s, http.MethodPost, "/jobs", EnqueueRequest{Command: "work", DelaySeconds: 600}), http.StatusCreated, &job)
+	if want := before.Add(10 * time.Minute); job.ScheduledAt.Before(want) || job.ScheduledAt.After(want.Add(5*time.Second)) {
+		t.Fatalf("job scheduled at %s, want 10 minutes from now", job.ScheduledAt)
+	}
+	if score, err := testClient.GetClient().ZScore(context.Background(), command.JobsSortedSetKey, job.ID).Result(); err != nil || score != command.JobScore(job.ScheduledAt) {
+		t.Fatalf("job stored with score %v, %v, want its scheduled time", score, err)
+	}
+
+	decode(t, serve(t, s, http.MethodPost, "/jobs", EnqueueRequest{Command: "work", DelaySeconds: -1}), http.StatusBadRequest, nil)
+}
+
+func TestGetJobOutputStoredSeparately(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	stored := command.NewJob("work", nil, time.Now())
+	stored.Start()
+	stored.Output = "separate output"
+	stored.Complete()
+	if err := stored.UpdateInRedisSeparateOutput(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+
+	var output JobOutputResponse
+	decode(t, serve(t, s, http.MethodGet, "/jobs/"+stored.ID+"/output", nil), http.StatusOK, &output)
+	if output.ID != stored.ID || output.Output != "separate output" {
+		t.Fatalf("GET /jobs/%s/output = %+v, want the separately stored output", stored.ID, output)
+	}
+	var job command.Job
+	decode(t, serve(t, s, http.MethodGet, "/jobs/"+stored.ID, nil), http.StatusOK, &job)
+	if job.Output != "separate output" {
+		t.Fatalf("GET /jobs/%s output = %q, want it loaded from its own key", stored.ID, job.Output)
+	}
+	decode(t, serve(t, s, http.MethodGet, "/jobs/work_1/output", nil), http.StatusNotFound, nil)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+)
+
+// testPodID is the ID the pod manager of the test binary registers with
+const testPodID = "test-pod"
+
+var (
+	testRedis      *miniredis.Miniredis
+	testClient     *cache.Client
+	testPodManager *leader.PodManager
+)
+
+// TestMain starts an in-memory Redis and registers the process-wide pod manager,
+// whose heartbeat is stopped right away so tests control the pod registry
+func TestMain(m *testing.M) {
+	testRedis = miniredis.NewMiniRedis()
+	if err := testRedis.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start miniredis:", err)
+		os.Exit(1)
+	}
+	testClient = cache.NewClientFromRedis(redis.NewClient(&redis.Options{Addr: testRedis.Addr()}))
+
+	config := testConfig()
+	config.PodID = testPodID
+	testPodManager = leader.NewPodManager(testClient, testLogger(), config)
+	testPodManager.SetOutput(io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := testPodManager.Initialize(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize pod manager:", err)
+		os.Exit(1)
+	}
+	cancel()
+
+	code := m.Run()
+	testRedis.Close()
+	os.Exit(code)
+}
+
+// testConfig returns the config with every variable at its default
+func testConfig() *utils.Config {
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// testLogger returns a logger discarding everything
+func testLogger() *utils.StandardLogger {
+	return &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}
+}
+
+// newTestServer empties Redis and returns a server on a fresh scheduler, with the test
+// pod registered as the only pod and leader
+func newTestServer(t *testing.T, config *utils.Config) (*Server, *scheduler.Scheduler) {
+	t.Helper()
+	testRedis.FlushAll()
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true})
+	sched := scheduler.NewScheduler(testClient, testLogger(), config)
+	return NewServer(testClient, testLogger(), config, testPodManager, sched), sched
+}
+
+// setTestPods replaces the pod registry, pods are stamped as just seen
+func setTestPods(t *testing.T, pods ...leader.PodInfo) {
+	t.Helper()
+	registry := make(map[string]leader.PodInfo, len(pods))
+	for _, pod := range pods {
+		if pod.StartTime.IsZero() {
+			pod.StartTime = time.Now()
+		}
+		if pod.LastSeen.IsZero() {
+			pod.LastSeen = time.Now()
+		}
+		registry[pod.ID] = pod
+	}
+	if err := testClient.SetJSON(context.Background(), "schedulerx:pods", registry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve sends a request to the server, with body encoded as JSON unless it is nil
+func serve(t *testing.T, s *Server, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest(method, path, reader))
+	return recorder
+}
+
+// decode decodes the JSON body of a response into v, failing the test unless it has the wanted status
+func decode(t *testing.T, recorder *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if recorder.Code != status {
+		t.Fatalf("status %d, want %d: %s", recorder.Code, status, recorder.Body)
+	}
+	if v == nil {
+		return
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
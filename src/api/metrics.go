@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+// handleMetrics exposes the pod's metrics in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.WriteText(w)
+}
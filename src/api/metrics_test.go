@@ -0,0 +1,28 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+func TestMetricsExposeEnqueuedPerCommand(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	// The counter is process-wide, so expect 3 more than any earlier run left behind
+	enqueued := metrics.JobsEnqueued.Value("metrics-test") + 3
+	metrics.JobsEnqueued.Add("metrics-test", 3)
+
+	recorder := serve(t, s, http.MethodGet, "/metrics", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	body := recorder.Body.String()
+	for _, want := range []string{"# TYPE schedulerx_jobs_enqueued_total counter", fmt.Sprintf(`schedulerx_jobs_enqueued_total{command="metrics-test"} %g`, enqueued)} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("GET /metrics is missing %q:\n%s", want, body)
+		}
+	}
+}
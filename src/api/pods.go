@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// handleListPods returns all live pods with their status and leader flag
+func (s *Server) handleListPods(w http.ResponseWriter, r *http.Request) {
+	pods, err := s.podManager.ListPods(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pods)
+}
+
+// handleSetPodPaused pauses or resumes job execution on the pod in the path
+func (s *Server) handleSetPodPaused(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info, err := s.podManager.SetPodPaused(r.Context(), r.PathValue("id"), paused)
+		if errors.Is(err, leader.ErrPodNotFound) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	}
+}
+
+// LeaderResponse is returned by GET /leader
+type LeaderResponse struct {
+	LeaderID string `json:"leader_id"` // Pod currently elected leader
+	PodID    string `json:"pod_id"`    // Pod that served the request
+	IsLeader bool   `json:"is_leader"` // Whether the serving pod is the leader
+}
+
+// handleGetLeader returns the current leader and whether this pod is it
+func (s *Server) handleGetLeader(w http.ResponseWriter, r *http.Request) {
+	leaderID, err := s.podManager.GetLeader(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if leaderID == "" {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no live pods, leader unknown"))
+		return
+	}
+
+	podID := s.podManager.GetPodID()
+	writeJSON(w, http.StatusOK, LeaderResponse{
+		LeaderID: leaderID,
+		PodID:    podID,
+		IsLeader: leaderID == podID,
+	})
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestListPodsFlagsElectedLeader(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	now := time.Now()
+	// The stored leader flag is outdated, the oldest live pod is the leader
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, IsLeader: true, StartTime: now.Add(-time.Minute)},
+		leader.PodInfo{ID: "worker-pod", StartTime: now.Add(-time.Hour)},
+		leader.PodInfo{ID: "gone-pod", StartTime: now.Add(-2 * time.Hour), LastSeen: now.Add(-time.Hour)},
+	)
+
+	var pods []leader.PodInfo
+	decode(t, serve(t, s, http.MethodGet, "/pods", nil), http.StatusOK, &pods)
+	if len(pods) != 2 || pods[0].ID != "worker-pod" || pods[1].ID != testPodID {
+		t.Fatalf("GET /pods = %+v, want worker-pod and %s without the stale pod", pods, testPodID)
+	}
+	if !pods[0].IsLeader || pods[1].IsLeader {
+		t.Fatalf("leader flags %v and %v, want only worker-pod as the oldest live pod", pods[0].IsLeader, pods[1].IsLeader)
+	}
+	if pods[0].StartTime.IsZero() || pods[0].LastSeen.IsZero() {
+		t.Fatalf("pod %+v is missing its start time or last seen", pods[0])
+	}
+
+	var current LeaderResponse
+	decode(t, serve(t, s, http.MethodGet, "/leader", nil), http.StatusOK, &current)
+	if current.LeaderID != "worker-pod" || current.PodID != testPodID || current.IsLeader {
+		t.Fatalf("GET /leader = %+v, want worker-pod served by the follower %s", current, testPodID)
+	}
+}
+
+func TestListPodsReportsStatus(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, IsLeader: true, Status: "active"},
+		leader.PodInfo{ID: "paused-pod", Status: "active", Paused: true},
+		leader.PodInfo{ID: "unhealthy-pod", Status: "active", Unhealthy: true},
+	)
+
+	var pods []leader.PodInfo
+	decode(t, serve(t, s, http.MethodGet, "/pods", nil), http.StatusOK, &pods)
+	status := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		status[pod.ID] = pod.Status
+	}
+	want := map[string]string{testPodID: "active", "paused-pod": "paused", "unhealthy-pod": "unhealthy"}
+	for id, wantStatus := range want {
+		if status[id] != wantStatus {
+			t.Fatalf("pod statuses %v, want %v", status, want)
+		}
+	}
+}
+
+func TestListPodsReportsClusterDrain(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	testRedis.Set(leader.ClusterDrainKey, time.Now().Format(time.RFC3339))
+
+	var pods []leader.PodInfo
+	decode(t, serve(t, s, http.MethodGet, "/pods", nil), http.StatusOK, &pods)
+	if len(pods) != 1 || pods[0].Status != "draining" {
+		t.Fatalf("GET /pods = %+v, want %s draining", pods, testPodID)
+	}
+}
+
+func TestGetLeader(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	now := time.Now()
+
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, StartTime: now.Add(-time.Hour)},
+		leader.PodInfo{ID: "worker-pod", StartTime: now.Add(-time.Minute)},
+	)
+	var current LeaderResponse
+	decode(t, serve(t, s, http.MethodGet, "/leader", nil), http.StatusOK, &current)
+	if current.LeaderID != testPodID || current.PodID != testPodID || !current.IsLeader {
+		t.Fatalf("GET /leader = %+v, want %s reporting itself as leader", current, testPodID)
+	}
+
+	setTestPods(t, leader.PodInfo{ID: testPodID, LastSeen: now.Add(-time.Hour)})
+	if rec := serve(t, s, http.MethodGet, "/leader", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("GET /leader without live pods = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestPauseAndResumePod(t *testing.T) {
+	s, _ := newTestServer(t, testConfig())
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "worker-pod"})
+
+	var info leader.PodInfo
+	decode(t, serve(t, s, http.MethodPost, "/pods/worker-pod/pause", nil), http.StatusOK, &info)
+	if !info.Paused {
+		t.Fatalf("POST /pods/worker-pod/pause = %+v, want it paused", info)
+	}
+	var pods []leader.PodInfo
+	decode(t, serve(t, s, http.MethodGet, "/pods", nil), http.StatusOK, &pods)
+	for _, pod := range pods {
+		if pod.Paused != (pod.ID == "worker-pod") {
+			t.Fatalf("pod %s listed with paused %v, want only worker-pod paused", pod.ID, pod.Paused)
+		}
+	}
+
+	info = leader.PodInfo{}
+	decode(t, serve(t, s, http.MethodPost, "/pods/worker-pod/resume", nil), http.StatusOK, &info)
+	if info.Paused {
+		t.Fatalf("POST /pods/worker-pod/resume = %+v, want it resumed", info)
+	}
+	decode(t, serve(t, s, http.MethodPost, "/pods/missing-pod/pause", nil), http.StatusNotFound, nil)
+}
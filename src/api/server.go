@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take once the server stops
+const shutdownTimeout = 5 * time.Second
+
+// Server exposes the scheduler over HTTP
+type Server struct {
+	redisClient *cache.Client
+	logger      *utils.StandardLogger
+	config      *utils.Config
+	podManager  *leader.PodManager
+	scheduler   *scheduler.Scheduler
+	mux         *http.ServeMux
+}
+
+// NewServer creates a new API server with all routes registered
+func NewServer(redisClient *cache.Client, logger *utils.StandardLogger, config *utils.Config, podManager *leader.PodManager, sched *scheduler.Scheduler) *Server {
+	s := &Server{
+		redisClient: redisClient,
+		logger:      logger,
+		config:      config,
+		podManager:  podManager,
+		scheduler:   sched,
+		mux:         http.NewServeMux(),
+	}
+	s.registerRoutes()
+	return s
+}
+
+// registerRoutes registers all supported endpoints
+func (s *Server) registerRoutes() {
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.mux.HandleFunc("GET /pods", s.handleListPods)
+	s.mux.HandleFunc("POST /pods/{id}/pause", s.leaderOnly(s.handleSetPodPaused(true)))
+	s.mux.HandleFunc("POST /pods/{id}/resume", s.leaderOnly(s.handleSetPodPaused(false)))
+	s.mux.HandleFunc("GET /leader", s.handleGetLeader)
+	s.mux.HandleFunc("GET /commands", s.handleListCommands)
+	s.mux.HandleFunc("GET /commands/{id}/schema", s.handleGetCommandSchema)
+	s.mux.HandleFunc("POST /commands/{id}/trigger", s.leaderOnly(s.handleTriggerCommand))
+	s.mux.HandleFunc("POST /commands/{id}/cancel", s.leaderOnly(s.handleCancelCommandJobs))
+	s.mux.HandleFunc("GET /jobs", s.handleListJobs)
+	s.mux.HandleFunc("POST /jobs", s.leaderOnly(s.handleEnqueueJob))
+	s.mux.HandleFunc("GET /jobs/completed", s.handleListCompletedJobs)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleGetJob)
+	s.mux.HandleFunc("GET /jobs/{id}/output", s.handleGetJobOutput)
+	s.mux.HandleFunc("GET /dashboard", s.handleDashboard)
+	s.mux.HandleFunc("GET /status/report", s.handleStatusReport)
+	s.mux.HandleFunc("GET /assignments/simulate", s.handleSimulateAssignment)
+	s.mux.HandleFunc("POST /debug/reset", s.leaderOnly(s.handleDebugReset))
+	s.mux.HandleFunc("GET /debug/export", s.handleDebugExport)
+	s.mux.HandleFunc("POST /debug/import", s.leaderOnly(s.handleDebugImport))
+}
+
+// Handler returns the HTTP handler serving all routes
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves the API on the configured port until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.config.HTTPPort),
+		Handler: s.mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error("Failed to shut down API server", "error", err)
+		}
+	}()
+
+	s.logger.Info("API server listening", "addr", server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve API: %w", err)
+	}
+	return nil
+}
+
+// handleHealth reports that the process is alive
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether the pod can reach Redis and its heartbeats are being recorded
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.redisClient.Healthy() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "redis unavailable"})
+		return
+	}
+	if !s.podManager.IsConnected() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "heartbeats failing"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error message as a JSON response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
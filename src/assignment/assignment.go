@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/yashkumarverma/schedulerx/src/command"
 	"github.com/yashkumarverma/schedulerx/src/utils"
 	"github.com/yashkumarverma/schedulerx/src/utils/cache"
@@ -15,18 +18,30 @@ type Manager struct {
 	redisClient *cache.Client
 	logger      *utils.StandardLogger
 	config      *utils.Config
+	strategy    AssignmentStrategy
 }
 
-// NewManager creates a new assignment manager
+// NewManager creates a new assignment manager using the strategy named by ASSIGNMENT_STRATEGY
 func NewManager(redisClient *cache.Client, logger *utils.StandardLogger, config *utils.Config) *Manager {
+	strategy, err := StrategyByName(config.AssignmentStrategy)
+	if err != nil {
+		logger.Warn("Invalid assignment strategy", "error", err)
+	}
+
 	return &Manager{
 		redisClient: redisClient,
 		logger:      logger,
 		config:      config,
+		strategy:    strategy,
 	}
 }
 
-// AssignJobs assigns unassigned jobs to available pods in a round-robin fashion
+// SetStrategy replaces the strategy used to assign jobs
+func (m *Manager) SetStrategy(strategy AssignmentStrategy) {
+	m.strategy = strategy
+}
+
+// AssignJobs assigns unassigned jobs to available pods as decided by the configured strategy
 func (m *Manager) AssignJobs(ctx context.Context, pods []string) error {
 	if len(pods) == 0 {
 		return fmt.Errorf("no pods available for job assignment")
@@ -38,17 +53,20 @@ func (m *Manager) AssignJobs(ctx context.Context, pods []string) error {
 		jobCount = 3 // Default value if not set
 	}
 
-	// Get unassigned jobs from Redis sorted set
-	jobs, err := m.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, int64(jobCount)-1).Result()
+	// Get due and near-due jobs from Redis sorted set, far-future jobs wait for a later round
+	horizon := time.Now().Add(m.config.AssignmentHorizon)
+	jobs, err := m.redisClient.GetClient().ZRangeByScore(ctx, command.JobsSortedSetKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(horizon.UnixMilli(), 10),
+		Count: int64(jobCount),
+	}).Result()
 	if err != nil {
 		return fmt.Errorf("failed to fetch jobs: %w", err)
 	}
 
-	// Round-robin assignment
-	for i, jobID := range jobs {
-		podIndex := i % len(pods)
-		podID := pods[podIndex]
-
+	// Collect jobs that still need a pod
+	pending := make([]*command.Job, 0, len(jobs))
+	for _, jobID := range jobs {
 		// Get job details
 		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
 		jobData, err := m.redisClient.GetClient().Get(ctx, jobKey).Bytes()
@@ -58,6 +76,10 @@ func (m *Manager) AssignJobs(ctx context.Context, pods []string) error {
 
 		var job command.Job
 		if err := json.Unmarshal(jobData, &job); err != nil {
+			m.logger.Error("Discarding undecodable job", "job_id", jobID, "quarantined", m.config.QuarantineCorruptJobs, "error", err)
+			if err := command.DiscardCorruptJob(ctx, m.redisClient.GetClient(), jobID, jobData, m.config.QuarantineCorruptJobs); err != nil {
+				m.logger.Error("Failed to discard undecodable job", "job_id", jobID, "error", err)
+			}
 			continue
 		}
 
@@ -65,6 +87,19 @@ func (m *Manager) AssignJobs(ctx context.Context, pods []string) error {
 		if job.AssignedTo != "" || job.Status == command.Running {
 			continue
 		}
+		pending = append(pending, &job)
+	}
+
+	assignments, err := m.strategy.Assign(ctx, pending, pods)
+	if err != nil {
+		return fmt.Errorf("failed to compute assignments: %w", err)
+	}
+
+	for _, job := range pending {
+		podID, ok := assignments[job.ID]
+		if !ok {
+			continue
+		}
 
 		// Update job with pod assignment
 		job.AssignedTo = podID
@@ -72,11 +107,11 @@ func (m *Manager) AssignJobs(ctx context.Context, pods []string) error {
 
 		// Store updated job in Redis
 		if err := job.StoreInRedis(ctx, m.redisClient.GetClient()); err != nil {
-			m.logger.Error("Failed to update job assignment", "job_id", job.ID, "pod_id", podID, "error", err)
+			job.Logger(m.logger).Error("Failed to update job assignment", "error", err)
 			continue
 		}
 
-		m.logger.Info("Assigned job to pod", "job_id", job.ID, "pod_id", podID)
+		job.Logger(m.logger).Info("Assigned job to pod")
 	}
 
 	return nil
@@ -99,6 +134,10 @@ func (m *Manager) UnassignJobsFromPod(ctx context.Context, podID string) error {
 
 		var job command.Job
 		if err := json.Unmarshal(jobData, &job); err != nil {
+			m.logger.Error("Discarding undecodable job", "job_id", jobID, "quarantined", m.config.QuarantineCorruptJobs, "error", err)
+			if err := command.DiscardCorruptJob(ctx, m.redisClient.GetClient(), jobID, jobData, m.config.QuarantineCorruptJobs); err != nil {
+				m.logger.Error("Failed to discard undecodable job", "job_id", jobID, "error", err)
+			}
 			continue
 		}
 
@@ -109,11 +148,11 @@ func (m *Manager) UnassignJobsFromPod(ctx context.Context, podID string) error {
 
 			// Store updated job in Redis
 			if err := job.StoreInRedis(ctx, m.redisClient.GetClient()); err != nil {
-				m.logger.Error("Failed to unassign job", "job_id", job.ID, "pod_id", podID, "error", err)
+				job.Logger(m.logger).Error("Failed to unassign job", "previous_pod_id", podID, "error", err)
 				continue
 			}
 
-			m.logger.Info("Unassigned job from pod", "job_id", job.ID, "pod_id", podID)
+			job.Logger(m.logger).Info("Unassigned job from pod", "previous_pod_id", podID)
 		}
 	}
 
@@ -0,0 +1,68 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+)
+
+// newTestManager returns a manager on a fresh in-memory Redis with every config variable at its default
+func newTestManager(t *testing.T) (*Manager, *redis.Client) {
+	t.Helper()
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	logger := &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}
+	return NewManager(cache.NewClientFromRedis(rdb), logger, config), rdb
+}
+
+// assignedPods stores jobs, assigns them across pods and returns the pod each job went to
+func assignedPods(t *testing.T, m *Manager, rdb *redis.Client, jobs []*command.Job, pods []string) []string {
+	t.Helper()
+	ctx := context.Background()
+	for _, job := range jobs {
+		if err := job.StoreInRedis(ctx, rdb); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.AssignJobs(ctx, pods); err != nil {
+		t.Fatal(err)
+	}
+
+	assigned := make([]string, len(jobs))
+	for i, job := range jobs {
+		var stored command.Job
+		if err := cache.NewClientFromRedis(rdb).GetJSON(ctx, fmt.Sprintf(command.JobDetailsKey, job.ID), &stored); err != nil {
+			t.Fatal(err)
+		}
+		assigned[i] = stored.AssignedTo
+	}
+	return assigned
+}
+
+func TestManagerDelegatesToStrategy(t *testing.T) {
+	pods := []string{"pod-a", "pod-b"}
+
+	m, rdb := newTestManager(t)
+	if got := assignedPods(t, m, rdb, testJobs(4), pods); !slices.Equal(got, []string{"pod-a", "pod-b", "pod-a", "pod-b"}) {
+		t.Fatalf("round-robin assigned %v, want the pods in turns", got)
+	}
+
+	m, rdb = newTestManager(t)
+	m.SetStrategy(firstPodStrategy{})
+	if got := assignedPods(t, m, rdb, testJobs(4), pods); !slices.Equal(got, []string{"pod-a", "pod-a", "pod-a", "pod-a"}) {
+		t.Fatalf("after swapping strategies assigned %v, want every job on pod-a", got)
+	}
+}
@@ -0,0 +1,80 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// RoundRobin is the name of the default assignment strategy
+const RoundRobin = "round-robin"
+
+// AssignmentStrategy decides which pod each job runs on
+type AssignmentStrategy interface {
+	// Assign returns the pod ID to assign each job to, keyed by job ID.
+	// Jobs missing from the result are left unassigned for a later round.
+	// A pod may be listed several times, healthier pods more often than degraded ones.
+	Assign(ctx context.Context, jobs []*command.Job, pods []string) (map[string]string, error)
+}
+
+// RoundRobinStrategy spreads jobs evenly across pods in order
+type RoundRobinStrategy struct{}
+
+// Assign assigns the i-th job to the i-th pod, wrapping around
+func (RoundRobinStrategy) Assign(ctx context.Context, jobs []*command.Job, pods []string) (map[string]string, error) {
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods available for job assignment")
+	}
+
+	assignments := make(map[string]string, len(jobs))
+	for i, job := range jobs {
+		assignments[job.ID] = pods[i%len(pods)]
+	}
+	return assignments, nil
+}
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]AssignmentStrategy{
+		RoundRobin: RoundRobinStrategy{},
+	}
+)
+
+// RegisterStrategy makes a custom strategy selectable by name through ASSIGNMENT_STRATEGY.
+// Registering an existing name replaces that strategy.
+func RegisterStrategy(name string, strategy AssignmentStrategy) error {
+	if name == "" {
+		return fmt.Errorf("strategy name must not be empty")
+	}
+	if strategy == nil {
+		return fmt.Errorf("strategy %s must not be nil", name)
+	}
+
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	strategies[name] = strategy
+	return nil
+}
+
+// GetStrategy returns the strategy registered under name
+func GetStrategy(name string) (AssignmentStrategy, bool) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	strategy, exists := strategies[name]
+	return strategy, exists
+}
+
+// StrategyByName returns the strategy registered under name, falling back to
+// round-robin with an error describing the unknown name
+func StrategyByName(name string) (AssignmentStrategy, error) {
+	if name == "" {
+		return RoundRobinStrategy{}, nil
+	}
+	strategy, exists := GetStrategy(name)
+	if !exists {
+		return RoundRobinStrategy{}, fmt.Errorf("unknown assignment strategy %q, using %s", name, RoundRobin)
+	}
+	return strategy, nil
+}
@@ -0,0 +1,87 @@
+package assignment
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// testJobs returns n jobs of the "work" command a second apart
+func testJobs(n int) []*command.Job {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	jobs := make([]*command.Job, n)
+	for i := range jobs {
+		jobs[i] = command.NewJob("work", nil, start.Add(time.Duration(i)*time.Second))
+	}
+	return jobs
+}
+
+// firstPodStrategy assigns every job to the first pod
+type firstPodStrategy struct{}
+
+func (firstPodStrategy) Assign(ctx context.Context, jobs []*command.Job, pods []string) (map[string]string, error) {
+	assignments := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		assignments[job.ID] = pods[0]
+	}
+	return assignments, nil
+}
+
+func TestRoundRobinAssignsInTurns(t *testing.T) {
+	jobs := testJobs(5)
+	assignments, err := RoundRobinStrategy{}.Assign(context.Background(), jobs, []string{"pod-a", "pod-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		jobs[0].ID: "pod-a", jobs[1].ID: "pod-b", jobs[2].ID: "pod-a", jobs[3].ID: "pod-b", jobs[4].ID: "pod-a",
+	}
+	if !maps.Equal(assignments, want) {
+		t.Fatalf("assignments = %v, want %v", assignments, want)
+	}
+
+	if _, err := (RoundRobinStrategy{}).Assign(context.Background(), jobs, nil); err == nil {
+		t.Fatal("assigning without pods succeeded")
+	}
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	if err := RegisterStrategy("first-pod", firstPodStrategy{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		strategiesMu.Lock()
+		delete(strategies, "first-pod")
+		strategiesMu.Unlock()
+	})
+
+	strategy, err := StrategyByName("first-pod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := strategy.(firstPodStrategy); !ok {
+		t.Fatalf("StrategyByName returned %T, want the registered strategy", strategy)
+	}
+
+	if err := RegisterStrategy("", firstPodStrategy{}); err == nil {
+		t.Fatal("registered a strategy without a name")
+	}
+	if err := RegisterStrategy("nil", nil); err == nil {
+		t.Fatal("registered a nil strategy")
+	}
+}
+
+func TestStrategyByNameFallsBackToRoundRobin(t *testing.T) {
+	for name, wantErr := range map[string]bool{"": false, RoundRobin: false, "missing": true} {
+		strategy, err := StrategyByName(name)
+		if (err != nil) != wantErr {
+			t.Fatalf("StrategyByName(%q) error = %v, want error %v", name, err, wantErr)
+		}
+		if _, ok := strategy.(RoundRobinStrategy); !ok {
+			t.Fatalf("StrategyByName(%q) = %T, want round-robin", name, strategy)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"os/exec"
+	"slices"
+)
+
+// CapabilityProvider is implemented by commands that can only run on pods with
+// certain capabilities. A capability is the name of a binary on the pod's PATH.
+type CapabilityProvider interface {
+	// RequiredCapabilities returns the capabilities a pod needs to run the command
+	RequiredCapabilities() []string
+}
+
+// RequiredCapabilities returns the capabilities cmd needs, from overrides keyed by
+// command ID (see CMD_REQUIRED_CAPABILITIES_<command id>) or else the command's own
+func RequiredCapabilities(cmd Command, overrides map[string][]string) []string {
+	if capabilities, ok := overrides[cmd.ID()]; ok {
+		return capabilities
+	}
+	if provider, ok := cmd.(CapabilityProvider); ok {
+		return provider.RequiredCapabilities()
+	}
+	return nil
+}
+
+// lookPathFunc finds a binary on the PATH, as exec.LookPath does, replaceable to probe without touching the PATH
+type lookPathFunc func(file string) (string, error)
+
+// ProbeCapabilities splits capabilities into the ones found on this pod's PATH and the missing ones, both sorted.
+// Found is never nil, so a pod without any of them is told apart from one that did not probe.
+func ProbeCapabilities(capabilities []string) (found, missing []string) {
+	return probeCapabilities(exec.LookPath, capabilities)
+}
+
+// probeCapabilities is ProbeCapabilities with the PATH lookup injected
+func probeCapabilities(lookPath lookPathFunc, capabilities []string) (found, missing []string) {
+	found = []string{}
+	for _, capability := range capabilities {
+		if slices.Contains(found, capability) || slices.Contains(missing, capability) {
+			continue
+		}
+		if _, err := lookPath(capability); err != nil {
+			missing = append(missing, capability)
+			continue
+		}
+		found = append(found, capability)
+	}
+	slices.Sort(found)
+	slices.Sort(missing)
+	return found, missing
+}
+
+// RequiredCapabilities returns the capabilities of the shell command
+func (c *ShellCommand) RequiredCapabilities() []string {
+	return []string{"sh"}
+}
+
+// RequiredCapabilities returns the capabilities of the ls command
+func (c *ListFilesCommand) RequiredCapabilities() []string {
+	return []string{"ls"}
+}
+
+// RequiredCapabilities returns the capabilities of the du command
+func (c *DiskUsageCommand) RequiredCapabilities() []string {
+	return []string{"du"}
+}
+
+// RequiredCapabilities returns the capabilities of the ping command
+func (c *PingCommand) RequiredCapabilities() []string {
+	return []string{"ping"}
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	onPath := []string{"du", "ls"}
+	lookPath := func(file string) (string, error) {
+		if slices.Contains(onPath, file) {
+			return "/bin/" + file, nil
+		}
+		return "", errors.New("executable file not found in $PATH")
+	}
+
+	found, missing := probeCapabilities(lookPath, []string{"ls", "ping", "du", "ls"})
+	if !slices.Equal(found, []string{"du", "ls"}) || !slices.Equal(missing, []string{"ping"}) {
+		t.Fatalf("probe found %q and missed %q, want du, ls found and ping missing", found, missing)
+	}
+	if found, _ := probeCapabilities(lookPath, []string{"ping"}); found == nil {
+		t.Fatal("probe without any capability found returned nil")
+	}
+}
+
+func TestRequiredCapabilities(t *testing.T) {
+	ping := NewPingCommand("localhost", 4, 1)
+	if got := RequiredCapabilities(ping, nil); !slices.Equal(got, []string{"ping"}) {
+		t.Fatalf("ping requires %q, want ping", got)
+	}
+	if got := RequiredCapabilities(ping, map[string][]string{"ping": {"ping6"}}); !slices.Equal(got, []string{"ping6"}) {
+		t.Fatalf("overridden ping requires %q, want ping6", got)
+	}
+	if got := RequiredCapabilities(NewEchoCommand("hi"), nil); got != nil {
+		t.Fatalf("echo requires %q, want nothing", got)
+	}
+}
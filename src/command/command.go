@@ -1,9 +1,15 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Command interface defines the methods that all commands must implement
@@ -14,12 +20,148 @@ type Command interface {
 	Description() string
 	// Execute runs the command with the given parameters
 	Execute(params []string) error
-	// Schedule returns the cron schedule and parameters for the command
+	// Schedule returns the cron schedule and parameters for the command.
+	// Several cron expressions may be separated by "|" and are combined.
+	// An empty schedule means the command only runs when triggered.
 	Schedule() (string, []string, error)
 	// Parameters returns the default parameters for the command
 	Parameters() []string
 }
 
+// OutputCommand is implemented by commands that can return their output instead of printing it
+type OutputCommand interface {
+	// ExecuteWithOutput runs the command with the given parameters and returns its combined output
+	ExecuteWithOutput(params []string) (string, error)
+}
+
+// ContextCommand is implemented by commands that can be cancelled through a context
+type ContextCommand interface {
+	// ExecuteContext runs the command with the given parameters until it finishes or ctx is done
+	ExecuteContext(ctx context.Context, params []string) (string, error)
+}
+
+// TimeoutProvider is implemented by commands that set their own execution timeout
+type TimeoutProvider interface {
+	// Timeout returns how long a run may take, 0 defers to DEFAULT_COMMAND_TIMEOUT_SECONDS
+	Timeout() time.Duration
+}
+
+// ParamTimeoutProvider is implemented by commands whose timeout depends on the job's
+// params, e.g. on a retry count. It takes precedence over TimeoutProvider.
+type ParamTimeoutProvider interface {
+	// TimeoutFor returns how long a run with the given params may take, 0 defers to DEFAULT_COMMAND_TIMEOUT_SECONDS
+	TimeoutFor(params []string) time.Duration
+}
+
+// CommandTimeout returns the timeout cmd sets for a run with params, 0 if it sets none
+func CommandTimeout(cmd Command, params []string) time.Duration {
+	if provider, ok := cmd.(ParamTimeoutProvider); ok {
+		return provider.TimeoutFor(params)
+	}
+	if provider, ok := cmd.(TimeoutProvider); ok {
+		return provider.Timeout()
+	}
+	return 0
+}
+
+// SuccessExitCodesProvider is implemented by commands whose process may exit
+// with codes other than 0 on success, e.g. grep exits 1 when nothing matches
+type SuccessExitCodesProvider interface {
+	// SuccessExitCodes returns the exit codes that mark a run as successful, including 0 if it is one
+	SuccessExitCodes() []int
+}
+
+// Validator is implemented by commands that can check their parameters before a job is created
+type Validator interface {
+	// Validate returns an error if the parameters cannot be used to run the command
+	Validate(params []string) error
+}
+
+// Chainer is implemented by commands that trigger follow-up commands when their jobs finish
+type Chainer interface {
+	// OnSuccess returns the IDs of commands to enqueue when a job of this command succeeds
+	OnSuccess() []string
+	// OnFailure returns the IDs of commands to enqueue when a job of this command fails
+	OnFailure() []string
+}
+
+// LeaderOnlyCommand is implemented by commands whose jobs must run on the leader, e.g. cluster-wide maintenance
+type LeaderOnlyCommand interface {
+	// LeaderOnly reports whether jobs of the command are only assigned to the leader
+	LeaderOnly() bool
+}
+
+// LabelProvider is implemented by commands whose jobs should carry labels
+type LabelProvider interface {
+	// Labels returns the labels attached to every job created for the command
+	Labels() map[string]string
+}
+
+// MergeLabels returns the command's labels with overrides applied on top, or nil when there are none
+func MergeLabels(cmd Command, overrides map[string]string) map[string]string {
+	var base map[string]string
+	if provider, ok := cmd.(LabelProvider); ok {
+		base = provider.Labels()
+	}
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		labels[key] = value
+	}
+	for key, value := range overrides {
+		labels[key] = value
+	}
+	return labels
+}
+
+// NormalizeParams returns params as a non-nil slice, so jobs without params
+// store [] rather than null and commands never have to check for nil
+func NormalizeParams(params []string) []string {
+	if params == nil {
+		return []string{}
+	}
+	return params
+}
+
+// MergeParams overlays overrides on top of defaults position by position.
+// Positions not covered by overrides keep their default value, so a shorter
+// override only replaces the leading parameters. Nil overrides mean "use the
+// command defaults" and return a copy of defaults.
+func MergeParams(defaults, overrides []string) []string {
+	size := len(defaults)
+	if len(overrides) > size {
+		size = len(overrides)
+	}
+	merged := make([]string, size)
+	copy(merged, defaults)
+	copy(merged, overrides)
+	return merged
+}
+
+// DefaultParamsOverrider is implemented by commands whose default parameters can be replaced through configuration
+type DefaultParamsOverrider interface {
+	// SetDefaultParams overlays params on the compiled-in defaults, see MergeParams
+	SetDefaultParams(params []string)
+}
+
+// overridableParams implements DefaultParamsOverrider for the built-in commands
+type overridableParams struct {
+	overrides []string
+}
+
+// SetDefaultParams overlays params on the compiled-in defaults, see MergeParams
+func (o *overridableParams) SetDefaultParams(params []string) {
+	o.overrides = params
+}
+
+// defaults returns the builtin parameters with the configured overrides applied
+func (o *overridableParams) defaults(builtin ...string) []string {
+	return MergeParams(builtin, o.overrides)
+}
+
 // CommandRegistry holds all available commands
 type CommandRegistry struct {
 	commands map[string]Command
@@ -35,32 +177,72 @@ func NewCommandRegistry() *CommandRegistry {
 
 // registerCommands registers all available commands
 func (r *CommandRegistry) registerCommands() {
-	// Register echo command
-	r.commands["echo"] = &EchoCommand{
-		message: "",
+	builtins := []Command{
+		// Register echo command
+		&EchoCommand{
+			message: "",
+		},
+		// Register shell command
+		&ShellCommand{
+			command: "",
+		},
+		// Register ls command
+		&ListFilesCommand{
+			directory: ".",
+		},
+		// Register du command
+		&DiskUsageCommand{
+			path: ".",
+		},
+		// Register ping command
+		&PingCommand{
+			host:     "localhost",
+			count:    4,
+			interval: 1.0,
+		},
 	}
 
-	// Register shell command
-	r.commands["shell"] = &ShellCommand{
-		command: "",
+	for _, cmd := range builtins {
+		if err := r.Register(cmd); err != nil {
+			panic(err)
+		}
 	}
+}
 
-	// Register ls command
-	r.commands["ls"] = &ListFilesCommand{
-		directory: ".",
+// Register adds a command to the registry, rejecting nil commands, unsafe IDs (see ValidateCommandID) and duplicates
+func (r *CommandRegistry) Register(cmd Command) error {
+	if cmd == nil {
+		return fmt.Errorf("command must not be nil")
 	}
-
-	// Register du command
-	r.commands["du"] = &DiskUsageCommand{
-		path: ".",
+	id := cmd.ID()
+	if err := ValidateCommandID(id); err != nil {
+		return err
 	}
+	if _, exists := r.commands[id]; exists {
+		return fmt.Errorf("command already registered: %s", id)
+	}
+	r.commands[id] = cmd
+	return nil
+}
+
+// Unregister removes a command from the registry, it is a no-op for unknown IDs
+func (r *CommandRegistry) Unregister(id string) {
+	delete(r.commands, id)
+}
 
-	// Register ping command
-	r.commands["ping"] = &PingCommand{
-		host:     "localhost",
-		count:    4,
-		interval: 1.0,
+// ApplyDefaultParams replaces the default parameters of registered commands, keyed by command ID.
+// It returns the IDs that are not registered or whose command does not support overrides.
+func (r *CommandRegistry) ApplyDefaultParams(overrides map[string][]string) []string {
+	var skipped []string
+	for id, params := range overrides {
+		overrider, ok := r.commands[id].(DefaultParamsOverrider)
+		if !ok {
+			skipped = append(skipped, id)
+			continue
+		}
+		overrider.SetDefaultParams(params)
 	}
+	return skipped
 }
 
 // GetCommand returns a command by its ID
@@ -78,6 +260,17 @@ func (r *CommandRegistry) GetCommandDescriptions() map[string]string {
 	return descriptions
 }
 
+// GetCommandSchemas returns the parameter schema of every command that declares one
+func (r *CommandRegistry) GetCommandSchemas() map[string][]ParamSpec {
+	schemas := make(map[string][]ParamSpec)
+	for id, cmd := range r.commands {
+		if schema, ok := ParamSchemaOf(cmd); ok {
+			schemas[id] = schema
+		}
+	}
+	return schemas
+}
+
 // GetCommands returns all registered commands
 func (r *CommandRegistry) GetCommands() map[string]Command {
 	return r.commands
@@ -85,6 +278,7 @@ func (r *CommandRegistry) GetCommands() map[string]Command {
 
 // EchoCommand implements a simple echo command
 type EchoCommand struct {
+	overridableParams
 	message string
 }
 
@@ -107,36 +301,61 @@ func (c *EchoCommand) Description() string {
 
 // Execute runs the echo command
 func (c *EchoCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	fmt.Print(output)
+	return err
+}
+
+// ExecuteWithOutput returns the echoed message
+func (c *EchoCommand) ExecuteWithOutput(params []string) (string, error) {
 	if len(params) > 0 {
-		fmt.Println(strings.Join(params, " "))
-	} else {
-		fmt.Println(c.message)
+		return strings.Join(params, " ") + "\n", nil
 	}
-	return nil
+	return c.message + "\n", nil
 }
 
 // Schedule returns the cron schedule and parameters for the command
 func (c *EchoCommand) Schedule() (string, []string, error) {
-	return "*/5 * * * * *", []string{"Heartbeat check"}, nil // Run every 5 seconds
+	return "*/5 * * * * *", c.Parameters(), nil // Run every 5 seconds
 }
 
 // Parameters returns the default parameters for the command
 func (c *EchoCommand) Parameters() []string {
-	return []string{"Heartbeat check"}
+	return c.defaults("Heartbeat check")
 }
 
+// DefaultShellTimeout caps how long a shell command may run unless configured otherwise
+const DefaultShellTimeout = 5 * time.Minute
+
+// shellWaitDelay bounds how long output is collected after a timed out process is killed,
+// in case children it spawned still hold the output pipes open
+const shellWaitDelay = time.Second
+
 // ShellCommand implements a shell command execution
 type ShellCommand struct {
+	overridableParams
 	command string
+	timeout time.Duration
 }
 
-// NewShellCommand creates a new ShellCommand
+// NewShellCommand creates a new ShellCommand with DefaultShellTimeout
 func NewShellCommand(command string) *ShellCommand {
+	return NewShellCommandWithTimeout(command, DefaultShellTimeout)
+}
+
+// NewShellCommandWithTimeout creates a new ShellCommand that is killed after timeout, 0 disables the timeout
+func NewShellCommandWithTimeout(command string, timeout time.Duration) *ShellCommand {
 	return &ShellCommand{
 		command: command,
+		timeout: timeout,
 	}
 }
 
+// Timeout returns how long the shell command may run, 0 when it has no timeout of its own
+func (c *ShellCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
 // ID returns the command identifier
 func (c *ShellCommand) ID() string {
 	return "shell"
@@ -149,27 +368,53 @@ func (c *ShellCommand) Description() string {
 
 // Execute runs the shell command
 func (c *ShellCommand) Execute(params []string) error {
-	cmd := exec.Command("sh", "-c", c.command)
-	output, err := cmd.CombinedOutput()
+	output, err := c.ExecuteWithOutput(params)
 	if err != nil {
-		return fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+		return err
 	}
-	fmt.Print(string(output))
+	fmt.Print(output)
 	return nil
 }
 
+// ExecuteWithOutput runs the shell command and returns its output
+func (c *ShellCommand) ExecuteWithOutput(params []string) (string, error) {
+	return c.ExecuteContext(context.Background(), params)
+}
+
+// ExecuteContext runs the shell command until it finishes, its timeout expires or ctx is done
+func (c *ShellCommand) ExecuteContext(ctx context.Context, params []string) (string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.command)
+	setProcessGroup(cmd)
+	cmd.WaitDelay = shellWaitDelay
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return string(output), fmt.Errorf("command timed out after %s\nOutput: %s", c.timeout, string(output))
+	}
+	if err != nil {
+		return string(output), fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
 // Schedule returns the cron schedule and parameters for the command
 func (c *ShellCommand) Schedule() (string, []string, error) {
-	return "0 */30 * * * *", []string{"df -h"}, nil // Run every 30 minutes
+	return "0 */30 * * * *", c.Parameters(), nil // Run every 30 minutes
 }
 
 // Parameters returns the default parameters for the command
 func (c *ShellCommand) Parameters() []string {
-	return []string{"df -h"}
+	return c.defaults("df -h")
 }
 
 // ListFilesCommand implements a directory listing command
 type ListFilesCommand struct {
+	overridableParams
 	directory string
 }
 
@@ -192,32 +437,43 @@ func (c *ListFilesCommand) Description() string {
 
 // Execute lists files in the specified directory
 func (c *ListFilesCommand) Execute(params []string) error {
-	dir := c.directory
-	if len(params) > 0 {
-		dir = params[0]
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput lists files in the specified directory and returns the listing
+func (c *ListFilesCommand) ExecuteWithOutput(params []string) (string, error) {
+	dir, err := RequiredParam(params, 0, "directory", c.directory)
+	if err != nil {
+		return "", err
 	}
 
 	cmd := exec.Command("ls", "-la", dir)
+	setProcessGroup(cmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to list files: %w\nOutput: %s", err, string(output))
+		return string(output), fmt.Errorf("failed to list files: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Print(string(output))
-	return nil
+	return string(output), nil
 }
 
 // Schedule returns the cron schedule and parameters for the command
 func (c *ListFilesCommand) Schedule() (string, []string, error) {
-	return "0 * * * * *", []string{"."}, nil // Run every minute
+	return "0 * * * * *", c.Parameters(), nil // Run every minute
 }
 
 // Parameters returns the default parameters for the command
 func (c *ListFilesCommand) Parameters() []string {
-	return []string{"."}
+	return c.defaults(".")
 }
 
 // DiskUsageCommand implements a disk usage command
 type DiskUsageCommand struct {
+	overridableParams
 	path string
 }
 
@@ -240,32 +496,43 @@ func (c *DiskUsageCommand) Description() string {
 
 // Execute shows disk usage for the specified path
 func (c *DiskUsageCommand) Execute(params []string) error {
-	path := c.path
-	if len(params) > 0 {
-		path = params[0]
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput returns the disk usage for the specified path
+func (c *DiskUsageCommand) ExecuteWithOutput(params []string) (string, error) {
+	path, err := RequiredParam(params, 0, "path", c.path)
+	if err != nil {
+		return "", err
 	}
 
 	cmd := exec.Command("du", "-sh", path)
+	setProcessGroup(cmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to get disk usage: %w\nOutput: %s", err, string(output))
+		return string(output), fmt.Errorf("failed to get disk usage: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Print(string(output))
-	return nil
+	return string(output), nil
 }
 
 // Schedule returns the cron schedule and parameters for the command
 func (c *DiskUsageCommand) Schedule() (string, []string, error) {
-	return "0 */5 * * * *", []string{"/"}, nil // Run every 5 minutes
+	return "0 */5 * * * *", c.Parameters(), nil // Run every 5 minutes
 }
 
 // Parameters returns the default parameters for the command
 func (c *DiskUsageCommand) Parameters() []string {
-	return []string{"/"}
+	return c.defaults("/")
 }
 
 // PingCommand implements a network ping command
 type PingCommand struct {
+	overridableParams
 	host     string
 	count    int
 	interval float64
@@ -292,32 +559,95 @@ func (c *PingCommand) Description() string {
 
 // Execute runs the ping command
 func (c *PingCommand) Execute(params []string) error {
-	host := c.host
-	if len(params) > 0 {
-		host = params[0]
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
 	}
+	fmt.Print(output)
+	return nil
+}
 
-	args := []string{
-		"-c", fmt.Sprintf("%d", c.count),
-		"-i", fmt.Sprintf("%f", c.interval),
-		host,
+// ExecuteWithOutput runs the ping command and returns its output, params are host, count and interval in seconds
+func (c *PingCommand) ExecuteWithOutput(params []string) (string, error) {
+	host, err := RequiredParam(params, 0, "host", c.host)
+	if err != nil {
+		return "", err
+	}
+	count, err := IntParam(params, 1, "count", strconv.Itoa(c.count))
+	if err != nil {
+		return "", err
 	}
+	interval, err := FloatParam(params, 2, "interval", strconv.FormatFloat(c.interval, 'g', -1, 64))
+	if err != nil {
+		return "", err
+	}
+
+	args := pingArgs(runtime.GOOS, os.Geteuid() == 0, host, count, interval)
 
 	cmd := exec.Command("ping", args...)
+	setProcessGroup(cmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("ping failed: %w\nOutput: %s", err, string(output))
+		// Every platform exits non-zero when no reply was received, report the code the same way everywhere
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return string(output), fmt.Errorf("ping failed with exit code %d: %w\nOutput: %s", exitErr.ExitCode(), err, string(output))
+		}
+		return string(output), fmt.Errorf("ping failed: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Print(string(output))
-	return nil
+	return string(output), nil
+}
+
+// minUnprivilegedPingInterval is the smallest interval in seconds ping accepts from non-root users.
+// Linux iputils refuses intervals below 0.2s, macOS and the BSDs refuse sub-second intervals.
+func minUnprivilegedPingInterval(goos string) float64 {
+	if goos == "linux" {
+		return 0.2
+	}
+	return 1
+}
+
+// pingArgs builds the ping arguments for goos. Intervals that an unprivileged
+// user may not request are raised to the smallest permitted one instead of
+// making ping fail, and the interval is formatted without trailing zeros.
+// Windows ping takes the count as -n and always waits a second between echo
+// requests, so the interval is not passed there.
+func pingArgs(goos string, privileged bool, host string, count int, interval float64) []string {
+	if goos == "windows" {
+		return []string{"-n", strconv.Itoa(count), host}
+	}
+
+	args := []string{"-c", strconv.Itoa(count)}
+	if interval > 0 {
+		if minimum := minUnprivilegedPingInterval(goos); !privileged && interval < minimum {
+			interval = minimum
+		}
+		args = append(args, "-i", strconv.FormatFloat(interval, 'g', -1, 64))
+	}
+	return append(args, host)
+}
+
+// ParamSchema returns the typed parameters of the ping command
+func (c *PingCommand) ParamSchema() []ParamSpec {
+	return []ParamSpec{
+		{Name: "host", Type: ParamString, Required: true},
+		{Name: "count", Type: ParamInt, Default: "4"},
+		{Name: "interval", Type: ParamFloat, Default: "1.0"},
+	}
+}
+
+// Validate checks the parameters against the ping command's schema
+func (c *PingCommand) Validate(params []string) error {
+	_, err := CoerceParams(c.ParamSchema(), params)
+	return err
 }
 
 // Schedule returns the cron schedule and parameters for the command
 func (c *PingCommand) Schedule() (string, []string, error) {
-	return "0 */10 * * * *", []string{"google.com", "4", "1.0"}, nil // Run every 10 minutes
+	return "0 */10 * * * *", c.Parameters(), nil // Run every 10 minutes
 }
 
 // Parameters returns the default parameters for the command
 func (c *PingCommand) Parameters() []string {
-	return []string{"google.com", "4", "1.0"}
+	return c.defaults("google.com", "4", "1.0")
 }
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePing puts a ping executable on PATH that prints its arguments and exits with exitCode
+func fakePing(t *testing.T, exitCode int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ping is a shell script")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\"\nexit " + strconv.Itoa(exitCode) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "ping"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPingFailureKeepsExitError(t *testing.T) {
+	fakePing(t, 2)
+
+	_, err := NewPingCommand("example.com", 1, 0).ExecuteWithOutput(nil)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("ExecuteWithOutput = %v, want an error wrapping *exec.ExitError", err)
+	}
+	if exitErr.ExitCode() != 2 {
+		t.Fatalf("exit code %d, want 2", exitErr.ExitCode())
+	}
+}
+
+func TestPingUsesCountAndIntervalParams(t *testing.T) {
+	fakePing(t, 0)
+
+	output, err := NewPingCommand("example.com", 4, 1).ExecuteWithOutput([]string{"example.org", "2", "0.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unprivileged runs raise the interval to the platform minimum, so only check it is passed
+	if !strings.HasPrefix(output, "-c 2 -i ") || !strings.HasSuffix(output, " example.org\n") {
+		t.Fatalf("ping ran with %q, want -c 2, an interval and example.org", output)
+	}
+
+	if _, err := NewPingCommand("example.com", 4, 1).ExecuteWithOutput([]string{"example.org", "two"}); err == nil {
+		t.Fatal("invalid count was accepted")
+	}
+}
+
+func TestCommandRegistryRegisterAndUnregister(t *testing.T) {
+	registry := NewCommandRegistry()
+	custom := NewFuncCommand("greet", "Greets", "", func(ctx context.Context, params []string) (string, error) {
+		return "hello", nil
+	})
+
+	if err := registry.Register(custom); err != nil {
+		t.Fatalf("registering a new command failed: %v", err)
+	}
+	if cmd, ok := registry.GetCommand("greet"); !ok || cmd != Command(custom) {
+		t.Fatal("registered command is not returned by its ID")
+	}
+	for _, duplicate := range []Command{custom, NewEchoCommand("again")} {
+		if err := registry.Register(duplicate); err == nil || !strings.Contains(err.Error(), "already registered") {
+			t.Fatalf("registering duplicate %s returned %v, want it rejected", duplicate.ID(), err)
+		}
+	}
+	if err := registry.Register(nil); err == nil {
+		t.Fatal("registering a nil command succeeded")
+	}
+
+	registry.Unregister("greet")
+	if _, ok := registry.GetCommand("greet"); ok {
+		t.Fatal("unregistered command is still returned")
+	}
+	registry.Unregister("greet")
+	if err := registry.Register(custom); err != nil {
+		t.Fatalf("registering again after unregistering failed: %v", err)
+	}
+}
+
+func TestShellCommandTimesOut(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell command runs sh")
+	}
+
+	start := time.Now()
+	_, err := NewShellCommandWithTimeout("sleep 10", 200*time.Millisecond).ExecuteWithOutput(nil)
+	if err == nil || !strings.Contains(err.Error(), "command timed out after 200ms") {
+		t.Fatalf("ExecuteWithOutput = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("timed out command returned after %s, want it killed", elapsed)
+	}
+
+	if output, err := NewShellCommandWithTimeout("echo done", time.Second).ExecuteWithOutput(nil); err != nil || output != "done\n" {
+		t.Fatalf("command within its timeout = %q, %v", output, err)
+	}
+}
+
+func TestShellCommandHonoursContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell command runs sh")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := NewShellCommandWithTimeout("sleep 10", 0).ExecuteContext(ctx, nil); err == nil {
+		t.Fatal("command outlived its context")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("cancelled command returned after %s, want it killed", elapsed)
+	}
+}
+
+func TestPingArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		privileged bool
+		interval   float64
+		want       string
+	}{
+		{"whole seconds", "linux", false, 1, "-c 4 -i 1 example.org"},
+		{"fraction", "linux", false, 0.5, "-c 4 -i 0.5 example.org"},
+		{"raised on linux", "linux", false, 0.05, "-c 4 -i 0.2 example.org"},
+		{"raised on darwin", "darwin", false, 0.5, "-c 4 -i 1 example.org"},
+		{"privileged", "darwin", true, 0.05, "-c 4 -i 0.05 example.org"},
+		{"no interval", "linux", false, 0, "-c 4 example.org"},
+		{"raised on freebsd", "freebsd", false, 0.5, "-c 4 -i 1 example.org"},
+		{"windows count", "windows", false, 0.5, "-n 4 example.org"},
+		{"windows privileged", "windows", true, 2, "-n 4 example.org"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(pingArgs(tt.goos, tt.privileged, "example.org", 4, tt.interval), " "); got != tt.want {
+				t.Fatalf("pingArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
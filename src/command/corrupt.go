@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CorruptJobsKey is a hash of job ID to the raw data of job entries that could not be decoded
+const CorruptJobsKey = "schedulerx:corrupt"
+
+// DiscardCorruptJob removes a job whose details cannot be decoded from the sorted
+// set and deletes its details, so it stops being reprocessed. With quarantine set
+// the raw data is kept in CorruptJobsKey for inspection.
+func DiscardCorruptJob(ctx context.Context, client *redis.Client, jobID string, data []byte, quarantine bool) error {
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if quarantine {
+			pipe.HSet(ctx, CorruptJobsKey, jobID, data)
+		}
+		pipe.ZRem(ctx, JobsSortedSetKey, jobID)
+		pipe.Del(ctx, fmt.Sprintf(JobDetailsKey, jobID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to discard corrupt job %s: %w", jobID, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// defaultEmailSubject is used when no subject param is given
+const defaultEmailSubject = "Scheduled report from schedulerx"
+
+// EmailCommand implements a command that sends an email over SMTP
+type EmailCommand struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       string
+	schedule string
+}
+
+// NewEmailCommand creates a new EmailCommand from the SMTP settings in config
+func NewEmailCommand(config *utils.Config) *EmailCommand {
+	return &EmailCommand{
+		host:     config.SMTPHost,
+		port:     config.SMTPPort,
+		username: config.SMTPUsername,
+		password: config.SMTPPassword,
+		from:     config.SMTPFrom,
+		to:       config.EmailTo,
+		schedule: config.EmailSchedule,
+	}
+}
+
+// ID returns the command identifier
+func (c *EmailCommand) ID() string {
+	return "email"
+}
+
+// Description returns the command description
+func (c *EmailCommand) Description() string {
+	return "Send an email over SMTP"
+}
+
+// Execute sends the email
+func (c *EmailCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput sends the email, params are recipients (comma separated), subject and body
+func (c *EmailCommand) ExecuteWithOutput(params []string) (string, error) {
+	to, subject, body := Param(params, 0, c.to), Param(params, 1, defaultEmailSubject), Param(params, 2, "")
+	recipients, err := parseRecipients(to)
+	if err != nil {
+		return "", err
+	}
+	if err := checkHeaderValue("subject", subject); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		c.from, strings.Join(recipients, ", "), subject, body)
+
+	var auth smtp.Auth
+	if c.username != "" {
+		auth = smtp.PlainAuth("", c.username, c.password, c.host)
+	}
+
+	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	if err := smtp.SendMail(addr, auth, c.from, recipients, []byte(message)); err != nil {
+		return "", fmt.Errorf("failed to send email: %w", err)
+	}
+	return fmt.Sprintf("email sent to %s\n", to), nil
+}
+
+// Validate rejects recipients that are not valid addresses and line breaks in the
+// recipients or subject, which would let params inject headers into the message
+func (c *EmailCommand) Validate(params []string) error {
+	if _, err := parseRecipients(Param(params, 0, c.to)); err != nil {
+		return err
+	}
+	return checkHeaderValue("subject", Param(params, 1, defaultEmailSubject))
+}
+
+// parseRecipients parses a comma separated list of addresses, e.g. "ops@example.com, Dev <dev@example.com>",
+// into the bare addresses
+func parseRecipients(to string) ([]string, error) {
+	if strings.TrimSpace(to) == "" {
+		return nil, fmt.Errorf("no email recipients configured")
+	}
+	if err := checkHeaderValue("to", to); err != nil {
+		return nil, err
+	}
+	addresses, err := mail.ParseAddressList(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid param to %q: %w", to, err)
+	}
+	recipients := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		recipients = append(recipients, address.Address)
+	}
+	return recipients, nil
+}
+
+// checkHeaderValue rejects values containing CR or LF, which would end the header they are written into
+func checkHeaderValue(name, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid param %s: must not contain line breaks", name)
+	}
+	return nil
+}
+
+// ParamSchema returns the typed parameters of the email command
+func (c *EmailCommand) ParamSchema() []ParamSpec {
+	return []ParamSpec{
+		{Name: "to", Type: ParamString, Default: c.to},
+		{Name: "subject", Type: ParamString, Default: defaultEmailSubject},
+		{Name: "body", Type: ParamString},
+	}
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *EmailCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *EmailCommand) Parameters() []string {
+	return []string{c.to, defaultEmailSubject, ""}
+}
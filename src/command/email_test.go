@@ -0,0 +1,158 @@
+package command
+
+import (
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// smtpMessage is a message received by the mock SMTP server
+type smtpMessage struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// mockSMTPServer accepts one SMTP session on a local port and returns the port and
+// the received message, rejecting every recipient with a 550 when rejectRecipients is set
+func mockSMTPServer(t *testing.T, rejectRecipients bool) (int, <-chan smtpMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+
+		var message smtpMessage
+		text.PrintfLine("220 mock ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 mock")
+			case "MAIL":
+				message.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				if rejectRecipients {
+					text.PrintfLine("550 mailbox unavailable")
+					continue
+				}
+				message.recipients = append(message.recipients, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 end with .")
+				lines, err := text.ReadDotLines()
+				if err != nil {
+					return
+				}
+				message.data = strings.Join(lines, "\n")
+				text.PrintfLine("250 OK")
+				received <- message
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestEmailValidateRejectsHeaderInjection(t *testing.T) {
+	c := &EmailCommand{to: "ops@example.com"}
+
+	tests := []struct {
+		name   string
+		params []string
+		valid  bool
+	}{
+		{"defaults", nil, true},
+		{"named recipients", []string{"Ops <ops@example.com>, dev@example.com", "Daily report"}, true},
+		{"line break in to", []string{"ops@example.com\r\nBcc: victim@example.com"}, false},
+		{"line feed in to", []string{"ops@example.com\nBcc: victim@example.com"}, false},
+		{"line break in subject", []string{"ops@example.com", "Report\r\nBcc: victim@example.com"}, false},
+		{"invalid address", []string{"not an address"}, false},
+		{"no recipients", []string{" "}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Validate(tt.params)
+			if tt.valid && err != nil {
+				t.Fatalf("Validate(%q) = %v, want nil", tt.params, err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatalf("Validate(%q) = nil, want an error", tt.params)
+			}
+		})
+	}
+}
+
+func TestParseRecipientsReturnsBareAddresses(t *testing.T) {
+	recipients, err := parseRecipients("Ops <ops@example.com>, dev@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ops@example.com", "dev@example.com"}; !slices.Equal(recipients, want) {
+		t.Fatalf("parseRecipients = %q, want %q", recipients, want)
+	}
+}
+
+func TestEmailExecuteRejectsHeaderInjection(t *testing.T) {
+	c := &EmailCommand{host: "127.0.0.1", port: 1, to: "ops@example.com"}
+	_, err := c.ExecuteWithOutput([]string{"ops@example.com", "Report\nBcc: victim@example.com"})
+	if err == nil || err.Error() != "invalid param subject: must not contain line breaks" {
+		t.Fatalf("ExecuteWithOutput = %v, want the line break to be rejected before sending", err)
+	}
+}
+
+func TestEmailExecuteSendsMessage(t *testing.T) {
+	port, received := mockSMTPServer(t, false)
+	c := &EmailCommand{host: "127.0.0.1", port: port, from: "scheduler@example.com", to: "ops@example.com"}
+
+	output, err := c.ExecuteWithOutput([]string{"Ops <ops@example.com>, dev@example.com", "Daily report", "All jobs succeeded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "email sent to") {
+		t.Fatalf("output = %q, want a confirmation", output)
+	}
+
+	message := <-received
+	if message.from != "scheduler@example.com" {
+		t.Fatalf("envelope sender %q, want scheduler@example.com", message.from)
+	}
+	if want := []string{"ops@example.com", "dev@example.com"}; !slices.Equal(message.recipients, want) {
+		t.Fatalf("envelope recipients %q, want %q", message.recipients, want)
+	}
+	for _, want := range []string{"From: scheduler@example.com", "To: ops@example.com, dev@example.com", "Subject: Daily report", "All jobs succeeded"} {
+		if !strings.Contains(message.data, want) {
+			t.Fatalf("message %q is missing %q", message.data, want)
+		}
+	}
+}
+
+func TestEmailExecuteFailsOnSMTPError(t *testing.T) {
+	port, _ := mockSMTPServer(t, true)
+	c := &EmailCommand{host: "127.0.0.1", port: port, from: "scheduler@example.com", to: "ops@example.com"}
+
+	if _, err := c.ExecuteWithOutput(nil); err == nil || !strings.Contains(err.Error(), "550") {
+		t.Fatalf("ExecuteWithOutput = %v, want the rejected recipient to fail the command", err)
+	}
+}
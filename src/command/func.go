@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+	"fmt"
+)
+
+// Func is an in-process task run by a FuncCommand. It returns the job's output,
+// ctx is done once the job is cancelled or its timeout expires.
+type Func func(ctx context.Context, params []string) (string, error)
+
+// FuncCommand runs a Go function registered in-process, so embedding
+// applications can schedule native tasks without shelling out
+type FuncCommand struct {
+	overridableParams
+	id          string
+	description string
+	schedule    string
+	params      []string
+	fn          Func
+}
+
+// NewFuncCommand creates a new FuncCommand running fn on schedule with params as
+// its default parameters. An empty schedule means fn only runs when triggered.
+func NewFuncCommand(id, description, schedule string, fn Func, params ...string) *FuncCommand {
+	return &FuncCommand{
+		id:          id,
+		description: description,
+		schedule:    schedule,
+		params:      params,
+		fn:          fn,
+	}
+}
+
+// ID returns the command identifier
+func (c *FuncCommand) ID() string {
+	return c.id
+}
+
+// Description returns the command description
+func (c *FuncCommand) Description() string {
+	return c.description
+}
+
+// Execute runs the function and prints its output
+func (c *FuncCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput runs the function and returns its output
+func (c *FuncCommand) ExecuteWithOutput(params []string) (string, error) {
+	return c.ExecuteContext(context.Background(), params)
+}
+
+// ExecuteContext runs the function until it returns. A panic fails the job
+// instead of taking down the pod.
+func (c *FuncCommand) ExecuteContext(ctx context.Context, params []string) (output string, err error) {
+	if c.fn == nil {
+		return "", fmt.Errorf("command %s has no function", c.id)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("command %s panicked: %v", c.id, r)
+		}
+	}()
+	return c.fn(ctx, params)
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *FuncCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *FuncCommand) Parameters() []string {
+	return c.defaults(c.params...)
+}
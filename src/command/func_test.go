@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFuncCommandRunsFunction(t *testing.T) {
+	var got []string
+	cmd := NewFuncCommand("native", "Native task", "@every 1m", func(ctx context.Context, params []string) (string, error) {
+		got = params
+		return "ran " + strings.Join(params, ","), nil
+	}, "a", "b")
+
+	if schedule, params, err := cmd.Schedule(); err != nil || schedule != "@every 1m" || !slices.Equal(params, []string{"a", "b"}) {
+		t.Fatalf("Schedule = %q, %q, %v, want @every 1m with the default params", schedule, params, err)
+	}
+	output, err := cmd.ExecuteContext(context.Background(), []string{"x"})
+	if err != nil || output != "ran x" || !slices.Equal(got, []string{"x"}) {
+		t.Fatalf("ExecuteContext = %q, %v with params %q, want the function run with x", output, err, got)
+	}
+}
+
+func TestFuncCommandPanicFailsJob(t *testing.T) {
+	cmd := NewFuncCommand("native", "Native task", "", func(ctx context.Context, params []string) (string, error) {
+		panic("boom")
+	})
+	if _, err := cmd.ExecuteWithOutput(nil); err == nil || !strings.Contains(err.Error(), "panicked: boom") {
+		t.Fatalf("ExecuteWithOutput = %v, want the panic as an error", err)
+	}
+	if _, err := NewFuncCommand("empty", "No function", "", nil).ExecuteWithOutput(nil); err == nil {
+		t.Fatal("command without a function succeeded")
+	}
+}
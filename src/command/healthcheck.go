@@ -0,0 +1,131 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthCheckTimeout bounds a single probe, on top of any latency assertion
+const healthCheckTimeout = 30 * time.Second
+
+// healthCheckBodyLimit caps how much of the response body is read for the contains assertion
+const healthCheckBodyLimit = 1 << 20
+
+// HealthCheckCommand probes a URL and fails unless the response matches the expected
+// status code, arrives within the latency threshold and contains the expected substring
+type HealthCheckCommand struct {
+	url      string
+	schedule string
+	client   *http.Client
+}
+
+// NewHealthCheckCommand creates a new HealthCheckCommand probing url on schedule
+func NewHealthCheckCommand(url, schedule string) *HealthCheckCommand {
+	return &HealthCheckCommand{
+		url:      url,
+		schedule: schedule,
+		client:   &http.Client{Timeout: healthCheckTimeout},
+	}
+}
+
+// ID returns the command identifier
+func (c *HealthCheckCommand) ID() string {
+	return "healthcheck"
+}
+
+// Description returns the command description
+func (c *HealthCheckCommand) Description() string {
+	return "Probe a URL and assert on status, latency and body"
+}
+
+// Execute runs the probe and prints the result
+func (c *HealthCheckCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput runs the probe and returns the measured status and latency
+func (c *HealthCheckCommand) ExecuteWithOutput(params []string) (string, error) {
+	return c.ExecuteContext(context.Background(), params)
+}
+
+// ExecuteContext probes the configured URL, params are expected status, max latency in ms (0 disables) and body substring.
+// The URL is not a param, so jobs triggered through the API cannot make the pod request arbitrary addresses.
+func (c *HealthCheckCommand) ExecuteContext(ctx context.Context, params []string) (string, error) {
+	params, err := CoerceParams(c.ParamSchema(), params)
+	if err != nil {
+		return "", err
+	}
+	url, bodyContains := c.url, Param(params, 2, "")
+	expectedStatus, err := IntParam(params, 0, "status", "")
+	if err != nil {
+		return "", err
+	}
+	maxLatencyMs, err := IntParam(params, 1, "max_latency_ms", "0")
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid health check request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("health check request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, healthCheckBodyLimit))
+	latency := time.Since(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to read health check response: %w", err)
+	}
+
+	output := fmt.Sprintf("url=%s status=%d latency_ms=%d\n", url, resp.StatusCode, latency.Milliseconds())
+
+	if resp.StatusCode != expectedStatus {
+		return output, fmt.Errorf("unexpected status %d, expected %d", resp.StatusCode, expectedStatus)
+	}
+	if maxLatencyMs > 0 && latency > time.Duration(maxLatencyMs)*time.Millisecond {
+		return output, fmt.Errorf("latency %dms exceeds threshold %dms", latency.Milliseconds(), maxLatencyMs)
+	}
+	if bodyContains != "" && !strings.Contains(string(body), bodyContains) {
+		return output, fmt.Errorf("response body does not contain %q", bodyContains)
+	}
+	return output, nil
+}
+
+// ParamSchema returns the typed parameters of the health check command
+func (c *HealthCheckCommand) ParamSchema() []ParamSpec {
+	return []ParamSpec{
+		{Name: "status", Type: ParamInt, Default: "200"},
+		{Name: "max_latency_ms", Type: ParamInt, Default: "0"},
+		{Name: "body_contains", Type: ParamString},
+	}
+}
+
+// Validate checks the params against the command's schema
+func (c *HealthCheckCommand) Validate(params []string) error {
+	_, err := CoerceParams(c.ParamSchema(), params)
+	return err
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *HealthCheckCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *HealthCheckCommand) Parameters() []string {
+	return []string{"200", "0", ""}
+}
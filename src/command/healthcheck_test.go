@@ -0,0 +1,87 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "status: ok")
+	}))
+	defer server.Close()
+	c := NewHealthCheckCommand(server.URL, "")
+
+	tests := []struct {
+		name   string
+		params []string
+		err    string
+	}{
+		{"defaults", nil, ""},
+		{"body contains", []string{"200", "0", "ok"}, ""},
+		{"unexpected status", []string{"204"}, "unexpected status 200, expected 204"},
+		{"missing substring", []string{"200", "0", "degraded"}, `does not contain "degraded"`},
+		{"invalid status", []string{"ok"}, "status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.ExecuteWithOutput(tt.params)
+			if tt.err == "" && err != nil {
+				t.Fatalf("ExecuteWithOutput(%q) = %v, want nil", tt.params, err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("ExecuteWithOutput(%q) = %v, want an error containing %q", tt.params, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHealthCheckOnlyProbesConfiguredURL(t *testing.T) {
+	var probed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probed = append(probed, r.URL.Path)
+	}))
+	defer server.Close()
+	c := NewHealthCheckCommand(server.URL+"/healthz", "")
+
+	for _, spec := range c.ParamSchema() {
+		if spec.Name == "url" {
+			t.Fatal("url must not be a param, jobs could probe any address")
+		}
+	}
+	if err := c.Validate([]string{"http://169.254.169.254/latest/meta-data"}); err == nil {
+		t.Fatal("a URL was accepted as the status param")
+	}
+
+	if _, err := c.ExecuteWithOutput(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(probed) != 1 || probed[0] != "/healthz" {
+		t.Fatalf("probed %q, want only the configured /healthz", probed)
+	}
+}
+
+func TestHealthCheckLatencyThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "status: ok")
+	}))
+	defer server.Close()
+	c := NewHealthCheckCommand(server.URL, "")
+
+	output, err := c.ExecuteWithOutput([]string{"200", "10"})
+	if err == nil || !strings.Contains(err.Error(), "exceeds threshold 10ms") {
+		t.Fatalf("ExecuteWithOutput = %v, want the slow response to fail the latency assertion", err)
+	}
+	if !strings.Contains(output, "status=200 latency_ms=") {
+		t.Fatalf("output %q does not record the measured latency", output)
+	}
+
+	if _, err := c.ExecuteWithOutput([]string{"200", "5000"}); err != nil {
+		t.Fatalf("response within the threshold failed: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// commandIDPattern is what command IDs may consist of. Command IDs end up in Redis
+	// keys, job IDs, metric labels and environment variable names.
+	commandIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// jobIDPattern additionally allows the "." separating the milliseconds of a job ID
+	jobIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+)
+
+// ValidateCommandID returns an error unless id only consists of letters, digits, "_" and "-"
+func ValidateCommandID(id string) error {
+	if !commandIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid command ID %q: must match %s", id, commandIDPattern)
+	}
+	return nil
+}
+
+// ValidateJobID returns an error unless id could have been generated for a valid command.
+// IDs from outside, e.g. API paths, are checked before they are used in Redis keys.
+func ValidateJobID(id string) error {
+	if !jobIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid job ID %q: must match %s", id, jobIDPattern)
+	}
+	return nil
+}
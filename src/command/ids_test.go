@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnsafeIDsAreRejected(t *testing.T) {
+	tests := []struct {
+		id          string
+		commandSafe bool
+		jobSafe     bool
+	}{
+		{"backup-db_2", true, true},
+		{"echo_1700000000.250", false, true},
+		{"", false, false},
+		{"has space", false, false},
+		{"new\nline", false, false},
+		{"job:details", false, false},
+		{"glob*", false, false},
+		{"../etc", false, false},
+		{"café", false, false},
+	}
+	for _, tt := range tests {
+		if err := ValidateCommandID(tt.id); (err == nil) != tt.commandSafe {
+			t.Errorf("ValidateCommandID(%q) = %v, want safe %v", tt.id, err, tt.commandSafe)
+		}
+		if err := ValidateJobID(tt.id); (err == nil) != tt.jobSafe {
+			t.Errorf("ValidateJobID(%q) = %v, want safe %v", tt.id, err, tt.jobSafe)
+		}
+
+		registry := NewCommandRegistry()
+		cmd := NewFuncCommand(tt.id, "test command", "", func(ctx context.Context, params []string) (string, error) {
+			return "", nil
+		})
+		if err := registry.Register(cmd); (err == nil) != tt.commandSafe {
+			t.Errorf("registering %q returned %v, want accepted %v", tt.id, err, tt.commandSafe)
+		}
+	}
+}
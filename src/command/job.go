@@ -3,10 +3,14 @@ package command
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os/exec"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/utils"
 )
 
 // JobStatus represents the current state of a job
@@ -18,42 +22,135 @@ const (
 	Running   JobStatus = "running"
 	Failed    JobStatus = "failed"
 	Success   JobStatus = "success"
+	Cancelled JobStatus = "cancelled"
 )
 
+// IsTerminal reports whether the status is final, i.e. the job will not run again
+func (s JobStatus) IsTerminal() bool {
+	return s == Success || s == Failed || s == Cancelled
+}
+
 // Redis keys
 const (
 	JobsSortedSetKey = "scheduler:jobs"
-	JobDetailsKey    = "scheduler:job:%s" // Format string for job details
+	JobDetailsKey    = "scheduler:job:%s"        // Format string for job details
+	CompletedJobsKey = "schedulerx:completed"    // Finished jobs scored by FinishedAt, see COMPLETED_JOB_RETENTION
+	JobOutputKey     = "scheduler:job_output:%s" // Output kept apart from the details, see UpdateInRedisSeparateOutput
 )
 
+// JobDetailsTTL is how long job details are kept after their last write
+const JobDetailsTTL = 24 * time.Hour
+
 // Job represents a scheduled command execution
 type Job struct {
-	ID          string     // Unique Job ID
-	CommandID   string     // Related Command
-	Params      []string   // Command parameters
-	Status      JobStatus  // Current status of the job
-	ScheduledAt time.Time  // When the job is scheduled to run
-	StartedAt   *time.Time // When the job actually started
-	FinishedAt  *time.Time // When the job finished
-	Error       string     // Error message if job failed
-	AssignedTo  string     // ID of the pod assigned to run this job
-}
-
-// NewJob creates a new job with a unique ID based on command ID and scheduled time
-func NewJob(commandID string, params []string, scheduledAt time.Time) *Job {
-	// Create a unique ID by combining command ID and scheduled time
-	// Format: commandID_timestamp
-	jobID := fmt.Sprintf("%s_%d", commandID, scheduledAt.Unix())
+	ID           string            // Unique Job ID
+	CommandID    string            // Related Command
+	Params       []string          // Command parameters
+	Status       JobStatus         // Current status of the job
+	ScheduledAt  time.Time         // When the job is scheduled to run
+	StartedAt    *time.Time        // When the job actually started
+	FinishedAt   *time.Time        // When the job finished
+	Error        string            // Error message if job failed
+	Output       string            // Output captured from the command execution
+	AssignedTo   string            // ID of the pod assigned to run this job
+	PinnedPod    string            `json:",omitempty"` // Pod the job must run on, bypassing round-robin assignment
+	Attempt      int               // Number of times execution of the job has been started
+	Shard        int               // Index of this shard within its occurrence, 0..Shards-1
+	Shards       int               // Number of parallel shards enqueued for the occurrence, 0 when not sharded
+	Labels       map[string]string `json:",omitempty"` // Free-form tags such as team=payments, used for filtering
+	Attempts     []Attempt         `json:",omitempty"` // Execution history, capped at MaxAttemptHistory entries
+	ParentJobID  string            `json:",omitempty"` // Job whose outcome enqueued this one, empty unless chained
+	ChainDepth   int               `json:",omitempty"` // Number of chained jobs before this one, 0 for scheduled jobs
+	CacheHit     bool              `json:",omitempty"` // Output was served from the result cache without running the command
+	TraceContext string            `json:",omitempty"` // W3C traceparent of the span that created the job, empty without tracing
+}
+
+// MaxAttemptHistory caps how many attempts are kept on a job, the oldest are dropped first
+const MaxAttemptHistory = 10
+
+// Attempt records a single execution of a job
+type Attempt struct {
+	PodID      string     // Pod that ran the attempt
+	StartedAt  time.Time  // When the attempt started
+	FinishedAt *time.Time // When the attempt finished, nil while running
+	Error      string     // Error message if the attempt failed
+	ExitCode   int        // Exit code of the process, 0 on success and 1 for failures without one
+}
 
+// NewJob creates a new job with a unique ID based on command ID and scheduled time.
+// Params are stored as given, a nil slice is normalized to an empty one; callers
+// wanting the command defaults should resolve them before creating the job.
+func NewJob(commandID string, params []string, scheduledAt time.Time) *Job {
 	return &Job{
-		ID:          jobID,
+		ID:          jobID(commandID, scheduledAt),
 		CommandID:   commandID,
-		Params:      params,
+		Params:      NormalizeParams(params),
 		Status:      Scheduled,
 		ScheduledAt: scheduledAt,
 	}
 }
 
+// jobID combines command ID and scheduled time, at millisecond precision.
+// Format: commandID_timestamp, with the milliseconds appended only for sub-second
+// times (e.g. echo_1700000000.250), so whole-second IDs stay as they were.
+func jobID(commandID string, scheduledAt time.Time) string {
+	seconds := scheduledAt.Unix()
+	millis := scheduledAt.UnixMilli() - seconds*1000
+	if millis == 0 {
+		return fmt.Sprintf("%s_%d", commandID, seconds)
+	}
+	return fmt.Sprintf("%s_%d.%03d", commandID, seconds, millis)
+}
+
+// JobScore returns the sorted set score of a job due at t, in Unix milliseconds
+func JobScore(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// Reschedule moves a job that has not been stored yet to scheduledAt, updating its ID to match
+func (j *Job) Reschedule(scheduledAt time.Time) {
+	j.ScheduledAt = scheduledAt
+	j.ID = jobID(j.CommandID, scheduledAt)
+}
+
+// NewShardedJobs creates one job per shard for a single occurrence of a command.
+// With fewer than two shards it returns a single regular job.
+func NewShardedJobs(commandID string, params []string, scheduledAt time.Time, shards int) []*Job {
+	if shards < 2 {
+		return []*Job{NewJob(commandID, params, scheduledAt)}
+	}
+
+	jobs := make([]*Job, 0, shards)
+	for shard := 0; shard < shards; shard++ {
+		job := NewJob(commandID, params, scheduledAt)
+		job.ID = fmt.Sprintf("%s_shard%d", job.ID, shard)
+		job.Shard = shard
+		job.Shards = shards
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// MatchesLabels reports whether the job carries every key/value pair in selector
+func (j *Job) MatchesLabels(selector map[string]string) bool {
+	for key, value := range selector {
+		if j.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ExecutionParams returns the params the command is run with, the shard index is appended for sharded jobs
+func (j *Job) ExecutionParams() []string {
+	if j.Shards < 2 {
+		return NormalizeParams(j.Params)
+	}
+	params := make([]string, 0, len(j.Params)+1)
+	params = append(params, j.Params...)
+	return append(params, strconv.Itoa(j.Shard))
+}
+
 // StoreInRedis stores the job in Redis using a sorted set for scheduling and a hash for job details
 func (j *Job) StoreInRedis(ctx context.Context, client *redis.Client) error {
 	// Store job details in a hash
@@ -65,9 +162,9 @@ func (j *Job) StoreInRedis(ctx context.Context, client *redis.Client) error {
 
 	// Store in sorted set with scheduled time as score
 	pipe := client.Pipeline()
-	pipe.Set(ctx, jobKey, jobData, 24*time.Hour) // Store for 24 hours
+	pipe.Set(ctx, jobKey, jobData, JobDetailsTTL)
 	pipe.ZAdd(ctx, JobsSortedSetKey, redis.Z{
-		Score:  float64(j.ScheduledAt.Unix()),
+		Score:  JobScore(j.ScheduledAt),
 		Member: j.ID,
 	})
 
@@ -79,10 +176,137 @@ func (j *Job) StoreInRedis(ctx context.Context, client *redis.Client) error {
 	return nil
 }
 
+// ErrStaleFencingToken is returned when a fenced write is attempted with an outdated leader token
+var ErrStaleFencingToken = errors.New("stale leader fencing token")
+
+// fencedStoreScript stores job details and its sorted set entry only if the
+// caller's token is not older than the latest token stored at KEYS[1]
+var fencedStoreScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) < current then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[2], 'EX', ARGV[3])
+redis.call('ZADD', KEYS[3], ARGV[4], ARGV[5])
+return 1
+`)
+
+// StoreInRedisFenced behaves like StoreInRedis but rejects the write with
+// ErrStaleFencingToken if a newer token than the given one exists at tokenKey
+func (j *Job) StoreInRedisFenced(ctx context.Context, client *redis.Client, tokenKey string, token int64) error {
+	jobKey := fmt.Sprintf(JobDetailsKey, j.ID)
+	jobData, err := json.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job data: %w", err)
+	}
+
+	stored, err := fencedStoreScript.Run(ctx, client,
+		[]string{tokenKey, jobKey, JobsSortedSetKey},
+		token, jobData, int64(JobDetailsTTL.Seconds()), j.ScheduledAt.UnixMilli(), j.ID,
+	).Int()
+	if err != nil {
+		return fmt.Errorf("failed to store job in Redis: %w", err)
+	}
+	if stored == 0 {
+		return ErrStaleFencingToken
+	}
+
+	return nil
+}
+
+// fencedCreateScript behaves like fencedStoreScript but leaves a job whose details
+// already exist at KEYS[2] untouched, returning 2 for it
+var fencedCreateScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) < current then
+	return 0
+end
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return 2
+end
+redis.call('SET', KEYS[2], ARGV[2], 'EX', ARGV[3])
+redis.call('ZADD', KEYS[3], ARGV[4], ARGV[5])
+return 1
+`)
+
+// CreateInRedisFenced behaves like StoreInRedisFenced but only creates the job. A job
+// whose details already exist, e.g. because it was assigned, pinned or has finished,
+// is left as it is. It reports whether the job was created.
+func (j *Job) CreateInRedisFenced(ctx context.Context, client *redis.Client, tokenKey string, token int64) (bool, error) {
+	jobKey := fmt.Sprintf(JobDetailsKey, j.ID)
+	jobData, err := json.Marshal(j)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job data: %w", err)
+	}
+
+	stored, err := fencedCreateScript.Run(ctx, client,
+		[]string{tokenKey, jobKey, JobsSortedSetKey},
+		token, jobData, int64(JobDetailsTTL.Seconds()), j.ScheduledAt.UnixMilli(), j.ID,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to store job in Redis: %w", err)
+	}
+	if stored == 0 {
+		return false, ErrStaleFencingToken
+	}
+
+	return stored == 1, nil
+}
+
+// createScript stores the job details at KEYS[1] and adds the job to the sorted set
+// at KEYS[2], unless the details already exist. It returns 1 if the job was created.
+var createScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+return 1
+`)
+
+// CreateInRedis behaves like CreateInRedisFenced without the fencing token check,
+// for writes made outside the leader such as follow-ups enqueued by the executor
+func (j *Job) CreateInRedis(ctx context.Context, client *redis.Client) (bool, error) {
+	jobKey := fmt.Sprintf(JobDetailsKey, j.ID)
+	jobData, err := json.Marshal(j)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job data: %w", err)
+	}
+
+	created, err := createScript.Run(ctx, client,
+		[]string{jobKey, JobsSortedSetKey},
+		jobData, int64(JobDetailsTTL.Seconds()), j.ScheduledAt.UnixMilli(), j.ID,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to store job in Redis: %w", err)
+	}
+
+	return created == 1, nil
+}
+
 // UpdateInRedis updates the job status and details in Redis
 func (j *Job) UpdateInRedis(ctx context.Context, client *redis.Client) error {
+	return j.update(ctx, client, false)
+}
+
+// UpdateInRedisSeparateOutput behaves like UpdateInRedis but stores the output under
+// JobOutputKey instead of in the details, so reading the job's status does not fetch
+// a potentially large output. Read the output with GetJobOutput.
+func (j *Job) UpdateInRedisSeparateOutput(ctx context.Context, client *redis.Client) error {
+	return j.update(ctx, client, true)
+}
+
+// update writes the job details, with or without the output, and drops finished jobs from the sorted set
+func (j *Job) update(ctx context.Context, client *redis.Client, separateOutput bool) error {
+	details := j
+	if separateOutput {
+		withoutOutput := *j
+		withoutOutput.Output = ""
+		details = &withoutOutput
+	}
+
 	jobKey := fmt.Sprintf(JobDetailsKey, j.ID)
-	jobData, err := json.Marshal(j)
+	jobData, err := json.Marshal(details)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job data: %w", err)
 	}
@@ -90,10 +314,13 @@ func (j *Job) UpdateInRedis(ctx context.Context, client *redis.Client) error {
 	pipe := client.Pipeline()
 
 	// Update job details
-	pipe.Set(ctx, jobKey, jobData, 24*time.Hour)
+	pipe.Set(ctx, jobKey, jobData, JobDetailsTTL)
+	if separateOutput {
+		pipe.Set(ctx, fmt.Sprintf(JobOutputKey, j.ID), j.Output, JobDetailsTTL)
+	}
 
-	// If job is completed (success or failed), remove from sorted set
-	if j.Status == Success || j.Status == Failed {
+	// If job is completed (success, failed or cancelled), remove from sorted set
+	if j.Status.IsTerminal() {
 		pipe.ZRem(ctx, JobsSortedSetKey, j.ID)
 	}
 
@@ -105,11 +332,42 @@ func (j *Job) UpdateInRedis(ctx context.Context, client *redis.Client) error {
 	return nil
 }
 
-// Start marks the job as running and sets the start time
+// GetJobOutput returns the output of a job, from JobOutputKey when it was stored
+// there and from the job details otherwise. It returns redis.Nil when neither exists.
+func GetJobOutput(ctx context.Context, client *redis.Client, jobID string) (string, error) {
+	output, err := client.Get(ctx, fmt.Sprintf(JobOutputKey, jobID)).Result()
+	if err == nil {
+		return output, nil
+	}
+	if err != redis.Nil {
+		return "", fmt.Errorf("failed to get output of job %s: %w", jobID, err)
+	}
+
+	data, err := client.Get(ctx, fmt.Sprintf(JobDetailsKey, jobID)).Bytes()
+	if err == redis.Nil {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job %s: %w", jobID, err)
+	}
+	return job.Output, nil
+}
+
+// Start marks the job as running, sets the start time and opens a new attempt
 func (j *Job) Start() {
 	now := time.Now()
 	j.StartedAt = &now
 	j.Status = Running
+	j.Attempt++
+
+	j.Attempts = append(j.Attempts, Attempt{PodID: j.AssignedTo, StartedAt: now})
+	if len(j.Attempts) > MaxAttemptHistory {
+		j.Attempts = j.Attempts[len(j.Attempts)-MaxAttemptHistory:]
+	}
 }
 
 // Complete marks the job as successful and sets the finish time
@@ -117,6 +375,7 @@ func (j *Job) Complete() {
 	now := time.Now()
 	j.FinishedAt = &now
 	j.Status = Success
+	j.finishAttempt(now, nil)
 }
 
 // Fail marks the job as failed, sets the finish time and error message
@@ -127,11 +386,57 @@ func (j *Job) Fail(err error) {
 	if err != nil {
 		j.Error = err.Error()
 	}
+	j.finishAttempt(now, err)
+}
+
+// finishAttempt closes the attempt opened by Start, if it is still running
+func (j *Job) finishAttempt(at time.Time, err error) {
+	if len(j.Attempts) == 0 {
+		return
+	}
+	attempt := &j.Attempts[len(j.Attempts)-1]
+	if attempt.FinishedAt != nil {
+		return
+	}
+
+	attempt.FinishedAt = &at
+	if err != nil {
+		attempt.Error = err.Error()
+		attempt.ExitCode = 1
+		if code, ok := ExitCode(err); ok {
+			attempt.ExitCode = code
+		}
+	}
 }
 
-// IsOverdue checks if the job is overdue based on its scheduled time
-func (j *Job) IsOverdue() bool {
-	return time.Now().After(j.ScheduledAt)
+// ExitCode returns the exit code of the process that caused err, ok is false
+// when err does not come from a process exiting, e.g. a timeout
+func ExitCode(err error) (code int, ok bool) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// Cancel marks the job as cancelled, sets the finish time and the reason
+func (j *Job) Cancel(reason string) {
+	now := time.Now()
+	j.FinishedAt = &now
+	j.Status = Cancelled
+	j.Error = reason
+}
+
+// Age returns how long ago the job was scheduled to run
+func (j *Job) Age() time.Duration {
+	return time.Since(j.ScheduledAt)
+}
+
+// IsOverdue checks if the job is due by more than tolerance. Jobs are usually
+// stored or picked up a little after their scheduled time, within the tolerance
+// they still count as on time.
+func (j *Job) IsOverdue(tolerance time.Duration) bool {
+	return j.Age() > tolerance
 }
 
 // Duration returns the duration of the job execution if it has finished
@@ -143,6 +448,22 @@ func (j *Job) Duration() *time.Duration {
 	return &duration
 }
 
+// Logger returns a child of parent carrying the fields logged on every job transition
+func (j *Job) Logger(parent *utils.StandardLogger) *utils.StandardLogger {
+	durationMs := ""
+	if d := j.Duration(); d != nil {
+		durationMs = strconv.FormatInt(d.Milliseconds(), 10)
+	}
+	return utils.GetChildLogger(parent, map[string]string{
+		"job_id":      j.ID,
+		"command_id":  j.CommandID,
+		"status":      string(j.Status),
+		"pod_id":      j.AssignedTo,
+		"attempt":     strconv.Itoa(j.Attempt),
+		"duration_ms": durationMs,
+	})
+}
+
 // String returns a string representation of the job
 func (j *Job) String() string {
 	assignedTo := "unassigned"
@@ -0,0 +1,306 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// testTokenKey holds the fencing token in tests
+const testTokenKey = "test:fencing_token"
+
+// newTestRedis returns a client on a fresh in-memory Redis
+func newTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+// loadJob returns the stored details of a job
+func loadJob(t *testing.T, client *redis.Client, jobID string) *Job {
+	t.Helper()
+	data, err := client.Get(context.Background(), fmt.Sprintf(JobDetailsKey, jobID)).Bytes()
+	if err != nil {
+		t.Fatalf("failed to load job %s: %v", jobID, err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatal(err)
+	}
+	return &job
+}
+
+func TestStaleLeaderCannotStoreJobs(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestRedis(t)
+	job := NewJob("work", nil, time.Now().Truncate(time.Millisecond))
+
+	// The old leader held token 1, a new leader has since taken token 2
+	server.Set(testTokenKey, "2")
+	if err := job.StoreInRedisFenced(ctx, client, testTokenKey, 1); !errors.Is(err, ErrStaleFencingToken) {
+		t.Fatalf("stale write returned %v, want %v", err, ErrStaleFencingToken)
+	}
+	if server.Exists(fmt.Sprintf(JobDetailsKey, job.ID)) {
+		t.Fatal("stale leader stored job details")
+	}
+	if created, err := job.CreateInRedisFenced(ctx, client, testTokenKey, 1); !errors.Is(err, ErrStaleFencingToken) || created {
+		t.Fatalf("stale create returned %v, %v, want %v", created, err, ErrStaleFencingToken)
+	}
+
+	if err := job.StoreInRedisFenced(ctx, client, testTokenKey, 2); err != nil {
+		t.Fatalf("current leader's write failed: %v", err)
+	}
+	if members, _ := server.ZMembers(JobsSortedSetKey); len(members) != 1 || members[0] != job.ID {
+		t.Fatalf("job set holds %v, want %s", members, job.ID)
+	}
+
+	// Without any token stored, e.g. before the first election, writes pass
+	server.Del(testTokenKey)
+	if err := job.StoreInRedisFenced(ctx, client, testTokenKey, 0); err != nil {
+		t.Fatalf("write without a stored token failed: %v", err)
+	}
+}
+
+func TestCreateInRedisFencedKeepsExistingJobs(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestRedis(t)
+	job := NewJob("work", nil, time.Now().Truncate(time.Millisecond))
+	job.AssignedTo, job.Status = "pod-a", Assigned
+	if err := job.StoreInRedis(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := NewJob("work", nil, job.ScheduledAt)
+	created, err := fresh.CreateInRedisFenced(ctx, client, testTokenKey, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Fatal("existing job was reported as created")
+	}
+	if stored := loadJob(t, client, job.ID); stored.AssignedTo != "pod-a" || stored.Status != Assigned {
+		t.Fatalf("existing job was overwritten: %s on %q", stored.Status, stored.AssignedTo)
+	}
+}
+
+func TestJobLoggerCarriesTransitionFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	parent := &utils.StandardLogger{SugaredLogger: zap.New(core).Sugar()}
+
+	job := NewJob("work", nil, time.Now())
+	job.AssignedTo = "pod-a"
+	job.Start()
+	job.Complete()
+	job.Logger(parent).Info("Completed job execution")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]any{
+		"job_id":     job.ID,
+		"command_id": "work",
+		"status":     string(Success),
+		"pod_id":     "pod-a",
+		"attempt":    "1",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("field %s = %v, want %v", key, fields[key], value)
+		}
+	}
+	if _, ok := fields["duration_ms"]; !ok {
+		t.Error("field duration_ms is missing")
+	}
+}
+
+func TestAttemptsRecordRetryTimeline(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestRedis(t)
+	job := NewJob("work", nil, time.Now())
+
+	for _, pod := range []string{"pod-a", "pod-b"} {
+		job.AssignedTo = pod
+		job.Start()
+		job.Fail(exec.Command("sh", "-c", "exit 3").Run())
+	}
+	job.AssignedTo = "pod-c"
+	job.Start()
+	job.Complete()
+	if err := job.StoreInRedis(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := loadJob(t, client, job.ID).Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("%d attempts recorded, want 3", len(attempts))
+	}
+	for i, attempt := range attempts[:2] {
+		if attempt.PodID != []string{"pod-a", "pod-b"}[i] || attempt.ExitCode != 3 || attempt.Error == "" || attempt.FinishedAt == nil {
+			t.Fatalf("failed attempt %d = %+v, want its pod, error and exit code 3", i, attempt)
+		}
+	}
+	if last := attempts[2]; last.PodID != "pod-c" || last.ExitCode != 0 || last.Error != "" || last.FinishedAt == nil {
+		t.Fatalf("successful attempt = %+v, want a finished attempt on pod-c without error", last)
+	}
+}
+
+func TestAttemptsAreCapped(t *testing.T) {
+	job := NewJob("work", nil, time.Now())
+	for i := range MaxAttemptHistory + 5 {
+		job.AssignedTo = fmt.Sprintf("pod-%d", i)
+		job.Start()
+		job.Fail(errors.New("timed out"))
+	}
+
+	if len(job.Attempts) != MaxAttemptHistory {
+		t.Fatalf("%d attempts kept, want %d", len(job.Attempts), MaxAttemptHistory)
+	}
+	if first := job.Attempts[0]; first.PodID != "pod-5" || first.ExitCode != 1 {
+		t.Fatalf("oldest kept attempt = %+v, want pod-5 with exit code 1", first)
+	}
+}
+
+func TestParamsRoundTripThroughRedis(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestRedis(t)
+	start := time.Now()
+
+	tests := []struct {
+		name   string
+		params []string
+		want   []string
+	}{
+		{"nil", nil, []string{}},
+		{"empty", []string{}, []string{}},
+		{"populated", []string{"/var", ""}, []string{"/var", ""}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := NewJob("work", tt.params, start.Add(time.Duration(i)*time.Second))
+			if err := job.StoreInRedis(ctx, client); err != nil {
+				t.Fatal(err)
+			}
+
+			stored := loadJob(t, client, job.ID)
+			if stored.Params == nil || !slices.Equal(stored.Params, tt.want) {
+				t.Fatalf("params loaded as %#v, want %#v", stored.Params, tt.want)
+			}
+			if raw, _ := server.Get(fmt.Sprintf(JobDetailsKey, job.ID)); strings.Contains(raw, `"Params":null`) {
+				t.Fatalf("params stored as null: %s", raw)
+			}
+		})
+	}
+}
+
+func TestMergeParamsNilUsesDefaults(t *testing.T) {
+	defaults := []string{"/", "-h"}
+	tests := []struct {
+		name      string
+		overrides []string
+		want      []string
+	}{
+		{"nil", nil, []string{"/", "-h"}},
+		{"empty", []string{}, []string{"/", "-h"}},
+		{"shorter", []string{"/var"}, []string{"/var", "-h"}},
+		{"longer", []string{"/var", "-k", "--total"}, []string{"/var", "-k", "--total"}},
+	}
+	for _, tt := range tests {
+		if got := MergeParams(defaults, tt.overrides); !slices.Equal(got, tt.want) {
+			t.Errorf("MergeParams(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestJobIDsKeepSubSecondPrecision(t *testing.T) {
+	second := time.Unix(1700000000, 0)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{second, "echo_1700000000"},
+		{second.Add(250 * time.Millisecond), "echo_1700000000.250"},
+		{second.Add(750 * time.Millisecond), "echo_1700000000.750"},
+		{second.Add(5 * time.Millisecond), "echo_1700000000.005"},
+	}
+	for _, tt := range tests {
+		if got := NewJob("echo", nil, tt.at).ID; got != tt.want {
+			t.Errorf("job ID at %s = %q, want %q", tt.at.Format(time.StampMilli), got, tt.want)
+		}
+	}
+	if JobScore(second.Add(250*time.Millisecond)) >= JobScore(second.Add(750*time.Millisecond)) {
+		t.Fatal("jobs within the same second are not ordered by their score")
+	}
+}
+
+func TestIsOverdueAllowsTolerance(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		scheduledAt time.Time
+		want        bool
+	}{
+		{now.Add(time.Minute), false},
+		{now.Add(-5 * time.Second), false},
+		{now.Add(-time.Minute), true},
+	}
+	for _, tt := range tests {
+		job := NewJob("work", nil, tt.scheduledAt)
+		if got := job.IsOverdue(15 * time.Second); got != tt.want {
+			t.Errorf("job due %s ago overdue = %v, want %v", now.Sub(tt.scheduledAt).Round(time.Second), got, tt.want)
+		}
+	}
+	if !NewJob("work", nil, now.Add(-time.Second)).IsOverdue(0) {
+		t.Error("job past its scheduled time is on time without tolerance")
+	}
+}
+
+func TestSeparateOutputKeepsDetailsSmall(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestRedis(t)
+	output := strings.Repeat("large output line\n", 1000)
+
+	separate := NewJob("work", nil, time.Now())
+	separate.Start()
+	separate.Output = output
+	separate.Complete()
+	if err := separate.UpdateInRedisSeparateOutput(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := server.Get(fmt.Sprintf(JobDetailsKey, separate.ID)); strings.Contains(raw, "large output") {
+		t.Fatalf("details include the output payload: %d bytes", len(raw))
+	}
+	if stored := loadJob(t, client, separate.ID); stored.Status != Success || stored.Output != "" {
+		t.Fatalf("details loaded as %s with %d bytes of output, want success without output", stored.Status, len(stored.Output))
+	}
+
+	inline := NewJob("work", nil, time.Now().Add(time.Second))
+	inline.Output = "inline output"
+	if err := inline.UpdateInRedis(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range []*Job{separate, inline} {
+		if got, err := GetJobOutput(ctx, client, job.ID); err != nil || got != job.Output {
+			t.Fatalf("GetJobOutput(%s) = %d bytes, %v, want %d bytes", job.ID, len(got), err, len(job.Output))
+		}
+	}
+	if _, err := GetJobOutput(ctx, client, "work_1"); err != redis.Nil {
+		t.Fatalf("output of a missing job returned %v, want redis.Nil", err)
+	}
+}
@@ -0,0 +1,166 @@
+//go:build k8s
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// k8sJobPollInterval is how often the Job status is checked while waiting for completion
+const k8sJobPollInterval = 2 * time.Second
+
+// K8sJobCommand implements a command that runs a one-off Kubernetes Job
+type K8sJobCommand struct {
+	id        string
+	schedule  string
+	namespace string
+	image     string
+	args      []string
+	timeout   time.Duration
+	client    kubernetes.Interface
+}
+
+// NewK8sJobCommand creates a new K8sJobCommand using the in-cluster config,
+// falling back to the default kubeconfig when not running inside a cluster
+func NewK8sJobCommand(id, schedule, namespace, image string, args []string, timeout time.Duration) (*K8sJobCommand, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		restConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{},
+		).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+		}
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return NewK8sJobCommandWithClient(client, id, schedule, namespace, image, args, timeout), nil
+}
+
+// NewK8sJobCommandWithClient creates a new K8sJobCommand using the given client
+func NewK8sJobCommandWithClient(client kubernetes.Interface, id, schedule, namespace, image string, args []string, timeout time.Duration) *K8sJobCommand {
+	return &K8sJobCommand{
+		id:        id,
+		schedule:  schedule,
+		namespace: namespace,
+		image:     image,
+		args:      args,
+		timeout:   timeout,
+		client:    client,
+	}
+}
+
+// ID returns the command identifier
+func (c *K8sJobCommand) ID() string {
+	return c.id
+}
+
+// Description returns the command description
+func (c *K8sJobCommand) Description() string {
+	return fmt.Sprintf("Run Kubernetes Job from image %s in namespace %s", c.image, c.namespace)
+}
+
+// Execute creates the Kubernetes Job and waits for it to complete or time out
+func (c *K8sJobCommand) Execute(params []string) error {
+	args := c.args
+	if len(params) > 0 {
+		args = params
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: c.id + "-",
+			Namespace:    c.namespace,
+			Labels:       map[string]string{"app.kubernetes.io/managed-by": "schedulerx"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:  c.id,
+							Image: c.image,
+							Args:  args,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	created, err := c.client.BatchV1().Jobs(c.namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes job: %w", err)
+	}
+
+	ticker := time.NewTicker(k8sJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := c.client.BatchV1().Jobs(c.namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get kubernetes job %s: %w", created.Name, err)
+		}
+		if current.Status.Succeeded > 0 {
+			fmt.Printf("kubernetes job %s succeeded\n", created.Name)
+			return nil
+		}
+		if current.Status.Failed > 0 {
+			return fmt.Errorf("kubernetes job %s failed with exit code %d", created.Name, c.exitCode(ctx, created.Name))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("kubernetes job %s did not finish within %s: %w", created.Name, c.timeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// exitCode returns the exit code of the Job's terminated container, -1 if it cannot be determined
+func (c *K8sJobCommand) exitCode(ctx context.Context, jobName string) int32 {
+	pods, err := c.client.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + jobName,
+	})
+	if err != nil {
+		return -1
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil {
+				return status.State.Terminated.ExitCode
+			}
+		}
+	}
+	return -1
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *K8sJobCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.args, nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *K8sJobCommand) Parameters() []string {
+	return c.args
+}
@@ -0,0 +1,96 @@
+//go:build k8s
+
+package command
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeJobClient returns a fake clientset that names created Jobs like the API server
+// does and lets finish set their status, nil leaves them running
+func fakeJobClient(finish func(job *batchv1.Job)) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		job.Name = job.GenerateName + "x7k2p"
+		if finish != nil {
+			finish(job)
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+// createdJob returns the only Job created through client
+func createdJob(t *testing.T, client *fake.Clientset) batchv1.Job {
+	t.Helper()
+	jobs, err := client.BatchV1().Jobs("batch").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Fatalf("%d jobs created, want 1", len(jobs.Items))
+	}
+	return jobs.Items[0]
+}
+
+func TestK8sJobSucceeds(t *testing.T) {
+	client := fakeJobClient(func(job *batchv1.Job) { job.Status.Succeeded = 1 })
+	cmd := NewK8sJobCommandWithClient(client, "report", "", "batch", "reporter:1.0", []string{"--daily"}, time.Minute)
+
+	if err := cmd.Execute([]string{"--weekly"}); err != nil {
+		t.Fatal(err)
+	}
+
+	job := createdJob(t, client)
+	if !strings.HasPrefix(job.Name, "report-") || job.Labels["app.kubernetes.io/managed-by"] != "schedulerx" {
+		t.Fatalf("job %s has labels %v, want a report- name managed by schedulerx", job.Name, job.Labels)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if container.Image != "reporter:1.0" || !slices.Equal(container.Args, []string{"--weekly"}) {
+		t.Fatalf("container runs %s %v, want reporter:1.0 with the job's params", container.Image, container.Args)
+	}
+	if job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever || *job.Spec.BackoffLimit != 0 {
+		t.Fatal("job may be retried by Kubernetes, retries belong to the scheduler")
+	}
+}
+
+func TestK8sJobFailureReportsExitCode(t *testing.T) {
+	client := fakeJobClient(func(job *batchv1.Job) { job.Status.Failed = 1 })
+	_, err := client.CoreV1().Pods("batch").Create(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-x7k2p-pod", Namespace: "batch", Labels: map[string]string{"job-name": "report-x7k2p"}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3}},
+		}}},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewK8sJobCommandWithClient(client, "report", "", "batch", "reporter:1.0", nil, time.Minute)
+
+	err = cmd.Execute(nil)
+	if err == nil || !strings.Contains(err.Error(), "exit code 3") {
+		t.Fatalf("Execute = %v, want the failure with exit code 3", err)
+	}
+}
+
+func TestK8sJobTimesOut(t *testing.T) {
+	client := fakeJobClient(nil)
+	cmd := NewK8sJobCommandWithClient(client, "report", "", "batch", "reporter:1.0", nil, 100*time.Millisecond)
+
+	err := cmd.Execute(nil)
+	if err == nil || !strings.Contains(err.Error(), "did not finish within 100ms") {
+		t.Fatalf("Execute = %v, want a timeout", err)
+	}
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PipelineStep is one step of a PipelineCommand
+type PipelineStep struct {
+	Command Command
+	Params  []string // Merged over the command's default parameters, nil runs it with its defaults
+}
+
+// PipelineCommand runs its steps in order, e.g. dump a database, compress the
+// dump and upload it, and stops at the first step that fails
+type PipelineCommand struct {
+	id          string
+	description string
+	schedule    string
+	steps       []PipelineStep
+}
+
+// NewPipelineCommand creates a new PipelineCommand running steps on schedule.
+// An empty schedule means the pipeline only runs when triggered.
+func NewPipelineCommand(id, description, schedule string, steps ...PipelineStep) *PipelineCommand {
+	return &PipelineCommand{
+		id:          id,
+		description: description,
+		schedule:    schedule,
+		steps:       steps,
+	}
+}
+
+// ID returns the command identifier
+func (c *PipelineCommand) ID() string {
+	return c.id
+}
+
+// Description returns the command description
+func (c *PipelineCommand) Description() string {
+	return c.description
+}
+
+// Execute runs the pipeline and prints its output
+func (c *PipelineCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput runs the pipeline and returns the output of every step that ran
+func (c *PipelineCommand) ExecuteWithOutput(params []string) (string, error) {
+	return c.ExecuteContext(context.Background(), params)
+}
+
+// ExecuteContext runs the steps in order until one fails or ctx is done. The output
+// of each step that ran follows a "[step i/n: command]" header. The error of a failed
+// step names the step and wraps the step's error, so its exit code is kept.
+func (c *PipelineCommand) ExecuteContext(ctx context.Context, params []string) (string, error) {
+	var output strings.Builder
+	for i, step := range c.steps {
+		if step.Command == nil {
+			return output.String(), fmt.Errorf("step %d/%d has no command", i+1, len(c.steps))
+		}
+		if err := ctx.Err(); err != nil {
+			return output.String(), fmt.Errorf("pipeline stopped before step %d/%d (%s): %w", i+1, len(c.steps), step.Command.ID(), err)
+		}
+
+		fmt.Fprintf(&output, "[step %d/%d: %s]\n", i+1, len(c.steps), step.Command.ID())
+		stepOutput, err := runStep(ctx, step)
+		output.WriteString(stepOutput)
+		if err != nil {
+			return output.String(), fmt.Errorf("step %d/%d (%s) failed: %w", i+1, len(c.steps), step.Command.ID(), err)
+		}
+	}
+	return output.String(), nil
+}
+
+// runStep runs a single step, through the most capable interface its command implements.
+// Steps with a timeout of their own can only be stopped when they support cancellation.
+func runStep(ctx context.Context, step PipelineStep) (string, error) {
+	params := MergeParams(step.Command.Parameters(), step.Params)
+	switch cmd := step.Command.(type) {
+	case ContextCommand:
+		if timeout := CommandTimeout(step.Command, params); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return cmd.ExecuteContext(ctx, params)
+	case OutputCommand:
+		return cmd.ExecuteWithOutput(params)
+	default:
+		return "", step.Command.Execute(params)
+	}
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *PipelineCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command, the steps carry their own
+func (c *PipelineCommand) Parameters() []string {
+	return []string{}
+}
+
+// RequiredCapabilities returns the capabilities needed by any of the steps
+func (c *PipelineCommand) RequiredCapabilities() []string {
+	var capabilities []string
+	for _, step := range c.steps {
+		if step.Command != nil {
+			capabilities = append(capabilities, RequiredCapabilities(step.Command, nil)...)
+		}
+	}
+	return capabilities
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordingStep returns a step whose command records that it ran and fails with err
+func recordingStep(id string, ran *[]string, err error) PipelineStep {
+	return PipelineStep{Command: NewFuncCommand(id, "test step", "", func(ctx context.Context, params []string) (string, error) {
+		*ran = append(*ran, id+" "+strings.Join(params, ","))
+		return id + " output\n", err
+	}, "default"), Params: []string{"step-param"}}
+}
+
+func TestPipelineRunsAllSteps(t *testing.T) {
+	var ran []string
+	pipeline := NewPipelineCommand("backup", "Backup", "", recordingStep("dump", &ran, nil), recordingStep("compress", &ran, nil), recordingStep("upload", &ran, nil))
+
+	output, err := pipeline.ExecuteWithOutput(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dump step-param", "compress step-param", "upload step-param"}; !slices.Equal(ran, want) {
+		t.Fatalf("steps ran as %q, want %q", ran, want)
+	}
+	want := "[step 1/3: dump]\ndump output\n[step 2/3: compress]\ncompress output\n[step 3/3: upload]\nupload output\n"
+	if output != want {
+		t.Fatalf("output %q, want %q", output, want)
+	}
+}
+
+func TestPipelineStopsAtFailingStep(t *testing.T) {
+	var ran []string
+	failure := errors.New("disk full")
+	pipeline := NewPipelineCommand("backup", "Backup", "", recordingStep("dump", &ran, nil), recordingStep("compress", &ran, failure), recordingStep("upload", &ran, nil))
+
+	output, err := pipeline.ExecuteWithOutput(nil)
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "step 2/3 (compress) failed") {
+		t.Fatalf("pipeline failed with %v, want step 2 named and its error wrapped", err)
+	}
+	if len(ran) != 2 {
+		t.Fatalf("steps ran as %q, want the pipeline to stop after compress", ran)
+	}
+	if !strings.HasSuffix(output, "[step 2/3: compress]\ncompress output\n") {
+		t.Fatalf("output %q does not end with the failing step's output", output)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package command
+
+import "os/exec"
+
+// setProcessGroup is a no-op on platforms without process groups, cancellation kills only the process itself
+func setProcessGroup(cmd *exec.Cmd) {}
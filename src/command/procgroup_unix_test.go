@@ -0,0 +1,48 @@
+//go:build unix
+
+package command
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// processGone reports whether pid has exited, counting zombies awaiting their reaper as gone
+func processGone(pid int) bool {
+	if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+		return true
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	// The state follows the parenthesised command name, e.g. "123 (sleep) Z ..."
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] == "Z"
+}
+
+func TestTimeoutKillsChildProcesses(t *testing.T) {
+	// The shell prints the PID of a child it spawned, then waits for it
+	output, err := NewShellCommandWithTimeout("sleep 30 & echo $!; wait", 300*time.Millisecond).ExecuteWithOutput(nil)
+	if err == nil {
+		t.Fatal("command outlived its timeout")
+	}
+	pid, convErr := strconv.Atoi(strings.TrimSpace(output))
+	if convErr != nil {
+		t.Fatalf("no child PID in output %q: %v", output, convErr)
+	}
+	t.Cleanup(func() { syscall.Kill(pid, syscall.SIGKILL) })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !processGone(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("child process %d outlived the timed out command", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParamType is the type of a positional command parameter
+type ParamType string
+
+const (
+	ParamString ParamType = "string"
+	ParamInt    ParamType = "int"
+	ParamFloat  ParamType = "float"
+	ParamBool   ParamType = "bool"
+)
+
+// ParamSpec describes a single positional command parameter
+type ParamSpec struct {
+	Name     string    `json:"name"`
+	Type     ParamType `json:"type"`
+	Required bool      `json:"required"`
+	Default  string    `json:"default,omitempty"`
+}
+
+// SchemaProvider is implemented by commands that declare typed parameters
+type SchemaProvider interface {
+	// ParamSchema returns the ordered parameter specs of the command
+	ParamSchema() []ParamSpec
+}
+
+// ParamSchemaOf returns the parameter schema of cmd, reporting false when it declares none
+func ParamSchemaOf(cmd Command) ([]ParamSpec, bool) {
+	provider, ok := cmd.(SchemaProvider)
+	if !ok {
+		return nil, false
+	}
+	return provider.ParamSchema(), true
+}
+
+// CoerceParams validates params against the schema, fills in defaults for
+// missing optional params and normalizes every value to its type's canonical form
+func CoerceParams(schema []ParamSpec, params []string) ([]string, error) {
+	if len(params) > len(schema) {
+		return nil, fmt.Errorf("too many params: expected at most %d, got %d", len(schema), len(params))
+	}
+
+	coerced := make([]string, 0, len(schema))
+	for i, spec := range schema {
+		value := spec.Default
+		if i < len(params) && params[i] != "" {
+			value = params[i]
+		}
+		if value == "" {
+			if spec.Required {
+				return nil, fmt.Errorf("missing required param %s", spec.Name)
+			}
+			coerced = append(coerced, value)
+			continue
+		}
+
+		normalized, err := coerceValue(spec.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid param %s: %w", spec.Name, err)
+		}
+		coerced = append(coerced, normalized)
+	}
+
+	return coerced, nil
+}
+
+// CoerceCommandParams applies the command's schema to params if it declares one,
+// otherwise params are returned unchanged
+func CoerceCommandParams(cmd Command, params []string) ([]string, error) {
+	provider, ok := cmd.(SchemaProvider)
+	if !ok {
+		return params, nil
+	}
+	return CoerceParams(provider.ParamSchema(), params)
+}
+
+// Param returns the positional param at index i, or fallback when params is too short or the param is empty
+func Param(params []string, i int, fallback string) string {
+	if i < 0 || i >= len(params) || params[i] == "" {
+		return fallback
+	}
+	return params[i]
+}
+
+// RequiredParam returns Param(params, i, fallback), failing with an error naming the
+// param when neither params nor fallback provide a value
+func RequiredParam(params []string, i int, name, fallback string) (string, error) {
+	value := Param(params, i, fallback)
+	if value == "" {
+		return "", fmt.Errorf("missing required param %s", name)
+	}
+	return value, nil
+}
+
+// IntParam parses Param(params, i, fallback) as an int, failing with an error naming
+// the param when it is missing or not a number
+func IntParam(params []string, i int, name, fallback string) (int, error) {
+	value, err := RequiredParam(params, i, name, fallback)
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid param %s: expected int, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+// FloatParam parses Param(params, i, fallback) as a float, failing with an error naming
+// the param when it is missing or not a number
+func FloatParam(params []string, i int, name, fallback string) (float64, error) {
+	value, err := RequiredParam(params, i, name, fallback)
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid param %s: expected float, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+// coerceValue parses value as the given type and formats it back canonically
+func coerceValue(paramType ParamType, value string) (string, error) {
+	switch paramType {
+	case ParamString, "":
+		return value, nil
+	case ParamInt:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("expected int, got %q", value)
+		}
+		return strconv.Itoa(parsed), nil
+	case ParamFloat:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("expected float, got %q", value)
+		}
+		return strconv.FormatFloat(parsed, 'g', -1, 64), nil
+	case ParamBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("expected bool, got %q", value)
+		}
+		return strconv.FormatBool(parsed), nil
+	default:
+		return "", fmt.Errorf("unknown param type %s", paramType)
+	}
+}
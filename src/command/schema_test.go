@@ -0,0 +1,96 @@
+package command
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+func TestPositionalParamAccessors(t *testing.T) {
+	params := []string{"a", "", "3", "0.5"}
+
+	if got := Param(params, 0, "x"); got != "a" {
+		t.Fatalf("Param(0) = %q, want a", got)
+	}
+	if got := Param(params, 1, "x"); got != "x" {
+		t.Fatalf("Param of an empty param = %q, want the fallback", got)
+	}
+	if got := Param(params, 9, "x"); got != "x" {
+		t.Fatalf("Param out of range = %q, want the fallback", got)
+	}
+	if got := Param(params, -1, "x"); got != "x" {
+		t.Fatalf("Param(-1) = %q, want the fallback", got)
+	}
+	if _, err := RequiredParam(params, 1, "name", ""); err == nil || err.Error() != "missing required param name" {
+		t.Fatalf("RequiredParam of a missing param = %v", err)
+	}
+	if got, err := IntParam(params, 2, "count", ""); err != nil || got != 3 {
+		t.Fatalf("IntParam = %d, %v, want 3", got, err)
+	}
+	if _, err := IntParam(params, 0, "count", ""); err == nil {
+		t.Fatal("IntParam accepted a non-number")
+	}
+	if got, err := FloatParam(params, 3, "interval", ""); err != nil || got != 0.5 {
+		t.Fatalf("FloatParam = %g, %v, want 0.5", got, err)
+	}
+	if got, err := FloatParam(params, 9, "interval", "1.5"); err != nil || got != 1.5 {
+		t.Fatalf("FloatParam out of range = %g, %v, want the fallback 1.5", got, err)
+	}
+	if _, err := FloatParam(params, 0, "interval", ""); err == nil {
+		t.Fatal("FloatParam accepted a non-number")
+	}
+}
+
+func TestCoerceParams(t *testing.T) {
+	schema := NewPingCommand("localhost", 4, 1).ParamSchema()
+
+	coerced, err := CoerceParams(schema, []string{"example.com", "03", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"example.com", "3", "2"}; !slices.Equal(coerced, want) {
+		t.Fatalf("CoerceParams = %q, want %q", coerced, want)
+	}
+
+	coerced, err = CoerceParams(schema, []string{"example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"example.com", "4", "1"}; !slices.Equal(coerced, want) {
+		t.Fatalf("CoerceParams with defaults = %q, want %q", coerced, want)
+	}
+
+	for _, params := range [][]string{
+		nil,                                  // missing required host
+		{"example.com", "four"},              // count is not an int
+		{"example.com", "4", "fast"},         // interval is not a float
+		{"example.com", "4", "1", "surplus"}, // more params than the schema declares
+	} {
+		if _, err := CoerceParams(schema, params); err == nil {
+			t.Fatalf("CoerceParams(%q) accepted invalid params", params)
+		}
+	}
+}
+
+func TestCommandsRejectMissingParams(t *testing.T) {
+	config := &utils.Config{}
+	tests := []struct {
+		cmd  OutputCommand
+		want string
+	}{
+		{NewListFilesCommand(""), "missing required param directory"},
+		{NewDiskUsageCommand(""), "missing required param path"},
+		{NewPingCommand("", 4, 1), "missing required param host"},
+		{NewEmailCommand(config), "recipient"},
+	}
+	for _, tt := range tests {
+		for _, params := range [][]string{nil, {}, {""}} {
+			output, err := tt.cmd.ExecuteWithOutput(params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("%T with params %q = %q, %v, want an error containing %q", tt.cmd, params, output, err, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+const (
+	// webhookTimeout bounds a single delivery attempt
+	webhookTimeout = 30 * time.Second
+	// webhookMaxBackoff caps the delay between two delivery attempts
+	webhookMaxBackoff = time.Minute
+	// webhookResponseLimit caps how much of a response body is kept in the output
+	webhookResponseLimit = 4 << 10
+)
+
+// WebhookCommand POSTs a payload to a URL for reliable delivery. Failed attempts
+// are retried with exponential backoff, the job only fails once every attempt did.
+type WebhookCommand struct {
+	url         string
+	payload     string
+	schedule    string
+	maxAttempts int
+	backoff     time.Duration
+	client      *http.Client
+}
+
+// NewWebhookCommand creates a new WebhookCommand from the WEBHOOK_* settings
+func NewWebhookCommand(config *utils.Config) *WebhookCommand {
+	return &WebhookCommand{
+		url:         config.WebhookURL,
+		payload:     config.WebhookPayload,
+		schedule:    config.WebhookSchedule,
+		maxAttempts: max(config.WebhookMaxAttempts, 1),
+		backoff:     config.WebhookRetryBackoff,
+		client:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// ID returns the command identifier
+func (c *WebhookCommand) ID() string {
+	return "webhook"
+}
+
+// Description returns the command description
+func (c *WebhookCommand) Description() string {
+	return "POST a payload to a webhook, retrying with backoff"
+}
+
+// Execute delivers the webhook and prints the result
+func (c *WebhookCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput delivers the webhook and returns the attempts made and the final status
+func (c *WebhookCommand) ExecuteWithOutput(params []string) (string, error) {
+	return c.ExecuteContext(context.Background(), params)
+}
+
+// ExecuteContext delivers the webhook to the configured URL, params are payload and max attempts.
+// The URL is not a param, so jobs triggered through the API cannot make the pod request arbitrary addresses.
+// Network errors, 408, 429 and 5xx responses are retried, other non-2xx responses
+// fail right away. The output records the number of attempts made.
+func (c *WebhookCommand) ExecuteContext(ctx context.Context, params []string) (string, error) {
+	params, err := CoerceParams(c.ParamSchema(), params)
+	if err != nil {
+		return "", err
+	}
+	url, payload := c.url, Param(params, 0, "")
+	maxAttempts, err := c.attempts(params)
+	if err != nil {
+		return "", err
+	}
+
+	var output string
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		output, retryable, err = c.deliver(ctx, url, payload)
+		output = fmt.Sprintf("url=%s attempts=%d %s\n", url, attempt, output)
+		if err == nil {
+			return output, nil
+		}
+		if !retryable || attempt >= maxAttempts {
+			return output, fmt.Errorf("webhook delivery failed after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return output, fmt.Errorf("webhook delivery stopped after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(c.retryDelay(attempt)):
+		}
+	}
+}
+
+// deliver makes a single delivery attempt, reporting whether a failure may succeed on retry
+func (c *WebhookCommand) deliver(ctx context.Context, url, payload string) (output string, retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(payload))
+	if err != nil {
+		return "", false, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+
+	output = fmt.Sprintf("status=%d\n%s", resp.StatusCode, body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return output, false, nil
+	}
+	retryable = resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return output, retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// retryDelay returns the backoff before the attempt following the given one, doubling each time
+func (c *WebhookCommand) retryDelay(attempt int) time.Duration {
+	delay := c.backoff
+	for i := 1; i < attempt && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxBackoff)
+}
+
+// ParamSchema returns the typed parameters of the webhook command
+func (c *WebhookCommand) ParamSchema() []ParamSpec {
+	return []ParamSpec{
+		{Name: "payload", Type: ParamString, Default: c.payload},
+		{Name: "max_attempts", Type: ParamInt, Default: strconv.Itoa(c.maxAttempts)},
+	}
+}
+
+// Validate checks the params against the command's schema
+func (c *WebhookCommand) Validate(params []string) error {
+	_, err := CoerceParams(c.ParamSchema(), params)
+	return err
+}
+
+// attempts returns the number of delivery attempts params allow, at least one
+func (c *WebhookCommand) attempts(params []string) (int, error) {
+	maxAttempts, err := IntParam(params, 1, "max_attempts", strconv.Itoa(c.maxAttempts))
+	if err != nil {
+		return 0, err
+	}
+	return max(maxAttempts, 1), nil
+}
+
+// Timeout allows for every attempt of a run with the default params and the backoff between them
+func (c *WebhookCommand) Timeout() time.Duration {
+	return c.TimeoutFor(nil)
+}
+
+// TimeoutFor allows for every attempt of a run with params and the backoff between them
+func (c *WebhookCommand) TimeoutFor(params []string) time.Duration {
+	maxAttempts, err := c.attempts(params)
+	if err != nil {
+		// The run fails on the same error before making a request
+		return webhookTimeout
+	}
+	total := time.Duration(maxAttempts) * webhookTimeout
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		total += c.retryDelay(attempt)
+	}
+	return total
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *WebhookCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *WebhookCommand) Parameters() []string {
+	return []string{c.payload, strconv.Itoa(c.maxAttempts)}
+}
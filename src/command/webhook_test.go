@@ -0,0 +1,87 @@
+package command
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// newTestWebhook returns a webhook command delivering to url without waiting between attempts
+func newTestWebhook(url string, maxAttempts int) *WebhookCommand {
+	return NewWebhookCommand(&utils.Config{
+		WebhookURL:          url,
+		WebhookPayload:      "{}",
+		WebhookMaxAttempts:  maxAttempts,
+		WebhookRetryBackoff: time.Millisecond,
+	})
+}
+
+func TestWebhookRetriesUntilDelivered(t *testing.T) {
+	var payloads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, string(body))
+		if len(payloads) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	output, err := newTestWebhook(server.URL, 5).ExecuteWithOutput([]string{`{"event":"test"}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "attempts=3") {
+		t.Fatalf("output %q does not record 3 attempts", output)
+	}
+	if len(payloads) != 3 || payloads[2] != `{"event":"test"}` {
+		t.Fatalf("server received %q", payloads)
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if _, err := newTestWebhook(server.URL, 5).ExecuteWithOutput(nil); err == nil {
+		t.Fatal("delivery answered with 400 succeeded")
+	}
+	if requests != 1 {
+		t.Fatalf("400 response was retried, %d requests made", requests)
+	}
+}
+
+func TestWebhookOnlyDeliversToConfiguredURL(t *testing.T) {
+	c := newTestWebhook("http://127.0.0.1:1/hook", 1)
+	for _, spec := range c.ParamSchema() {
+		if spec.Name == "url" {
+			t.Fatal("url must not be a param, jobs could POST to any address")
+		}
+	}
+	if err := c.Validate([]string{"{}", "http://169.254.169.254/"}); err == nil {
+		t.Fatal("a URL was accepted as the max_attempts param")
+	}
+}
+
+func TestWebhookTimeoutFollowsEffectiveAttempts(t *testing.T) {
+	c := newTestWebhook("http://127.0.0.1:1/hook", 2)
+
+	if got := CommandTimeout(c, nil); got != c.Timeout() {
+		t.Fatalf("timeout with default params is %s, want %s", got, c.Timeout())
+	}
+	if got := CommandTimeout(c, []string{"{}", "1"}); got != webhookTimeout {
+		t.Fatalf("timeout for a single attempt is %s, want %s", got, webhookTimeout)
+	}
+	if got, want := CommandTimeout(c, []string{"{}", "10"}), 10*webhookTimeout; got < want {
+		t.Fatalf("timeout for 10 attempts is %s, want at least %s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// writeTimeout bounds a single sink write
+const writeTimeout = 30 * time.Second
+
+// Sink stores finished jobs outside of Redis for long-term retention
+type Sink interface {
+	// Write persists a single finished job
+	Write(ctx context.Context, job *command.Job) error
+}
+
+// FileSink writes each job as a JSON file into a directory per day, e.g. <dir>/2024-01-31/<job id>.json
+type FileSink struct {
+	dir string
+}
+
+// NewFileSink creates a new FileSink rooted at dir
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{dir: dir}
+}
+
+// Write stores the job under the day it finished on (UTC)
+func (f *FileSink) Write(ctx context.Context, job *command.Job) error {
+	finishedAt := job.ScheduledAt
+	if job.FinishedAt != nil {
+		finishedAt = *job.FinishedAt
+	}
+
+	dir := filepath.Join(f.dir, finishedAt.UTC().Format(time.DateOnly))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory %s: %w", dir, err)
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", job.ID, err)
+	}
+	path := filepath.Join(dir, job.ID+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// Exporter hands finished jobs to a sink in the background. Exporting is best
+// effort: jobs are dropped with a warning when the queue is full or the write fails.
+type Exporter struct {
+	sink   Sink
+	logger *utils.StandardLogger
+	queue  chan command.Job
+}
+
+// NewExporter creates a new Exporter buffering up to queueSize jobs
+func NewExporter(sink Sink, logger *utils.StandardLogger, queueSize int) *Exporter {
+	return &Exporter{
+		sink:   sink,
+		logger: logger,
+		queue:  make(chan command.Job, queueSize),
+	}
+}
+
+// Export queues a copy of the job without blocking
+func (e *Exporter) Export(job *command.Job) {
+	select {
+	case e.queue <- *job:
+	default:
+		job.Logger(e.logger).Warn("Export queue full, dropping job export")
+	}
+}
+
+// Run writes queued jobs to the sink until ctx is cancelled
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-e.queue:
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			if err := e.sink.Write(writeCtx, &job); err != nil {
+				job.Logger(e.logger).Warn("Failed to export job", "error", err)
+			}
+			cancel()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"go.uber.org/zap"
+)
+
+// finishedJob returns a successful job that finished at the given time
+func finishedJob(finishedAt time.Time) *command.Job {
+	job := command.NewJob("report", []string{"daily"}, finishedAt.Add(-time.Minute))
+	job.Start()
+	job.Complete()
+	job.FinishedAt = &finishedAt
+	job.Output = "42 rows"
+	return job
+}
+
+// readExport decodes the exported file of a job
+func readExport(t *testing.T, path string) *command.Job {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var job command.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatalf("export %s is not a JSON job: %v", path, err)
+	}
+	return &job
+}
+
+func TestFileSinkPartitionsByDay(t *testing.T) {
+	dir := t.TempDir()
+	finishedAt := time.Date(2026, 3, 14, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+	job := finishedJob(finishedAt)
+
+	if err := NewFileSink(dir).Write(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:30 at UTC-2 is the next day in UTC
+	exported := readExport(t, filepath.Join(dir, "2026-03-15", job.ID+".json"))
+	if exported.ID != job.ID || exported.Status != command.Success || exported.Output != "42 rows" || exported.Params[0] != "daily" {
+		t.Fatalf("exported job = %+v, want the finished job", exported)
+	}
+}
+
+func TestExporterWritesInBackground(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewExporter(NewFileSink(dir), &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exporter.Run(ctx)
+
+	job := finishedJob(time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC))
+	exporter.Export(job)
+	job.Output = "changed after export"
+
+	path := filepath.Join(dir, "2026-03-14", job.ID+".json")
+	var exported command.Job
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		// The file may be missing or partially written until the exporter is done with it
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &exported) == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("exporter did not write the job")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if exported.Output != "42 rows" {
+		t.Fatalf("exported output %q, want the job as it was when exported", exported.Output)
+	}
+}
+
+func TestExporterDropsJobsWhenQueueIsFull(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewExporter(NewFileSink(dir), &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}, 1)
+
+	// Nothing drains the queue, Export must not block
+	done := make(chan struct{})
+	go func() {
+		for i := range 3 {
+			exporter.Export(finishedJob(time.Date(2026, 3, 14, 12, i, 0, 0, time.UTC)))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Export blocked on a full queue")
+	}
+	if len(exporter.queue) != 1 {
+		t.Fatalf("%d jobs queued, want the queue size of 1", len(exporter.queue))
+	}
+}
@@ -0,0 +1,57 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// ErrNoLeader is returned while no live pod is registered
+var ErrNoLeader = errors.New("no live pods, leader unknown")
+
+// detectAddress returns the configured POD_ADDRESS, falling back to the hostname
+// and HTTP_PORT. It is empty when the HTTP API is disabled or the hostname is unknown.
+func (pm *PodManager) detectAddress() string {
+	if pm.config.PodAddress != "" {
+		return pm.config.PodAddress
+	}
+	if pm.config.HTTPPort <= 0 {
+		return ""
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		pm.logger.Warn("Failed to detect pod address, set POD_ADDRESS", "error", err)
+		return ""
+	}
+	return net.JoinHostPort(hostname, strconv.Itoa(pm.config.HTTPPort))
+}
+
+// LeaderInfo returns the registry entry of the current leader, ErrNoLeader while no pod is alive
+func (pm *PodManager) LeaderInfo(ctx context.Context) (PodInfo, error) {
+	pods, err := pm.ListPods(ctx)
+	if err != nil {
+		return PodInfo{}, err
+	}
+	for _, pod := range pods {
+		if pod.IsLeader {
+			return pod, nil
+		}
+	}
+	return PodInfo{}, ErrNoLeader
+}
+
+// GetLeaderAddress returns the host:port of the leader's HTTP API
+func (pm *PodManager) GetLeaderAddress(ctx context.Context) (string, error) {
+	info, err := pm.LeaderInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if info.Address == "" {
+		return "", fmt.Errorf("leader %s has no registered address, set POD_ADDRESS", info.ID)
+	}
+	return info.Address, nil
+}
@@ -0,0 +1,81 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDetectAddress(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("hostname is unknown")
+	}
+	tests := []struct {
+		name       string
+		podAddress string
+		httpPort   int
+		want       string
+	}{
+		{"configured", "10.0.0.1:9000", 8080, "10.0.0.1:9000"},
+		{"detected", "", 8080, net.JoinHostPort(hostname, strconv.Itoa(8080))},
+		{"api disabled", "", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.PodAddress, config.HTTPPort = tt.podAddress, tt.httpPort
+			pm, _ := newTestPodManager(t, config)
+			if got := pm.detectAddress(); got != tt.want {
+				t.Fatalf("detectAddress = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaderAddressRoundTripsThroughRegistry(t *testing.T) {
+	ctx := context.Background()
+	leaderPod, server := newTestPodManager(t, testConfig())
+	leaderPod.info.StartTime = time.Now().Add(-time.Hour)
+	leaderPod.info.Address = "10.0.0.1:8080"
+	follower := podManagerOn(t, server, "follower-pod", testConfig())
+	follower.info.Address = "10.0.0.2:8080"
+	for _, pm := range []*PodManager{leaderPod, follower} {
+		if err := pm.registerPod(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pods, err := follower.ListPods(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addresses := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		addresses[pod.ID] = pod.Address
+	}
+	if addresses[testPodID] != "10.0.0.1:8080" || addresses["follower-pod"] != "10.0.0.2:8080" {
+		t.Fatalf("registered addresses %v, want both pods' addresses", addresses)
+	}
+
+	if address, err := follower.GetLeaderAddress(ctx); err != nil || address != "10.0.0.1:8080" {
+		t.Fatalf("GetLeaderAddress = %q, %v, want the leader's address", address, err)
+	}
+
+	leaderPod.info.Address = ""
+	if err := leaderPod.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := follower.GetLeaderAddress(ctx); err == nil {
+		t.Fatal("GetLeaderAddress succeeded for a leader without an address")
+	}
+
+	server.Del(podRegistryKey)
+	if _, err := follower.GetLeaderAddress(ctx); !errors.Is(err, ErrNoLeader) {
+		t.Fatalf("GetLeaderAddress without pods = %v, want %v", err, ErrNoLeader)
+	}
+}
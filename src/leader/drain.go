@@ -0,0 +1,23 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ClusterDrainKey is set while the cluster is drained for maintenance
+const ClusterDrainKey = "schedulerx:cluster:draining"
+
+// IsClusterDraining reports whether the cluster is drained for maintenance
+func (pm *PodManager) IsClusterDraining(ctx context.Context) (bool, error) {
+	_, err := pm.client.GetClient().Get(ctx, ClusterDrainKey).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get drain status: %w", err)
+	}
+	return true, nil
+}
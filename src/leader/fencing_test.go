@@ -0,0 +1,53 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestNewLeaderFencesOutPreviousLeader(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	oldLeader := podManagerOn(t, server, "pod-a", testConfig())
+	newLeader := podManagerOn(t, server, "pod-b", testConfig())
+
+	if err := oldLeader.updateFencingToken(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := newLeader.updateFencingToken(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if oldLeader.FencingToken() >= newLeader.FencingToken() {
+		t.Fatalf("new leader got token %d, not above the old leader's %d", newLeader.FencingToken(), oldLeader.FencingToken())
+	}
+
+	// The old leader has not noticed it lost leadership yet and still writes with its token
+	job := command.NewJob("work", nil, time.Now())
+	err := job.StoreInRedisFenced(ctx, oldLeader.client.GetClient(), FencingTokenKey, oldLeader.FencingToken())
+	if !errors.Is(err, command.ErrStaleFencingToken) {
+		t.Fatalf("stale leader's write returned %v, want %v", err, command.ErrStaleFencingToken)
+	}
+	if err := job.StoreInRedisFenced(ctx, newLeader.client.GetClient(), FencingTokenKey, newLeader.FencingToken()); err != nil {
+		t.Fatalf("new leader's write failed: %v", err)
+	}
+
+	// Staying leader keeps the token, stepping down drops it
+	token := newLeader.FencingToken()
+	if err := newLeader.updateFencingToken(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if newLeader.FencingToken() != token {
+		t.Fatalf("token changed from %d to %d while leadership was kept", token, newLeader.FencingToken())
+	}
+	if err := newLeader.updateFencingToken(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	if newLeader.FencingToken() != 0 {
+		t.Fatalf("follower still holds token %d", newLeader.FencingToken())
+	}
+}
@@ -0,0 +1,101 @@
+package leader
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// healthWindow is the number of recent job outcomes a pod's failure rate is computed over
+	healthWindow = 50
+
+	// healthSlots is how many assignment slots a fully healthy pod gets per round-robin
+	// cycle, a degraded pod gets proportionally fewer but never less than one
+	healthSlots = 4
+)
+
+// outcomeWindow keeps the outcomes of the last healthWindow jobs run by this pod
+type outcomeWindow struct {
+	mu       sync.Mutex
+	failures []bool
+}
+
+// record adds the outcome of a job, dropping the oldest beyond healthWindow
+func (w *outcomeWindow) record(failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.failures = append(w.failures, failed)
+	if len(w.failures) > healthWindow {
+		w.failures = w.failures[len(w.failures)-healthWindow:]
+	}
+}
+
+// counts returns the number of recorded jobs and how many of them failed
+func (w *outcomeWindow) counts() (jobs, failures int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, failed := range w.failures {
+		if failed {
+			failures++
+		}
+	}
+	return len(w.failures), failures
+}
+
+// RecordJobOutcome records whether a job run by this pod failed, for its health score (global function)
+func RecordJobOutcome(failed bool) {
+	if instance == nil {
+		return
+	}
+	instance.outcomes.record(failed)
+}
+
+// HealthScore combines the pod's recent failure rate, its load and the freshness of
+// its heartbeat into a score between 0 (unhealthy) and 1 (healthy). Freshness only
+// drops once a heartbeat is overdue, decaying to 0 at staleness.
+func (p PodInfo) HealthScore(ttl, staleness time.Duration) float64 {
+	success := 1.0
+	if p.RecentJobs > 0 {
+		success = 1 - float64(p.RecentFailures)/float64(p.RecentJobs)
+	}
+
+	// A fully loaded pod keeps half its score, load alone does not starve it
+	capacity := 1 - min(max(p.Load, 0), 1)/2
+
+	freshness := 1.0
+	if age := time.Since(p.LastSeen); age > ttl {
+		if staleness > ttl {
+			freshness = max(1-float64(age-ttl)/float64(staleness-ttl), 0)
+		} else {
+			freshness = 0
+		}
+	}
+
+	return success * capacity * freshness
+}
+
+// HealthSlots returns how many assignment slots a pod with the given health score gets
+func HealthSlots(score float64) int {
+	return max(int(score*healthSlots+0.5), 1)
+}
+
+// systemLoad returns the 1 minute load average per CPU, capped at 1, or 0 where it is unavailable
+func systemLoad() float64 {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return min(load/float64(runtime.NumCPU()), 1)
+}
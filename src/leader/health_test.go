@@ -0,0 +1,34 @@
+package leader
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHealthScore(t *testing.T) {
+	ttl, staleness := 30*time.Second, 90*time.Second
+	now := time.Now()
+	tests := []struct {
+		name  string
+		pod   PodInfo
+		want  float64
+		slots int
+	}{
+		{"healthy", PodInfo{LastSeen: now, RecentJobs: 50}, 1, 4},
+		{"no history", PodInfo{LastSeen: now}, 1, 4},
+		{"failing", PodInfo{LastSeen: now, RecentJobs: 50, RecentFailures: 40}, 0.2, 1},
+		{"fully loaded", PodInfo{LastSeen: now, Load: 1}, 0.5, 2},
+		{"overdue heartbeat", PodInfo{LastSeen: now.Add(-60 * time.Second)}, 0.5, 2},
+		{"stale", PodInfo{LastSeen: now.Add(-2 * staleness)}, 0, 1},
+	}
+	for _, tt := range tests {
+		score := tt.pod.HealthScore(ttl, staleness)
+		if math.Abs(score-tt.want) > 0.01 {
+			t.Errorf("%s pod scored %.2f, want %.2f", tt.name, score, tt.want)
+		}
+		if slots := HealthSlots(score); slots != tt.slots {
+			t.Errorf("%s pod gets %d slots, want %d", tt.name, slots, tt.slots)
+		}
+	}
+}
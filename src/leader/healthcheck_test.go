@@ -0,0 +1,64 @@
+package leader
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHealthChecksReleaseJobsOfDeadPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pm, _ := newTestPodManager(t, testConfig())
+	now := time.Now()
+	pods := map[string]PodInfo{
+		testPodID:  {ID: testPodID, StartTime: now, LastSeen: now, Status: "active"},
+		"dead-pod": {ID: "dead-pod", StartTime: now.Add(-time.Hour), LastSeen: now.Add(-time.Hour), Status: "active"},
+	}
+	if err := pm.storePods(ctx, pods); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var released []map[string]bool
+	pm.SetDeadPodJobReleaser(func(ctx context.Context, alivePods map[string]bool) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		released = append(released, alivePods)
+		return 1, nil
+	})
+	calls := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(released)
+	}
+
+	// Followers leave the jobs alone
+	if err := pm.CheckPodHealth(ctx); err != nil || calls() != 0 {
+		t.Fatalf("follower health check = %v and released jobs %d times, want no release", err, calls())
+	}
+
+	pm.setLeading(true)
+	start := time.Now()
+	go pm.startHealthChecks(ctx, 300*time.Millisecond)
+	if calls() != 0 {
+		t.Fatal("jobs were released before the first interval elapsed")
+	}
+	for calls() == 0 {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("health check loop did not release the dead pod's jobs")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Fatalf("jobs were released after %s, want it on the first tick at 300ms", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := map[string]bool{testPodID: true}; !maps.Equal(released[0], want) {
+		t.Fatalf("released jobs with alive pods %v, want %v", released[0], want)
+	}
+}
@@ -2,13 +2,18 @@ package leader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yashkumarverma/schedulerx/src/assignment"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
 	"github.com/yashkumarverma/schedulerx/src/utils"
 	"github.com/yashkumarverma/schedulerx/src/utils/cache"
 )
@@ -17,16 +22,36 @@ const (
 	// Redis key for storing pod information
 	podRegistryKey = "schedulerx:pods"
 
-	// TTL for pod presence. if not heard for 5 minutes, assume pod to be dead
-	podTTL = 5 * time.Second
+	// defaultPodTTL is used when POD_TTL is not set, see PodTTL
+	defaultPodTTL = 5 * time.Second
+
+	// FencingTokenKey holds the latest fencing token handed out to a leader
+	FencingTokenKey = "schedulerx:leader:fencing_token"
 )
 
+// ErrDuplicatePodID is returned by Initialize when a live pod already uses the configured POD_ID
+var ErrDuplicatePodID = errors.New("duplicate pod id")
+
+// ErrPodNotFound is returned when a pod is not in the registry
+var ErrPodNotFound = errors.New("pod not found")
+
 type PodInfo struct {
 	ID        string    `json:"id"`
 	StartTime time.Time `json:"start_time"`
 	LastSeen  time.Time `json:"last_seen"`
 	Status    string    `json:"status"`
-	IsLeader  bool      `json:"is_leader""`
+	IsLeader  bool      `json:"is_leader"`
+	Capacity  int       `json:"capacity"`          // Max jobs the pod should hold at once, 0 means unlimited
+	Address   string    `json:"address,omitempty"` // host:port of the pod's HTTP API, empty when unknown
+
+	RecentJobs     int     `json:"recent_jobs"`     // Jobs this pod finished recently, at most 50
+	RecentFailures int     `json:"recent_failures"` // How many of RecentJobs failed
+	Load           float64 `json:"load"`            // Load average per CPU between 0 and 1, 0 when unknown
+	Health         float64 `json:"health"`          // Health score between 0 and 1, only set by ListPods
+
+	Capabilities []string `json:"capabilities"` // Binaries found on the pod, nil when the pod did not probe for any
+	Paused       bool     `json:"paused"`       // The pod holds its assigned jobs without starting them, see SetPodPaused
+	Unhealthy    bool     `json:"unhealthy"`    // HEARTBEAT_FAILURE_THRESHOLD consecutive heartbeats failed, see IsConnected
 }
 
 var (
@@ -41,6 +66,32 @@ type PodManager struct {
 	config     *utils.Config
 	info       *PodInfo
 	assignment *assignment.Manager
+	out        io.Writer
+
+	// fencingToken is the token acquired when this pod last became leader, 0 when not leader
+	fencingToken atomic.Int64
+
+	// heartbeatFailures counts consecutive failed presence updates
+	heartbeatFailures atomic.Int32
+	// disconnected is set once heartbeatFailures reaches the configured threshold
+	disconnected atomic.Bool
+
+	// leading caches whether this pod was leader as of the last heartbeat
+	leading atomic.Bool
+	// leadershipHooks are called from the heartbeat routine whenever leading flips
+	leadershipHooks []func(isLeader bool)
+
+	// registryOversized is set while the last written pod registry exceeded PodRegistryWarnBytes
+	registryOversized atomic.Bool
+
+	// outcomes holds the results of recent jobs, reported with each heartbeat for the health score
+	outcomes outcomeWindow
+
+	// capabilities are the binaries found on this pod, reported with each heartbeat
+	capabilities []string
+
+	// releaseDeadPodJobs returns the jobs of unresponsive pods to the pool, see SetDeadPodJobReleaser
+	releaseDeadPodJobs func(ctx context.Context, alivePods map[string]bool) (int, error)
 }
 
 // NewPodManager creates a new pod manager instance
@@ -51,17 +102,29 @@ func NewPodManager(client *cache.Client, logger *utils.StandardLogger, config *u
 			logger:     logger,
 			config:     config,
 			assignment: assignment.NewManager(client, logger, config),
+			out:        os.Stdout,
 		}
 	})
 	return instance
 }
 
+// SetOutput sets where the pod status line is printed, defaults to stdout
+func (pm *PodManager) SetOutput(w io.Writer) {
+	pm.out = w
+}
+
 // Initialize sets up the pod with a unique ID and starts presence updates
 func (pm *PodManager) Initialize(ctx context.Context) error {
 	// get or create and ID for the current pod
 	podID := pm.config.PodID
 	if podID == "" {
 		podID = uuid.New().String()
+	} else {
+		resolved, err := pm.resolveDuplicatePodID(ctx, podID)
+		if err != nil {
+			return err
+		}
+		podID = resolved
 	}
 
 	pm.info = &PodInfo{
@@ -70,8 +133,10 @@ func (pm *PodManager) Initialize(ctx context.Context) error {
 		LastSeen:  time.Now(),
 		Status:    "active",
 		IsLeader:  false,
+		Capacity:  pm.config.PodCapacity,
+		Address:   pm.detectAddress(),
 	}
-	fmt.Printf("Current Pod ID: %s", pm.info.ID)
+	fmt.Fprintf(pm.out, "Current Pod ID: %s", pm.info.ID)
 
 	if err := pm.registerPod(ctx); err != nil {
 		return fmt.Errorf("failed to register pod: %w", err)
@@ -80,10 +145,41 @@ func (pm *PodManager) Initialize(ctx context.Context) error {
 	// start pod heartbeat
 	go pm.startPresenceUpdates(ctx)
 
-	pm.logger.Info("Pod manager initialized", "pod_id", podID)
+	// Reassign jobs of unresponsive pods on a fixed cadence, the check only acts on the leader
+	if interval := pm.config.PodHealthCheckInterval; interval > 0 {
+		go pm.startHealthChecks(ctx, interval)
+	}
+
+	pm.logger.Info("Pod manager initialized", "pod_id", podID, "address", pm.info.Address)
 	return nil
 }
 
+// resolveDuplicatePodID checks whether a live pod already uses podID. On a collision
+// it either refuses to start or returns the ID with a random suffix appended,
+// depending on RefuseDuplicatePodID
+func (pm *PodManager) resolveDuplicatePodID(ctx context.Context, podID string) (string, error) {
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	existing, exists := pods[podID]
+	if !exists || !existing.Alive(PodTTL(pm.config)) {
+		return podID, nil
+	}
+
+	if pm.config.RefuseDuplicatePodID {
+		pm.logger.Error("Another live pod is already registered with this POD_ID, refusing to start",
+			"pod_id", podID, "last_seen", existing.LastSeen)
+		return "", fmt.Errorf("%w: %s", ErrDuplicatePodID, podID)
+	}
+
+	unique := fmt.Sprintf("%s-%s", podID, uuid.New().String()[:8])
+	pm.logger.Error("Another live pod is already registered with this POD_ID, starting under a uniquified ID instead",
+		"pod_id", podID, "unique_pod_id", unique, "last_seen", existing.LastSeen)
+	return unique, nil
+}
+
 // registerPod registers the pod in Redis
 func (pm *PodManager) registerPod(ctx context.Context) error {
 	if pm.info == nil {
@@ -96,22 +192,37 @@ func (pm *PodManager) registerPod(ctx context.Context) error {
 		return fmt.Errorf("failed to get pods: %w", err)
 	}
 
-	// Add or update current pod
-	pods[pm.info.ID] = PodInfo{
-		ID:        pm.info.ID,
-		StartTime: pm.info.StartTime,
-		LastSeen:  pm.info.LastSeen,
-		Status:    pm.info.Status,
-	}
+	// Add or update current pod, keeping the paused flag set through SetPodPaused
+	info := pm.currentInfo()
+	info.Paused = pods[pm.info.ID].Paused
+	pods[pm.info.ID] = info
 
 	// Store updated pods
-	if err := pm.client.SetJSONWithExpiry(ctx, podRegistryKey, pods, 24*time.Hour); err != nil {
+	if err := pm.storePods(ctx, pods); err != nil {
 		return fmt.Errorf("failed to store pods: %w", err)
 	}
 
 	return nil
 }
 
+// currentInfo returns the registry entry of this pod, including its recent job outcomes and load
+func (pm *PodManager) currentInfo() PodInfo {
+	recentJobs, recentFailures := pm.outcomes.counts()
+	return PodInfo{
+		ID:             pm.info.ID,
+		StartTime:      pm.info.StartTime,
+		LastSeen:       pm.info.LastSeen,
+		Status:         pm.info.Status,
+		Capacity:       pm.info.Capacity,
+		Address:        pm.info.Address,
+		RecentJobs:     recentJobs,
+		RecentFailures: recentFailures,
+		Load:           systemLoad(),
+		Capabilities:   pm.capabilities,
+		Unhealthy:      pm.disconnected.Load(),
+	}
+}
+
 // getPods retrieves all registered pods from Redis
 func (pm *PodManager) getPods(ctx context.Context) (map[string]PodInfo, error) {
 	var pods map[string]PodInfo
@@ -124,13 +235,15 @@ func (pm *PodManager) getPods(ctx context.Context) (map[string]PodInfo, error) {
 	return pods, nil
 }
 
-// cleanupDeadPods removes pods that haven't been seen for longer than podTTL
+// cleanupDeadPods removes pods that haven't been seen for longer than the
+// leadership staleness threshold. Pods that only missed a heartbeat or two
+// stay registered, so they keep their place in leader election.
 func (pm *PodManager) cleanupDeadPods(ctx context.Context, pods map[string]PodInfo) map[string]PodInfo {
 	cleanedPods := make(map[string]PodInfo)
-	now := time.Now()
+	staleness := LeadershipStaleness(pm.config)
 
 	for id, info := range pods {
-		if now.Sub(info.LastSeen) <= podTTL {
+		if info.Alive(staleness) {
 			cleanedPods[id] = info
 		}
 	}
@@ -153,13 +266,47 @@ func (pm *PodManager) startPresenceUpdates(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := pm.updatePresence(ctx); err != nil {
+			err := pm.updatePresence(ctx)
+			if err != nil {
 				pm.logger.Error("Failed to update presence", "error", err)
 			}
+			pm.recordHeartbeat(err)
 		}
 	}
 }
 
+// recordHeartbeat tracks consecutive heartbeat failures and flips the pod into a
+// disconnected state once the threshold is reached, so it stops acting on stale state.
+// A heartbeat fails when any part of it fails, not only the registry write, so a pod
+// whose entry is written but cannot read leadership is flagged as well. While
+// disconnected the pod does not act as leader, and if its entry still gets written
+// it is marked unhealthy so the leader neither elects it nor assigns it jobs.
+func (pm *PodManager) recordHeartbeat(err error) {
+	if err == nil {
+		pm.heartbeatFailures.Store(0)
+		metrics.HeartbeatFailures.Set(0)
+		if pm.disconnected.CompareAndSwap(true, false) {
+			pm.logger.Info("Redis connectivity restored, resuming job execution", "pod_id", pm.info.ID)
+		}
+		return
+	}
+
+	failures := pm.heartbeatFailures.Add(1)
+	metrics.HeartbeatFailures.Set(int64(failures))
+	threshold := pm.config.HeartbeatFailureThreshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+	if int(failures) >= threshold && pm.disconnected.CompareAndSwap(false, true) {
+		pm.logger.Error("Pod disconnected from Redis, pausing job execution", "pod_id", pm.info.ID, "failed_heartbeats", failures)
+	}
+}
+
+// IsConnected reports whether the pod's heartbeats are reaching Redis
+func (pm *PodManager) IsConnected() bool {
+	return !pm.disconnected.Load()
+}
+
 // updatePresence updates the pod's last seen time and displays other pods
 func (pm *PodManager) updatePresence(ctx context.Context) error {
 	if pm.info == nil {
@@ -178,12 +325,7 @@ func (pm *PodManager) updatePresence(ctx context.Context) error {
 	pods = pm.cleanupDeadPods(ctx, pods)
 
 	// Add or update current pod
-	pods[pm.info.ID] = PodInfo{
-		ID:        pm.info.ID,
-		StartTime: pm.info.StartTime,
-		LastSeen:  pm.info.LastSeen,
-		Status:    pm.info.Status,
-	}
+	pods[pm.info.ID] = pm.currentInfo()
 
 	// Store updated pods
 	if err := pm.registerPod(ctx); err != nil {
@@ -196,19 +338,26 @@ func (pm *PodManager) updatePresence(ctx context.Context) error {
 		return err
 	}
 
+	// Acquire a fresh fencing token on promotion, drop it on demotion
+	isLeader := leaderID == pm.info.ID
+	if err := pm.updateFencingToken(ctx, isLeader); err != nil {
+		return err
+	}
+	pm.setLeading(isLeader)
+
 	// Clear line and print header
-	fmt.Printf("\r\033[KActive Pods (%d): ", len(pods))
+	fmt.Fprintf(pm.out, "\r\033[KActive Pods (%d): ", len(pods))
 
 	// Print pod statuses
 	first := true
 	for id, info := range pods {
 		if !first {
-			fmt.Print(", ")
+			fmt.Fprint(pm.out, ", ")
 		}
 		first = false
 
 		status := "✓"
-		if time.Since(info.LastSeen) > podTTL {
+		if !info.Alive(PodTTL(pm.config)) {
 			status = "✗"
 		}
 
@@ -223,13 +372,82 @@ func (pm *PodManager) updatePresence(ctx context.Context) error {
 			indicators += "⚡" // Current pod (but not leader)
 		}
 
-		fmt.Printf("%s[%s]%s", status, id[:8], indicators)
+		fmt.Fprintf(pm.out, "%s[%s]%s", status, id[:8], indicators)
+	}
+	fmt.Fprint(pm.out, "\n")
+
+	return nil
+}
+
+// updateFencingToken increments the shared fencing token when this pod becomes
+// leader, so writes from any previous leader are rejected from then on
+func (pm *PodManager) updateFencingToken(ctx context.Context, isLeader bool) error {
+	if !isLeader {
+		pm.fencingToken.Store(0)
+		return nil
+	}
+	if pm.fencingToken.Load() != 0 {
+		return nil
 	}
-	fmt.Print("\n")
 
+	token, err := pm.client.GetClient().Incr(ctx, FencingTokenKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to acquire fencing token: %w", err)
+	}
+	pm.fencingToken.Store(token)
+	pm.logger.Info("Acquired leader fencing token", "pod_id", pm.info.ID, "token", token)
 	return nil
 }
 
+// OnLeadershipChange registers fn to be called when this pod gains or loses
+// leadership. Hooks run on the heartbeat routine and must not block. Register
+// hooks before Initialize.
+func (pm *PodManager) OnLeadershipChange(fn func(isLeader bool)) {
+	pm.leadershipHooks = append(pm.leadershipHooks, fn)
+}
+
+// SetDeadPodJobReleaser sets how CheckPodHealth returns the jobs of unresponsive pods to
+// the pool. The scheduler provides it, as job writes must follow its fencing and locking
+// rules. Without one CheckPodHealth leaves the jobs to the assignment pass. Set it before Initialize.
+func (pm *PodManager) SetDeadPodJobReleaser(fn func(ctx context.Context, alivePods map[string]bool) (int, error)) {
+	pm.releaseDeadPodJobs = fn
+}
+
+// Leading reports whether this pod was leader as of its last heartbeat, without reading Redis
+func (pm *PodManager) Leading() bool {
+	return pm.leading.Load()
+}
+
+// setLeading records the leadership state and notifies hooks when it changed
+func (pm *PodManager) setLeading(isLeader bool) {
+	if pm.leading.Swap(isLeader) == isLeader {
+		return
+	}
+	pm.logger.Info("Leadership changed", "pod_id", pm.info.ID, "is_leader", isLeader)
+	for _, fn := range pm.leadershipHooks {
+		fn(isLeader)
+	}
+}
+
+// FencingToken returns the fencing token held by this pod, 0 if it is not leader
+func (pm *PodManager) FencingToken() int64 {
+	return pm.fencingToken.Load()
+}
+
+// SetCapacity changes how many jobs this pod should hold, picked up by the leader on the next heartbeat
+func (pm *PodManager) SetCapacity(capacity int) {
+	if pm.info == nil {
+		return
+	}
+	pm.info.Capacity = capacity
+}
+
+// SetCapabilities records the capabilities found on this pod, see command.ProbeCapabilities.
+// It must be called before Initialize.
+func (pm *PodManager) SetCapabilities(capabilities []string) {
+	pm.capabilities = capabilities
+}
+
 // GetPodID returns the current pod's ID
 func (pm *PodManager) GetPodID() string {
 	if pm.info == nil {
@@ -253,6 +471,26 @@ func (pm *PodManager) GetLeader(ctx context.Context) (string, error) {
 		return "", nil
 	}
 
+	// Get the leader ID
+	leaderID := electLeader(pods)
+
+	// Update IsLeader status for all pods
+	for id, info := range pods {
+		info.IsLeader = (id == leaderID)
+		pods[id] = info
+	}
+
+	// Store updated pods with leader status
+	if err := pm.storePods(ctx, pods); err != nil {
+		return "", fmt.Errorf("failed to store updated pods: %w", err)
+	}
+
+	return leaderID, nil
+}
+
+// electLeader returns the ID of the pod that started first, empty if there are no pods.
+// Unhealthy pods are only elected when no healthy pod is left.
+func electLeader(pods map[string]PodInfo) string {
 	// Convert pods map to slice for sorting
 	type podEntry struct {
 		id        string
@@ -260,7 +498,17 @@ func (pm *PodManager) GetLeader(ctx context.Context) (string, error) {
 	}
 	podSlice := make([]podEntry, 0, len(pods))
 	for id, info := range pods {
-		podSlice = append(podSlice, podEntry{id: id, startTime: info.StartTime})
+		if !info.Unhealthy {
+			podSlice = append(podSlice, podEntry{id: id, startTime: info.StartTime})
+		}
+	}
+	if len(podSlice) == 0 {
+		for id, info := range pods {
+			podSlice = append(podSlice, podEntry{id: id, startTime: info.StartTime})
+		}
+	}
+	if len(podSlice) == 0 {
+		return ""
 	}
 
 	// Sort pods by start time
@@ -268,21 +516,52 @@ func (pm *PodManager) GetLeader(ctx context.Context) (string, error) {
 		return podSlice[i].startTime.Before(podSlice[j].startTime)
 	})
 
-	// Get the leader ID
-	leaderID := podSlice[0].id
+	return podSlice[0].id
+}
 
-	// Update IsLeader status for all pods
-	for id, info := range pods {
-		info.IsLeader = (id == leaderID)
-		pods[id] = info
+// ListPods returns all live pods ordered by start time, with the leader flagged.
+// Paused pods are reported with status "paused", pods whose heartbeats keep
+// failing with status "unhealthy", and while the cluster is
+// drained every pod is reported with status "draining".
+// Unlike GetLeader it does not write the registry.
+func (pm *PodManager) ListPods(ctx context.Context) ([]PodInfo, error) {
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Store updated pods with leader status
-	if err := pm.client.SetJSONWithExpiry(ctx, podRegistryKey, pods, 24*time.Hour); err != nil {
-		return "", fmt.Errorf("failed to store updated pods: %w", err)
+	// Skip pods that stopped sending heartbeats
+	pods = pm.cleanupDeadPods(ctx, pods)
+	leaderID := electLeader(pods)
+
+	draining, err := pm.IsClusterDraining(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return leaderID, nil
+	list := make([]PodInfo, 0, len(pods))
+	for id, info := range pods {
+		info.IsLeader = id == leaderID
+		info.Health = info.HealthScore(PodTTL(pm.config), LeadershipStaleness(pm.config))
+		if !info.Alive(PodTTL(pm.config)) {
+			info.Status = "unresponsive"
+		}
+		if info.Paused {
+			info.Status = "paused"
+		}
+		if info.Unhealthy {
+			info.Status = "unhealthy"
+		}
+		if draining {
+			info.Status = "draining"
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StartTime.Before(list[j].StartTime)
+	})
+
+	return list, nil
 }
 
 // IsLeader checks if the current pod is the leader
@@ -291,6 +570,11 @@ func (pm *PodManager) IsLeader(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("pod info not initialized")
 	}
 
+	// A pod whose heartbeats fail may no longer be the leader the others see
+	if pm.disconnected.Load() {
+		return false, nil
+	}
+
 	// Get pods to check leader status
 	pods, err := pm.getPods(ctx)
 	if err != nil {
@@ -318,10 +602,35 @@ func GetLeader() string {
 	return leaderID
 }
 
-// IsLeader checks if the current pod is the leader (global function)
+// GetPodID returns the current pod's ID (global function)
+func GetPodID() string {
+	if instance == nil {
+		return ""
+	}
+	return instance.GetPodID()
+}
+
+// FencingToken returns the fencing token held by the current pod (global function)
+func FencingToken() int64 {
+	if instance == nil {
+		return 0
+	}
+	return instance.FencingToken()
+}
+
+// IsConnected reports whether the current pod's heartbeats are reaching Redis (global function).
+// It is true while no pod manager exists, as no heartbeat has failed yet.
+func IsConnected() bool {
+	if instance == nil {
+		return true
+	}
+	return instance.IsConnected()
+}
+
+// IsLeader checks if the current pod is the leader (global function).
+// It is false while no pod manager exists.
 func IsLeader() bool {
 	if instance == nil {
-		instance.logger.Error("Pod manager instance is nil")
 		return false
 	}
 	isLeader, err := instance.IsLeader(context.Background())
@@ -332,57 +641,50 @@ func IsLeader() bool {
 	return isLeader
 }
 
-// CheckPodHealth checks the health of all pods and updates their status
+// CheckPodHealth returns the jobs of unresponsive pods to the pool so they can be
+// reassigned, including pods already removed from the registry, through the releaser
+// set with SetDeadPodJobReleaser. Only the leader acts. Dead pods are removed from the
+// registry by the heartbeat, not here.
 func (pm *PodManager) CheckPodHealth(ctx context.Context) error {
-	// Get all pods from Redis
+	if !pm.Leading() || pm.releaseDeadPodJobs == nil {
+		return nil
+	}
+
 	pods, err := pm.getPods(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get pods: %w", err)
 	}
 
-	// Check each pod's health
+	alivePods := make(map[string]bool, len(pods))
 	for podID, pod := range pods {
-		// Skip checking our own pod
-		if podID == pm.info.ID {
-			continue
-		}
-
-		// Check if pod is alive
-		if time.Since(pod.LastSeen) > podTTL {
-			pm.logger.Info("Pod is dead, removing from set", "pod_id", podID)
-
-			// Remove pod from map
-			delete(pods, podID)
-
-			// Unassign all jobs from this pod
-			if err := pm.assignment.UnassignJobsFromPod(ctx, podID); err != nil {
-				pm.logger.Error("Failed to unassign jobs from pod", "pod_id", podID, "error", err)
-			}
+		if pod.Alive(PodTTL(pm.config)) {
+			alivePods[podID] = true
 		}
 	}
 
-	// Store updated pods
-	if err := pm.client.SetJSONWithExpiry(ctx, podRegistryKey, pods, 24*time.Hour); err != nil {
-		return fmt.Errorf("failed to store updated pods: %w", err)
+	unassigned, err := pm.releaseDeadPodJobs(ctx, alivePods)
+	if err != nil {
+		return fmt.Errorf("failed to unassign jobs from dead pods: %w", err)
+	}
+	if unassigned > 0 {
+		pm.logger.Info("Unassigned jobs from unresponsive pods", "jobs", unassigned, "alive_pods", len(alivePods))
 	}
+	return nil
+}
 
-	// If we're the leader, assign jobs to available pods
-	if IsLeader() {
-		// Filter out our own pod from the list
-		availablePods := make([]string, 0)
-		for podID := range pods {
-			if podID != pm.info.ID {
-				availablePods = append(availablePods, podID)
-			}
-		}
+// startHealthChecks runs CheckPodHealth every POD_HEALTH_CHECK_INTERVAL until ctx is cancelled
+func (pm *PodManager) startHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-		// Assign jobs to available pods
-		if err := pm.assignment.AssignJobs(ctx, availablePods); err != nil {
-			pm.logger.Error("Failed to assign jobs", "error", err)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := pm.CheckPodHealth(ctx); err != nil {
+				pm.logger.Error("Failed to check pod health", "error", err)
+			}
 		}
-	} else {
-		pm.logger.Info("Not the leader, skipping job assignment")
 	}
-
-	return nil
 }
@@ -0,0 +1,159 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+func TestIsConnectedWithoutPodManager(t *testing.T) {
+	if instance != nil {
+		t.Skip("a pod manager was registered by another test")
+	}
+	if !IsConnected() {
+		t.Fatal("IsConnected is false before any heartbeat could fail")
+	}
+}
+
+func TestHeartbeatFailuresDisconnectPod(t *testing.T) {
+	config := testConfig()
+	config.HeartbeatFailureThreshold = 3
+	pm, _ := newTestPodManager(t, config)
+	if err := pm.registerPod(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("connection refused")
+
+	for range 2 {
+		pm.recordHeartbeat(failure)
+	}
+	if !pm.IsConnected() {
+		t.Fatal("pod disconnected before reaching the failure threshold")
+	}
+
+	pm.recordHeartbeat(failure)
+	if pm.IsConnected() {
+		t.Fatal("pod still connected after 3 failed heartbeats")
+	}
+	if !pm.currentInfo().Unhealthy {
+		t.Fatal("disconnected pod does not report itself unhealthy")
+	}
+	if isLeader, err := pm.IsLeader(context.Background()); err != nil || isLeader {
+		t.Fatalf("disconnected pod reports leadership %v, %v, want false", isLeader, err)
+	}
+
+	pm.recordHeartbeat(nil)
+	if !pm.IsConnected() {
+		t.Fatal("pod still disconnected after a successful heartbeat")
+	}
+	if pm.currentInfo().Unhealthy {
+		t.Fatal("reconnected pod still reports itself unhealthy")
+	}
+}
+
+func TestDuplicatePodIDIsUniquified(t *testing.T) {
+	ctx := context.Background()
+	first, server := newTestPodManager(t, testConfig())
+	if err := first.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	podID, err := podManagerOn(t, server, "", testConfig()).resolveDuplicatePodID(ctx, testPodID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if podID == testPodID || !strings.HasPrefix(podID, testPodID+"-") {
+		t.Fatalf("colliding pod starts as %q, want %s with a suffix", podID, testPodID)
+	}
+}
+
+func TestDuplicatePodIDIsRefused(t *testing.T) {
+	ctx := context.Background()
+	first, server := newTestPodManager(t, testConfig())
+	if err := first.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config := testConfig()
+	config.RefuseDuplicatePodID = true
+	if _, err := podManagerOn(t, server, "", config).resolveDuplicatePodID(ctx, testPodID); !errors.Is(err, ErrDuplicatePodID) {
+		t.Fatalf("resolveDuplicatePodID = %v, want %v", err, ErrDuplicatePodID)
+	}
+}
+
+func TestStalePodIDIsReused(t *testing.T) {
+	ctx := context.Background()
+	first, server := newTestPodManager(t, testConfig())
+	first.info.LastSeen = time.Now().Add(-time.Hour)
+	if err := first.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config := testConfig()
+	config.RefuseDuplicatePodID = true
+	podID, err := podManagerOn(t, server, "", config).resolveDuplicatePodID(ctx, testPodID)
+	if err != nil || podID != testPodID {
+		t.Fatalf("resolveDuplicatePodID = %q, %v, want the ID of the dead pod reused", podID, err)
+	}
+}
+
+func TestFailingHeartbeatWritesMarkPodUnhealthy(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.HeartbeatFailureThreshold = 2
+	pm, server := newTestPodManager(t, config)
+	pm.SetOutput(io.Discard)
+	if err := pm.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+	heartbeat := func() {
+		pm.recordHeartbeat(pm.updatePresence(ctx))
+	}
+
+	server.SetError("READONLY You can't write against a read only replica.")
+	heartbeat()
+	if !pm.IsConnected() || metrics.HeartbeatFailures.Value() != 1 {
+		t.Fatalf("after 1 failed write connected %v with %d failures, want connected with 1", pm.IsConnected(), metrics.HeartbeatFailures.Value())
+	}
+	heartbeat()
+	if pm.IsConnected() {
+		t.Fatal("pod still connected after 2 failed heartbeat writes")
+	}
+
+	// Once writes succeed again the pod reports itself unhealthy until a heartbeat fully succeeds
+	server.SetError("")
+	if err := pm.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pods[testPodID].Unhealthy {
+		t.Fatal("disconnected pod wrote its entry without the unhealthy flag")
+	}
+	heartbeat()
+	if !pm.IsConnected() || metrics.HeartbeatFailures.Value() != 0 {
+		t.Fatal("pod still disconnected after a successful heartbeat")
+	}
+}
+
+func TestElectLeaderSkipsUnhealthyPods(t *testing.T) {
+	now := time.Now()
+	pods := map[string]PodInfo{
+		"oldest": {ID: "oldest", StartTime: now.Add(-time.Hour), Unhealthy: true},
+		"newer":  {ID: "newer", StartTime: now},
+	}
+	if leaderID := electLeader(pods); leaderID != "newer" {
+		t.Fatalf("elected %q, want the healthy newer pod", leaderID)
+	}
+	delete(pods, "newer")
+	if leaderID := electLeader(pods); leaderID != "oldest" {
+		t.Fatalf("elected %q, want the unhealthy pod when no healthy pod is left", leaderID)
+	}
+}
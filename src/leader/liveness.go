@@ -0,0 +1,41 @@
+package leader
+
+import (
+	"slices"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// Alive reports whether the pod sent a heartbeat within ttl
+func (p PodInfo) Alive(ttl time.Duration) bool {
+	return time.Since(p.LastSeen) <= ttl
+}
+
+// HasCapabilities reports whether the pod has every required capability.
+// Pods that did not probe for capabilities are assumed to have all of them.
+func (p PodInfo) HasCapabilities(required []string) bool {
+	if p.Capabilities == nil {
+		return true
+	}
+	for _, capability := range required {
+		if !slices.Contains(p.Capabilities, capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// PodTTL returns how long a pod may miss heartbeats before it is excluded from job assignment
+func PodTTL(config *utils.Config) time.Duration {
+	if config.PodTTL > 0 {
+		return config.PodTTL
+	}
+	return defaultPodTTL
+}
+
+// LeadershipStaleness returns how long a pod may miss heartbeats before it is
+// no longer considered for leadership, never shorter than PodTTL
+func LeadershipStaleness(config *utils.Config) time.Duration {
+	return max(config.LeadershipStaleness, PodTTL(config))
+}
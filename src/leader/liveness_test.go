@@ -0,0 +1,58 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeadershipSurvivesMissedHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.PodTTL = 5 * time.Second
+	config.LeadershipStaleness = 15 * time.Second
+	pm, _ := newTestPodManager(t, config)
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		lastSeen   time.Time
+		wantLeader string
+		wantStatus string
+	}{
+		{"one missed heartbeat", now.Add(-8 * time.Second), "old-pod", "unresponsive"},
+		{"long gone", now.Add(-time.Minute), testPodID, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods := map[string]PodInfo{
+				"old-pod": {ID: "old-pod", StartTime: now.Add(-time.Hour), LastSeen: tt.lastSeen, Status: "active"},
+				testPodID: {ID: testPodID, StartTime: now, LastSeen: now, Status: "active"},
+			}
+			if err := pm.storePods(ctx, pods); err != nil {
+				t.Fatal(err)
+			}
+
+			if leaderID, err := pm.GetLeader(ctx); err != nil || leaderID != tt.wantLeader {
+				t.Fatalf("GetLeader = %q, %v, want %s", leaderID, err, tt.wantLeader)
+			}
+			listed, err := pm.ListPods(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			status := ""
+			for _, pod := range listed {
+				if pod.ID == "old-pod" {
+					status = pod.Status
+				}
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("old-pod listed as %q, want %q", status, tt.wantStatus)
+			}
+		})
+	}
+
+	if missed := (PodInfo{LastSeen: now.Add(-8 * time.Second)}); missed.Alive(PodTTL(config)) {
+		t.Fatal("pod that missed a heartbeat is still considered for assignment")
+	}
+}
@@ -0,0 +1,56 @@
+package leader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/assignment"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+)
+
+// testPodID is the ID of the pod managed by newTestPodManager
+const testPodID = "test-pod"
+
+// testConfig returns the config with every variable at its default
+func testConfig() *utils.Config {
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// testLogger returns a logger discarding everything
+func testLogger() *utils.StandardLogger {
+	return &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}
+}
+
+// newTestPodManager returns a pod manager on a fresh in-memory Redis without
+// registering the process-wide instance or starting heartbeats
+func newTestPodManager(t *testing.T, config *utils.Config) (*PodManager, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return podManagerOn(t, server, testPodID, config), server
+}
+
+// podManagerOn returns a pod manager with the given ID on server, e.g. to run several pods against one Redis
+func podManagerOn(t *testing.T, server *miniredis.Miniredis, podID string, config *utils.Config) *PodManager {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	client := cache.NewClientFromRedis(rdb)
+
+	now := time.Now()
+	return &PodManager{
+		client:     client,
+		logger:     testLogger(),
+		config:     config,
+		info:       &PodInfo{ID: podID, StartTime: now, LastSeen: now},
+		assignment: assignment.NewManager(client, testLogger(), config),
+	}
+}
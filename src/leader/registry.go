@@ -0,0 +1,66 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+// podRegistryExpiry is how long the pod registry outlives its last write
+const podRegistryExpiry = 24 * time.Hour
+
+// storePods writes the pod registry. The registry is a single value rewritten
+// on every heartbeat, so its size is tracked in schedulerx_pod_registry_bytes
+// and a warning is logged once it grows beyond POD_REGISTRY_WARN_BYTES.
+func (pm *PodManager) storePods(ctx context.Context, pods map[string]PodInfo) error {
+	data, err := json.Marshal(pods)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pods: %w", err)
+	}
+
+	size := len(data)
+	metrics.PodRegistryBytes.Set(int64(size))
+
+	// Warn when crossing the threshold rather than on every heartbeat
+	oversized := pm.config.PodRegistryWarnBytes > 0 && size > pm.config.PodRegistryWarnBytes
+	if pm.registryOversized.Swap(oversized) != oversized && oversized {
+		pm.logger.Warn("Pod registry exceeds size threshold",
+			"bytes", size,
+			"threshold", pm.config.PodRegistryWarnBytes,
+			"pods", len(pods),
+		)
+	}
+
+	return pm.client.SetWithExpiry(ctx, podRegistryKey, data, podRegistryExpiry)
+}
+
+// SetPodPaused sets the paused flag of a pod in the registry. A paused pod keeps
+// heartbeating and being assigned jobs, but does not start any until resumed.
+// The pod keeps the flag when it rewrites its entry, including after a restart
+// under the same POD_ID before its entry went stale.
+func (pm *PodManager) SetPodPaused(ctx context.Context, podID string, paused bool) (*PodInfo, error) {
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, exists := pods[podID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrPodNotFound, podID)
+	}
+
+	info.Paused = paused
+	pods[podID] = info
+	if err := pm.storePods(ctx, pods); err != nil {
+		return nil, fmt.Errorf("failed to store pods: %w", err)
+	}
+
+	if paused {
+		pm.logger.Warn("Pod paused, its assigned jobs will not start", "pod_id", podID)
+	} else {
+		pm.logger.Info("Pod resumed", "pod_id", podID)
+	}
+	return &info, nil
+}
@@ -0,0 +1,117 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// testPods returns a registry of n pods
+func testPods(n int) map[string]PodInfo {
+	now := time.Now()
+	pods := make(map[string]PodInfo, n)
+	for i := range n {
+		id := fmt.Sprintf("pod-%d", i)
+		pods[id] = PodInfo{ID: id, StartTime: now, LastSeen: now}
+	}
+	return pods
+}
+
+func TestOversizedPodRegistryWarnsOnce(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.PodRegistryWarnBytes = 1000
+	pm, server := newTestPodManager(t, config)
+	core, logs := observer.New(zapcore.WarnLevel)
+	pm.logger = &utils.StandardLogger{SugaredLogger: zap.New(core).Sugar()}
+
+	store := func(pods map[string]PodInfo) {
+		t.Helper()
+		if err := pm.storePods(ctx, pods); err != nil {
+			t.Fatal(err)
+		}
+		stored, err := server.Get(podRegistryKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := metrics.PodRegistryBytes.Value(); got != int64(len(stored)) {
+			t.Fatalf("schedulerx_pod_registry_bytes = %d, want the %d bytes written", got, len(stored))
+		}
+	}
+	warnings := func() int {
+		return logs.FilterMessageSnippet("Pod registry exceeds size threshold").Len()
+	}
+
+	store(testPods(1))
+	if warnings() != 0 {
+		t.Fatal("registry within the threshold was warned about")
+	}
+
+	store(testPods(20))
+	store(testPods(21))
+	if warnings() != 1 {
+		t.Fatalf("oversized registry warned %d times, want once", warnings())
+	}
+
+	store(testPods(1))
+	store(testPods(20))
+	if warnings() != 2 {
+		t.Fatalf("registry growing past the threshold again warned %d times in total, want 2", warnings())
+	}
+}
+
+func TestRegisteredPodCarriesProbedCapabilities(t *testing.T) {
+	ctx := context.Background()
+	pm, _ := newTestPodManager(t, testConfig())
+	pm.SetCapabilities([]string{"du", "ls"})
+	if err := pm.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := pods[testPodID]
+	if !slices.Equal(pod.Capabilities, []string{"du", "ls"}) {
+		t.Fatalf("registered capabilities %q, want du and ls", pod.Capabilities)
+	}
+	if !pod.HasCapabilities([]string{"ls"}) || pod.HasCapabilities([]string{"ping"}) {
+		t.Fatal("registered pod does not match the probed capabilities")
+	}
+}
+
+func TestPausedFlagSurvivesHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	pm, _ := newTestPodManager(t, testConfig())
+	if err := pm.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pm.SetPodPaused(ctx, testPodID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pm.SetPodPaused(ctx, "missing-pod", true); !errors.Is(err, ErrPodNotFound) {
+		t.Fatalf("pausing an unknown pod returned %v, want %v", err, ErrPodNotFound)
+	}
+
+	// The pod rewrites its own entry on every heartbeat and on restart
+	if err := pm.registerPod(ctx); err != nil {
+		t.Fatal(err)
+	}
+	pods, err := pm.getPods(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pods[testPodID].Paused {
+		t.Fatal("pod lost its paused flag when rewriting its entry")
+	}
+}
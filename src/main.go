@@ -6,13 +6,9 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/yashkumarverma/schedulerx/src/command"
-	"github.com/yashkumarverma/schedulerx/src/leader"
-	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/schedulerx"
 	"github.com/yashkumarverma/schedulerx/src/utils"
-	"github.com/yashkumarverma/schedulerx/src/utils/cache"
 )
 
 func main() {
@@ -20,59 +16,44 @@ func main() {
 	defer cancel()
 
 	logger := utils.NewLogger()
-	config := utils.GetConfig(ctx)
-
-	redisClient, err := cache.NewClient(ctx, config)
+	config, warnings, err := utils.LoadConfig()
+	for _, warning := range warnings {
+		logger.Warn(warning)
+	}
 	if err != nil {
-		logger.Fatal("Failed to create Redis client", err)
+		logger.Fatal("Failed to load config", err)
 	}
 
-	cmdRegistry := command.NewCommandRegistry()
+	app := schedulerx.New(config,
+		schedulerx.WithLogger(logger),
+		schedulerx.WithStatusOutput(os.Stdout),
+	)
 
 	// only hardcoded tasks supported now
 	fmt.Println("\nSupported Commands:")
-	for cmd, desc := range cmdRegistry.GetCommandDescriptions() {
-		fmt.Printf("%-15s - %s\n", cmd, desc)
-	}
-
-	// Initialize pod manager
-	podManager := leader.NewPodManager(redisClient, logger, config)
-	if err := podManager.Initialize(ctx); err != nil {
-		logger.Fatal("Failed to initialize pod manager", err)
+	for cmd, c := range app.Commands() {
+		fmt.Printf("%-15s - %s\n", cmd, c.Description())
 	}
 
-	logger.Info("Pod manager initialized successfully", "pod_id", podManager.GetPodID())
-
-	// Create scheduler instance
-	scheduler := scheduler.NewScheduler(redisClient, logger, config)
-
-	// Register all commands with the scheduler
-	for cmdID, cmd := range cmdRegistry.GetCommands() {
-		scheduler.RegisterCommand(cmd)
-		logger.Info("Registered command with scheduler", "command", cmdID)
-	}
-
-	// Start job scheduling routine
+	errChan := make(chan error, 1)
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := scheduler.ScheduleJobs(ctx); err != nil {
-					logger.Error("Failed to schedule jobs", "error", err)
-				}
-			}
-		}
+		errChan <- app.Run(ctx)
 	}()
 
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
 
-	logger.Info("Shutting down gracefully...")
+	select {
+	case <-sigChan:
+		logger.Info("Shutting down gracefully...")
+		cancel()
+		if err := app.Shutdown(context.Background()); err != nil {
+			logger.Error("Failed to shut down gracefully", "error", err)
+		}
+	case err := <-errChan:
+		if err != nil {
+			logger.Fatal("Scheduler stopped", err)
+		}
+	}
 }
@@ -0,0 +1,10 @@
+package metrics
+
+// Pod registry metrics, exposed on GET /metrics
+var (
+	// PodRegistryBytes is the size of the schedulerx:pods JSON as last written by this pod
+	PodRegistryBytes = NewGauge("schedulerx_pod_registry_bytes", "Size in bytes of the pod registry as last written by this pod.")
+
+	// HeartbeatFailures is the number of consecutive heartbeats of this pod that failed
+	HeartbeatFailures = NewGauge("schedulerx_heartbeat_failures", "Consecutive failed heartbeats of this pod.")
+)
@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// collector is a metric that can render itself in the Prometheus text format
+type collector interface {
+	writeTo(w io.Writer)
+	family() Family
+}
+
+// Family is a snapshot of a metric and its values, for exporters other than GET /metrics
+type Family struct {
+	Name    string
+	Help    string
+	Counter bool               // Whether the metric only increases, otherwise it is a gauge
+	Label   string             // Label partitioning the values, empty for metrics without labels
+	Values  map[string]float64 // Values by label value, keyed by "" for metrics without labels
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []collector
+)
+
+// register adds a collector to the set written by WriteText
+func register(c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, c)
+}
+
+// WriteText writes every registered metric in the Prometheus text exposition format
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryMu.Unlock()
+
+	for _, c := range collectors {
+		c.writeTo(w)
+	}
+}
+
+// Collect returns a snapshot of every registered metric
+func Collect() []Family {
+	registryMu.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryMu.Unlock()
+
+	families := make([]Family, 0, len(collectors))
+	for _, c := range collectors {
+		families = append(families, c.family())
+	}
+	return families
+}
+
+// Counter is a monotonically increasing counter without labels
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+// NewCounter creates and registers a counter
+func NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	register(c)
+	return c
+}
+
+// Inc increases the counter by one
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the current value of the counter
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+func (c *Counter) family() Family {
+	return Family{Name: c.name, Help: c.help, Counter: true, Values: map[string]float64{"": float64(c.value.Load())}}
+}
+
+func (c *Counter) writeTo(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.value.Load())
+}
+
+// CounterVec is a monotonically increasing counter partitioned by a single label
+type CounterVec struct {
+	name   string
+	help   string
+	label  string
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec creates and registers a counter partitioned by label
+func NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{
+		name:   name,
+		help:   help,
+		label:  label,
+		values: make(map[string]float64),
+	}
+	register(c)
+	return c
+}
+
+// Add increases the counter for labelValue by delta, negative deltas are ignored
+func (c *CounterVec) Add(labelValue string, delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[labelValue] += delta
+}
+
+// Inc increases the counter for labelValue by one
+func (c *CounterVec) Inc(labelValue string) {
+	c.Add(labelValue, 1)
+}
+
+// Value returns the current value for labelValue
+func (c *CounterVec) Value(labelValue string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[labelValue]
+}
+
+func (c *CounterVec) family() Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Family{Name: c.name, Help: c.help, Counter: true, Label: c.label, Values: maps.Clone(c.values)}
+}
+
+func (c *CounterVec) writeTo(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	labelValues := make([]string, 0, len(c.values))
+	for labelValue := range c.values {
+		labelValues = append(labelValues, labelValue)
+	}
+	sort.Strings(labelValues)
+	for _, labelValue := range labelValues {
+		fmt.Fprintf(w, "%s{%s=%q} %g\n", c.name, c.label, labelValue, c.values[labelValue])
+	}
+}
+
+// Gauge is a value that can go up and down, without labels
+type Gauge struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+// NewGauge creates and registers a gauge
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+// Set sets the gauge to value
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+}
+
+// Value returns the current value of the gauge
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) family() Family {
+	return Family{Name: g.name, Help: g.help, Values: map[string]float64{"": float64(g.value.Load())}}
+}
+
+func (g *Gauge) writeTo(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, g.value.Load())
+}
+
+// GaugeVec is a value that can go up and down, partitioned by a single label
+type GaugeVec struct {
+	name   string
+	help   string
+	label  string
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewGaugeVec creates and registers a gauge partitioned by label
+func NewGaugeVec(name, help, label string) *GaugeVec {
+	g := &GaugeVec{
+		name:   name,
+		help:   help,
+		label:  label,
+		values: make(map[string]float64),
+	}
+	register(g)
+	return g
+}
+
+// Set sets the gauge for labelValue
+func (g *GaugeVec) Set(labelValue string, value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[labelValue] = value
+}
+
+// Delete removes labelValue from the gauge
+func (g *GaugeVec) Delete(labelValue string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.values, labelValue)
+}
+
+// Value returns the current value for labelValue
+func (g *GaugeVec) Value(labelValue string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.values[labelValue]
+}
+
+func (g *GaugeVec) family() Family {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return Family{Name: g.name, Help: g.help, Label: g.label, Values: maps.Clone(g.values)}
+}
+
+func (g *GaugeVec) writeTo(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+	labelValues := make([]string, 0, len(g.values))
+	for labelValue := range g.values {
+		labelValues = append(labelValues, labelValue)
+	}
+	sort.Strings(labelValues)
+	for _, labelValue := range labelValues {
+		fmt.Fprintf(w, "%s{%s=%q} %g\n", g.name, g.label, labelValue, g.values[labelValue])
+	}
+}
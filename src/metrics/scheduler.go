@@ -0,0 +1,19 @@
+package metrics
+
+// Scheduler metrics, exposed on GET /metrics
+var (
+	// JobsEnqueued counts jobs enqueued by ScheduleJobs per command
+	JobsEnqueued = NewCounterVec("schedulerx_jobs_enqueued_total", "Jobs enqueued by the scheduler.", "command")
+
+	// JobsRejected counts jobs not enqueued because the job set reached MAX_QUEUED_JOBS, per command
+	JobsRejected = NewCounterVec("schedulerx_jobs_rejected_total", "Jobs rejected because the job queue is full.", "command")
+
+	// JobsOverdue counts jobs that started more than OVERDUE_TOLERANCE after their scheduled time, per command
+	JobsOverdue = NewCounterVec("schedulerx_jobs_overdue_total", "Jobs started later than the overdue tolerance after their scheduled time.", "command")
+
+	// JobLockContention counts job lock acquisitions skipped because another pod holds the lock
+	JobLockContention = NewCounter("schedulerx_job_lock_contention_total", "Job executions skipped because another pod holds the job lock.")
+
+	// CommandNeverFires is 1 for commands whose schedule has no occurrence within the next year
+	CommandNeverFires = NewGaugeVec("schedulerx_command_never_fires", "Whether a command's schedule has no occurrence within the next year.", "command")
+)
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+	"github.com/yashkumarverma/schedulerx/src/telemetry"
+)
+
+// bootstrapAfterSchedule enqueues a run of an "@after" command due now when the
+// command has nothing pending, e.g. on first start or after a lost follow-up
+func (s *Scheduler) bootstrapAfterSchedule(ctx context.Context, cmd command.Command, params []string) error {
+	pending, err := s.jobsForCommand(ctx, cmd.ID(), command.Scheduled, command.Assigned, command.Running)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return nil
+	}
+
+	job := command.NewJob(cmd.ID(), params, time.Now())
+	job.Labels = command.MergeLabels(cmd, nil)
+	job.TraceContext = telemetry.Inject(ctx)
+	if err := s.admitJob(ctx, job); err != nil {
+		return err
+	}
+	created, err := s.createJobFenced(ctx, job)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return nil
+	}
+	metrics.JobsEnqueued.Inc(cmd.ID())
+	s.jobScheduled(job)
+
+	job.Logger(s.logger).Info("Enqueued first run of @after schedule")
+	return nil
+}
+
+// scheduleNextAfterRun enqueues the next run of an "@after" command, due the
+// schedule's delay after the given job finished. Nothing is enqueued if the
+// command already has a job waiting, so ad-hoc runs do not multiply the schedule.
+func (s *Scheduler) scheduleNextAfterRun(ctx context.Context, job *command.Job) error {
+	cmd, exists := s.GetCommand(job.CommandID)
+	if !exists || job.FinishedAt == nil {
+		return nil
+	}
+
+	scheduleStr, params, err := cmd.Schedule()
+	if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+	schedule, err := NewParser().ParseSchedule(scheduleStr)
+	if err != nil {
+		return err
+	}
+	if schedule.Type != AfterSchedule {
+		return nil
+	}
+
+	pending, err := s.jobsForCommand(ctx, cmd.ID(), command.Scheduled, command.Assigned)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return nil
+	}
+
+	params, err = command.CoerceCommandParams(cmd, params)
+	if err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+
+	next := command.NewJob(cmd.ID(), params, job.FinishedAt.Add(schedule.Delay))
+	next.Labels = command.MergeLabels(cmd, nil)
+	if err := s.admitJob(ctx, next); err != nil {
+		return err
+	}
+	created, err := next.CreateInRedis(ctx, s.redisClient.GetClient())
+	if err != nil {
+		return fmt.Errorf("failed to store next run: %w", err)
+	}
+	if !created {
+		return nil
+	}
+	metrics.JobsEnqueued.Inc(cmd.ID())
+	s.jobScheduled(next)
+
+	job.Logger(s.logger).Info("Scheduled next run", "next_job_id", next.ID, "scheduled_at", next.ScheduledAt)
+	return nil
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// afterCommand returns a "report" command running 30 minutes after its previous run finished
+func afterCommand(fn command.Func) command.Command {
+	return command.NewFuncCommand("report", "test command", "@after 30m", fn)
+}
+
+// pendingReports returns the "report" jobs in the job set other than skip
+func pendingReports(t *testing.T, skip string) []*command.Job {
+	t.Helper()
+	var jobs []*command.Job
+	for _, id := range jobSet(t) {
+		if job := loadJob(t, id); job.ID != skip && job.CommandID == "report" {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+func TestAfterScheduleEnqueuesFirstRunOnce(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(afterCommand(nil))
+
+	before := time.Now()
+	for range 2 {
+		if err := s.ScheduleJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	jobs := pendingReports(t, "")
+	if len(jobs) != 1 {
+		t.Fatalf("%d runs enqueued, want a single first run", len(jobs))
+	}
+	if due := jobs[0].ScheduledAt; due.Before(before.Truncate(time.Millisecond)) || due.After(time.Now()) {
+		t.Fatalf("first run due at %v, want now", due)
+	}
+}
+
+func TestAfterScheduleFollowsCompletion(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		s := newTestScheduler(t, testConfig())
+		s.RegisterCommand(afterCommand(func(ctx context.Context, params []string) (string, error) {
+			if fail {
+				return "", errors.New("report failed")
+			}
+			return "done", nil
+		}))
+
+		// The previous run was due long ago, the next one follows when it finished
+		job := command.NewJob("report", nil, testNow().Add(-2*time.Hour))
+		job.AssignedTo, job.Status = testPodID, command.Assigned
+		storeJob(t, job)
+		if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		finished := loadJob(t, job.ID)
+		if finished.FinishedAt == nil {
+			t.Fatalf("run did not finish, status %s", finished.Status)
+		}
+		next := pendingReports(t, job.ID)
+		if len(next) != 1 {
+			t.Fatalf("%d next runs enqueued after a run with failure %v, want 1", len(next), fail)
+		}
+		if want := finished.FinishedAt.Add(30 * time.Minute); !next[0].ScheduledAt.Equal(want) {
+			t.Fatalf("next run due at %v, want 30m after the previous run finished at %v", next[0].ScheduledAt, finished.FinishedAt)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestBackpressureHysteresis(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.SchedulingHighWaterMark = 3
+	config.SchedulingLowWaterMark = 2
+	s := newTestScheduler(t, config)
+
+	now := testNow()
+	jobs := make([]*command.Job, 3)
+	for i := range jobs {
+		jobs[i] = command.NewJob("work", nil, now.Add(time.Duration(i)*time.Second))
+		storeJob(t, jobs[i])
+	}
+
+	steps := []struct {
+		queued int
+		paused bool
+	}{
+		{3, true},  // High-water mark reached
+		{2, true},  // Still at the low-water mark
+		{1, false}, // Drained below it
+	}
+	for _, step := range steps {
+		for _, job := range jobs[step.queued:] {
+			testRedis.ZRem(command.JobsSortedSetKey, job.ID)
+		}
+		paused, err := s.applyBackpressure(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paused != step.paused {
+			t.Fatalf("with %d queued jobs backpressure = %v, want %v", step.queued, paused, step.paused)
+		}
+	}
+}
+
+func TestScheduleJobsPausedByBackpressure(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.SchedulingHighWaterMark = 1
+	s := newTestScheduler(t, config)
+	storeJob(t, command.NewJob("work", nil, testNow()))
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ids := jobSet(t); len(ids) != 1 {
+		t.Fatalf("scheduling under backpressure enqueued %d jobs", len(ids)-1)
+	}
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// cancelLockTTL bounds how long CancelJobsForCommand holds a job lock, should it fail to release it
+const cancelLockTTL = time.Minute
+
+// CancelJobsForCommand cancels every scheduled or assigned job of a command and removes
+// it from the job set, returning how many were cancelled. Running jobs, and jobs a pod
+// is about to start, hold their job lock and are left to finish. A command that is still
+// registered gets new occurrences on the next scheduling pass, unregister it first to
+// decommission it.
+func (s *Scheduler) CancelJobsForCommand(ctx context.Context, commandID string) (int, error) {
+	jobs, err := s.jobsForCommand(ctx, commandID, command.Scheduled, command.Assigned)
+	if err != nil {
+		return 0, err
+	}
+
+	client := s.redisClient.GetClient()
+	cancelled := 0
+	for _, job := range jobs {
+		// Holding the job lock keeps pods from starting the job while it is cancelled
+		lockKey := fmt.Sprintf(jobLockKey, job.ID)
+		acquired, err := client.SetNX(ctx, lockKey, "cancel", cancelLockTTL).Result()
+		if err != nil {
+			return cancelled, fmt.Errorf("failed to lock job %s: %w", job.ID, err)
+		}
+		if !acquired {
+			job.Logger(s.logger).Info("Job is being executed, leaving it to finish")
+			continue
+		}
+
+		// The job may have run since it was listed, only cancel it if it is still outstanding
+		var current command.Job
+		err = s.redisClient.GetJSON(ctx, fmt.Sprintf(command.JobDetailsKey, job.ID), &current)
+		if err == nil && current.ID != "" && (current.Status == command.Scheduled || current.Status == command.Assigned) {
+			current.Cancel(fmt.Sprintf("all jobs of command %s cancelled", commandID))
+			err = current.UpdateInRedis(ctx, client)
+			if err == nil {
+				s.jobFinished(ctx, &current)
+				cancelled++
+			}
+		}
+		client.Del(ctx, lockKey)
+		if err != nil {
+			return cancelled, fmt.Errorf("failed to cancel job %s: %w", job.ID, err)
+		}
+	}
+
+	s.logger.Warn("Cancelled outstanding jobs of command", "command", commandID, "cancelled", cancelled)
+	return cancelled, nil
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestCancelJobsForCommandLeavesRunningJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	now := testNow()
+
+	scheduled := command.NewJob("work", nil, now)
+	assigned := command.NewJob("work", nil, now.Add(time.Second))
+	assigned.AssignedTo, assigned.Status = testPodID, command.Assigned
+	running := command.NewJob("work", nil, now.Add(2*time.Second))
+	running.AssignedTo = testPodID
+	running.Start()
+	other := command.NewJob("other", nil, now)
+	for _, job := range []*command.Job{scheduled, assigned, running, other} {
+		storeJob(t, job)
+	}
+	testRedis.Set("schedulerx:job_lock:"+running.ID, testPodID)
+
+	cancelled, err := s.CancelJobsForCommand(ctx, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != 2 {
+		t.Fatalf("cancelled %d jobs, want the scheduled and the assigned one", cancelled)
+	}
+	for _, job := range []*command.Job{scheduled, assigned} {
+		if stored := loadJob(t, job.ID); stored.Status != command.Cancelled {
+			t.Fatalf("job %s has status %s, want %s", job.ID, stored.Status, command.Cancelled)
+		}
+	}
+	if stored := loadJob(t, running.ID); stored.Status != command.Running {
+		t.Fatalf("running job has status %s, want it left running", stored.Status)
+	}
+	if ids := jobSet(t); !slices.Equal(ids, []string{other.ID, running.ID}) {
+		t.Fatalf("job set holds %q, want only the running job and the other command's job", ids)
+	}
+}
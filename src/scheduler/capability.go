@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// requiredCapabilities returns the capabilities a pod needs to run the command, none for unknown commands
+func (s *Scheduler) requiredCapabilities(commandID string) []string {
+	cmd, exists := s.GetCommand(commandID)
+	if !exists {
+		return nil
+	}
+	return command.RequiredCapabilities(cmd, s.config.CommandRequiredCapabilities)
+}
+
+// capabilityFilter returns a check whether a pod has the required capabilities. Pods
+// missing from the registry, e.g. in a simulation, count as capable, as do all pods
+// when the registry cannot be read.
+func (s *Scheduler) capabilityFilter(ctx context.Context) (func(podID string, required []string) bool, error) {
+	var registry map[string]leader.PodInfo
+	err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &registry)
+	return func(podID string, required []string) bool {
+		info, ok := registry[podID]
+		return !ok || info.HasCapabilities(required)
+	}, err
+}
+
+// leaderOnly reports whether jobs of the command may only run on the leader
+func (s *Scheduler) leaderOnly(commandID string) bool {
+	cmd, exists := s.GetCommand(commandID)
+	if !exists {
+		return false
+	}
+	provider, ok := cmd.(command.LeaderOnlyCommand)
+	return ok && provider.LeaderOnly()
+}
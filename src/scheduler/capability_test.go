@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestJobsOnlyAssignedToCapablePods(t *testing.T) {
+	config := testConfig()
+	config.NextJobCount = 10
+	config.CommandRequiredCapabilities = map[string][]string{"ping": {"ping"}, "archive": {"tar", "gzip"}}
+	s := newTestScheduler(t, config)
+	logs := observeLogs(s)
+	for _, id := range []string{"ping", "archive", "any"} {
+		s.RegisterCommand(funcCommand(id, nil))
+	}
+	setTestPods(t,
+		leader.PodInfo{ID: "pod-minimal", Capabilities: []string{}},
+		leader.PodInfo{ID: "pod-ping", Capabilities: []string{"ping", "tar"}},
+		leader.PodInfo{ID: "pod-unprobed"},
+	)
+	pods := []string{"pod-minimal", "pod-ping", "pod-unprobed"}
+
+	now := testNow()
+	var pingJobs, anyJobs []*command.Job
+	for i := range 3 {
+		pingJobs = append(pingJobs, command.NewJob("ping", nil, now.Add(-time.Duration(i+1)*time.Second)))
+		anyJobs = append(anyJobs, command.NewJob("any", nil, now.Add(-time.Duration(i+10)*time.Second)))
+	}
+	archive := command.NewJob("archive", nil, now.Add(-time.Minute))
+	for _, job := range append(append([]*command.Job{archive}, pingJobs...), anyJobs...) {
+		storeJob(t, job)
+	}
+	if err := s.AssignJobs(context.Background(), pods); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range pingJobs {
+		if podID := loadJob(t, job.ID).AssignedTo; podID != "pod-ping" && podID != "pod-unprobed" {
+			t.Errorf("ping job assigned to %q, want a pod with ping or one that did not probe", podID)
+		}
+	}
+	for _, job := range anyJobs {
+		if loadJob(t, job.ID).AssignedTo == "" {
+			t.Errorf("job without requirements was left unassigned")
+		}
+	}
+	if podID := loadJob(t, archive.ID).AssignedTo; podID != "pod-unprobed" {
+		t.Fatalf("archive job assigned to %q, want the only pod that may have tar and gzip", podID)
+	}
+
+	// Without the unprobed pod no pod has gzip
+	archive.AssignedTo, archive.Status = "", command.Scheduled
+	storeJob(t, archive)
+	if err := s.AssignJobs(context.Background(), pods[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if podID := loadJob(t, archive.ID).AssignedTo; podID != "" {
+		t.Fatalf("archive job assigned to %q without a capable pod", podID)
+	}
+	if logs.FilterMessageSnippet("No pod has the capabilities").Len() == 0 {
+		t.Fatal("job left without a capable pod was not logged")
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// enqueueFollowUps enqueues the OnSuccess or OnFailure commands of a finished job.
+// Follow-ups inherit the job's labels and are due immediately. Chains longer than
+// MaxChainDepth are cut off so commands triggering each other cannot loop forever.
+func (s *Scheduler) enqueueFollowUps(ctx context.Context, job *command.Job) error {
+	cmd, exists := s.GetCommand(job.CommandID)
+	if !exists {
+		return nil
+	}
+	chainer, ok := cmd.(command.Chainer)
+	if !ok {
+		return nil
+	}
+
+	var followUps []string
+	switch job.Status {
+	case command.Success:
+		followUps = chainer.OnSuccess()
+	case command.Failed:
+		followUps = chainer.OnFailure()
+	}
+	if len(followUps) == 0 {
+		return nil
+	}
+
+	if job.ChainDepth >= s.config.MaxChainDepth {
+		job.Logger(s.logger).Warn("Chain depth limit reached, not enqueuing follow-ups",
+			"chain_depth", job.ChainDepth, "follow_ups", followUps)
+		return nil
+	}
+
+	for _, followUpID := range followUps {
+		followUp, err := s.newImmediateJob(followUpID, nil, job.Labels)
+		if err != nil {
+			return fmt.Errorf("failed to create follow-up job for %s: %w", followUpID, err)
+		}
+		followUp.ParentJobID = job.ID
+		followUp.ChainDepth = job.ChainDepth + 1
+		// Follow-ups continue the trace of the job that enqueued them
+		followUp.TraceContext = job.TraceContext
+
+		if err := s.admitJob(ctx, followUp); err != nil {
+			return err
+		}
+
+		if err := s.createAdHocJob(ctx, followUp); err != nil {
+			return fmt.Errorf("failed to store follow-up job %s: %w", followUp.ID, err)
+		}
+		s.jobScheduled(followUp)
+		job.Logger(s.logger).Info("Enqueued follow-up job", "follow_up_job_id", followUp.ID, "follow_up_command", followUpID)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// chainedCommand is a "work" command enqueueing "report" on success and "alert" on failure
+type chainedCommand struct {
+	*command.FuncCommand
+}
+
+func (chainedCommand) OnSuccess() []string { return []string{"report"} }
+func (chainedCommand) OnFailure() []string { return []string{"alert"} }
+
+// runChainedJob runs a held "work" job at the given chain depth, failing it when fail is set,
+// and returns the commands of the follow-up jobs it enqueued
+func runChainedJob(t *testing.T, s *Scheduler, depth int, fail bool) []string {
+	t.Helper()
+	s.RegisterCommand(chainedCommand{funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		if fail {
+			return "", errors.New("upstream unavailable")
+		}
+		return "done", nil
+	})})
+	s.RegisterCommand(funcCommand("report", nil))
+	s.RegisterCommand(funcCommand("alert", nil))
+
+	job := command.NewJob("work", nil, testNow().Add(-time.Second))
+	job.AssignedTo, job.Status, job.ChainDepth = testPodID, command.Assigned, depth
+	storeJob(t, job)
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var followUps []string
+	for _, id := range jobSet(t) {
+		followUp := loadJob(t, id)
+		if followUp.ParentJobID != job.ID {
+			continue
+		}
+		if followUp.ChainDepth != depth+1 {
+			t.Fatalf("follow-up %s has chain depth %d, want %d", id, followUp.ChainDepth, depth+1)
+		}
+		followUps = append(followUps, followUp.CommandID)
+	}
+	return followUps
+}
+
+func TestFollowUpPerOutcome(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	if followUps := runChainedJob(t, s, 0, false); !slices.Equal(followUps, []string{"report"}) {
+		t.Fatalf("successful job enqueued %v, want report", followUps)
+	}
+
+	s = newTestScheduler(t, testConfig())
+	if followUps := runChainedJob(t, s, 0, true); !slices.Equal(followUps, []string{"alert"}) {
+		t.Fatalf("failed job enqueued %v, want alert", followUps)
+	}
+}
+
+func TestFollowUpStopsAtMaxChainDepth(t *testing.T) {
+	config := testConfig()
+	config.MaxChainDepth = 3
+	s := newTestScheduler(t, config)
+
+	if followUps := runChainedJob(t, s, 3, false); len(followUps) != 0 {
+		t.Fatalf("job at the depth limit enqueued %v, want no follow-ups", followUps)
+	}
+
+	s = newTestScheduler(t, config)
+	if followUps := runChainedJob(t, s, 2, false); !slices.Equal(followUps, []string{"report"}) {
+		t.Fatalf("job below the depth limit enqueued %v, want report", followUps)
+	}
+}
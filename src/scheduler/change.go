@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+const (
+	// outputHashKey stores the hash and output of a command's last run
+	outputHashKey = "schedulerx:output_hash:%s"
+
+	// outputHashTTL is how long the last output of a command is kept after its last run,
+	// a command that stops running starts over from a new baseline
+	outputHashTTL = 30 * 24 * time.Hour
+
+	// changeWebhookTimeout bounds how long a change notification may take
+	changeWebhookTimeout = 10 * time.Second
+)
+
+// OutputChange describes a change in a command's output between two runs
+type OutputChange struct {
+	CommandID    string `json:"command_id"`
+	JobID        string `json:"job_id"`
+	PreviousHash string `json:"previous_hash"`
+	Hash         string `json:"hash"`
+	Diff         string `json:"diff"`
+}
+
+// notifyOnChange compares the job's output with the previous run of its command
+// and notifies only when it differs. Only commands listed in NotifyOnChangeCommands are checked.
+func (s *Scheduler) notifyOnChange(ctx context.Context, job *command.Job) error {
+	if !slices.Contains(s.config.NotifyOnChangeCommands, job.CommandID) {
+		return nil
+	}
+
+	client := s.redisClient.GetClient()
+	key := fmt.Sprintf(outputHashKey, job.CommandID)
+
+	previous, err := client.HGetAll(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get last output of command %s: %w", job.CommandID, err)
+	}
+
+	sum := sha256.Sum256([]byte(job.Output))
+	hash := hex.EncodeToString(sum[:])
+
+	pipe := client.TxPipeline()
+	pipe.HSet(ctx, key, "hash", hash, "output", job.Output)
+	pipe.Expire(ctx, key, outputHashTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store output hash of command %s: %w", job.CommandID, err)
+	}
+
+	// The first run only records a baseline
+	if len(previous) == 0 || previous["hash"] == hash {
+		return nil
+	}
+
+	change := OutputChange{
+		CommandID:    job.CommandID,
+		JobID:        job.ID,
+		PreviousHash: previous["hash"],
+		Hash:         hash,
+		Diff:         diffLines(previous["output"], job.Output),
+	}
+
+	job.Logger(s.logger).Warn("Command output changed", "previous_hash", change.PreviousHash, "hash", change.Hash, "diff", change.Diff)
+
+	if s.config.ChangeWebhookURL == "" {
+		return nil
+	}
+	return postChange(ctx, s.config.ChangeWebhookURL, change)
+}
+
+// postChange sends the change notification to the webhook as JSON
+func postChange(ctx context.Context, url string, change OutputChange) error {
+	body, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output change: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, changeWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create change notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send change notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("change notification rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// diffLines returns the lines removed from and added to the output, prefixed with - and +
+func diffLines(previous, current string) string {
+	previousLines := strings.Split(previous, "\n")
+	currentLines := strings.Split(current, "\n")
+
+	var diff strings.Builder
+	for _, line := range previousLines {
+		if line != "" && !slices.Contains(currentLines, line) {
+			diff.WriteString("-" + line + "\n")
+		}
+	}
+	for _, line := range currentLines {
+		if line != "" && !slices.Contains(previousLines, line) {
+			diff.WriteString("+" + line + "\n")
+		}
+	}
+	return diff.String()
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestNotifyOnChangeOnlyPostsChanges(t *testing.T) {
+	var changes []OutputChange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var change OutputChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			t.Error(err)
+		}
+		changes = append(changes, change)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	config := testConfig()
+	config.NotifyOnChangeCommands = []string{"du"}
+	config.ChangeWebhookURL = server.URL
+	s := newTestScheduler(t, config)
+
+	for _, output := range []string{"4.0K\t/var\n", "4.0K\t/var\n", "8.0K\t/var\n"} {
+		job := command.NewJob("du", nil, testNow())
+		job.Output = output
+		if err := s.notifyOnChange(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("posted %d changes, want 1 for the single change", len(changes))
+	}
+	if !strings.Contains(changes[0].Diff, "8.0K") || !strings.Contains(changes[0].Diff, "4.0K") {
+		t.Fatalf("diff %q does not show the changed lines", changes[0].Diff)
+	}
+	if ttl := testRedis.TTL("schedulerx:output_hash:du"); ttl != outputHashTTL {
+		t.Fatalf("output hash expires in %s, want %s", ttl, outputHashTTL)
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// retainCompleted records a finished job in the completed sorted set, scored by
+// its finish time, and drops entries older than COMPLETED_JOB_RETENTION. Job
+// details are kept for at least as long as the job is retained.
+func (s *Scheduler) retainCompleted(ctx context.Context, job *command.Job) error {
+	retention := s.config.CompletedJobRetention
+	if retention <= 0 || job.FinishedAt == nil {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-retention)
+	pipe := s.redisClient.GetClient().Pipeline()
+	pipe.ZAdd(ctx, command.CompletedJobsKey, redis.Z{
+		Score:  command.JobScore(*job.FinishedAt),
+		Member: job.ID,
+	})
+	pipe.ZRemRangeByScore(ctx, command.CompletedJobsKey, "-inf", "("+strconv.FormatInt(cutoff.UnixMilli(), 10))
+	if retention > command.JobDetailsTTL {
+		pipe.Expire(ctx, fmt.Sprintf(command.JobDetailsKey, job.ID), retention)
+		pipe.Expire(ctx, fmt.Sprintf(command.JobOutputKey, job.ID), retention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to retain completed job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// CompletedJobs returns the retained jobs that finished within [from, to], oldest
+// first. A zero from or to leaves that end of the range open.
+func (s *Scheduler) CompletedJobs(ctx context.Context, from, to time.Time) ([]*command.Job, error) {
+	min, max := "-inf", "+inf"
+	if !from.IsZero() {
+		min = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+	if !to.IsZero() {
+		max = strconv.FormatInt(to.UnixMilli(), 10)
+	}
+
+	client := s.redisClient.GetClient()
+	jobIDs, err := client.ZRangeByScore(ctx, command.CompletedJobsKey, &redis.ZRangeBy{Min: min, Max: max}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch completed jobs: %w", err)
+	}
+
+	return s.loadJobs(ctx, jobIDs)
+}
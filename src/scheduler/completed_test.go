@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// finishedJob stores a successful "work" job that finished at finishedAt and retains it
+func finishedJob(t *testing.T, s *Scheduler, finishedAt time.Time) *command.Job {
+	t.Helper()
+	job := command.NewJob("work", nil, finishedAt.Add(-time.Minute))
+	job.Status, job.FinishedAt = command.Success, &finishedAt
+	storeJob(t, job)
+	if err := s.retainCompleted(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestCompletedJobsAreRetained(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CompletedJobRetention = 48 * time.Hour
+	s := newTestScheduler(t, config)
+	countRuns(s)
+	job := heldJob(t, testNow(), command.Assigned)
+
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	completed, err := s.CompletedJobs(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completed) != 1 || completed[0].ID != job.ID || completed[0].Status != command.Success {
+		t.Fatalf("CompletedJobs = %v, want the finished job %s", completed, job.ID)
+	}
+	if ttl := testRedis.TTL(fmt.Sprintf(command.JobDetailsKey, job.ID)); ttl != 48*time.Hour {
+		t.Fatalf("details of a retained job expire in %s, want the 48h retention", ttl)
+	}
+}
+
+func TestCompletedJobsExpireAndQueryByRange(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CompletedJobRetention = time.Hour
+	s := newTestScheduler(t, config)
+	now := time.Now()
+
+	expired := finishedJob(t, s, now.Add(-2*time.Hour))
+	older := finishedJob(t, s, now.Add(-30*time.Minute))
+	recent := finishedJob(t, s, now.Add(-5*time.Minute))
+	latest := finishedJob(t, s, now)
+
+	all, err := s.CompletedJobs(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, job := range all {
+		ids = append(ids, job.ID)
+	}
+	if len(ids) != 3 || ids[0] != older.ID || ids[2] != latest.ID {
+		t.Fatalf("retained %v, want %s, %s and %s without the expired %s", ids, older.ID, recent.ID, latest.ID, expired.ID)
+	}
+
+	window, err := s.CompletedJobs(ctx, now.Add(-10*time.Minute), now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(window) != 1 || window[0].ID != recent.ID {
+		t.Fatalf("jobs finished in the last 10 to 1 minutes = %v, want %s", window, recent.ID)
+	}
+}
+
+func TestCompletedJobsNotRetainedByDefault(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	finishedJob(t, s, time.Now())
+	if testRedis.Exists(command.CompletedJobsKey) {
+		t.Fatal("finished job was retained without COMPLETED_JOB_RETENTION")
+	}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// cooldownKey exists while a command is cooling down, holding the job that started it
+const cooldownKey = "schedulerx:cooldown:%s"
+
+// claimCooldown starts the command's COMMAND_COOLDOWN for the job about to run. When an
+// earlier job started within the cooldown it returns how long the job has to wait instead.
+// Claiming is atomic, so two pods cannot start jobs of the same command within the cooldown.
+func (s *Scheduler) claimCooldown(ctx context.Context, job *command.Job) (time.Duration, error) {
+	cooldown := s.config.CommandCooldown[job.CommandID]
+	if cooldown <= 0 {
+		return 0, nil
+	}
+
+	client := s.redisClient.GetClient()
+	key := fmt.Sprintf(cooldownKey, job.CommandID)
+	claimed, err := client.SetNX(ctx, key, job.ID, cooldown).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim cooldown of command %s: %w", job.CommandID, err)
+	}
+	if claimed {
+		return 0, nil
+	}
+
+	remaining, err := client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cooldown of command %s: %w", job.CommandID, err)
+	}
+	if remaining <= 0 {
+		// Expired between the two calls, or stored without a TTL, wait a whole cooldown to be safe
+		remaining = cooldown
+	}
+	return remaining, nil
+}
+
+// deferJob unassigns a job and moves it to the end of its command's cooldown,
+// so it is assigned again once it may run rather than dropped
+func (s *Scheduler) deferJob(ctx context.Context, job *command.Job, wait time.Duration) error {
+	job.AssignedTo = ""
+	job.Status = command.Scheduled
+	job.ScheduledAt = time.Now().Add(wait)
+	if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
+		return fmt.Errorf("failed to defer job %s: %w", job.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestTriggeredJobWithinCooldownIsDeferred(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CommandCooldown = map[string]time.Duration{"work": time.Minute}
+	s := newTestScheduler(t, config)
+	runs := 0
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		runs++
+		return "", nil
+	}))
+
+	first, err := s.TriggerJob(ctx, "work", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.TriggerJob(ctx, "work", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range []*command.Job{first, second} {
+		job.AssignedTo, job.Status = testPodID, command.Assigned
+		storeJob(t, job)
+	}
+	// A trigger in the same millisecond moves to the next one, wait until both are due
+	time.Sleep(time.Until(second.ScheduledAt))
+
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Fatalf("%d jobs ran, want only the first within the cooldown", runs)
+	}
+	deferred := loadJob(t, second.ID)
+	if deferred.Status != command.Scheduled || deferred.AssignedTo != "" {
+		t.Fatalf("second job is %s on %q, want it unassigned and scheduled", deferred.Status, deferred.AssignedTo)
+	}
+	if wait := time.Until(deferred.ScheduledAt); wait < 50*time.Second {
+		t.Fatalf("second job deferred by %s, want about the 1m cooldown", wait)
+	}
+
+	// The deferral survives scheduling and assignment passes, and the job does not start early
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Fatal("deferred job ran before the cooldown ended")
+	}
+	if stored := loadJob(t, second.ID); !stored.ScheduledAt.Equal(deferred.ScheduledAt) {
+		t.Fatalf("deferral was overwritten, due at %s instead of %s", stored.ScheduledAt, deferred.ScheduledAt)
+	}
+}
+
+func TestDeferredOccurrenceSurvivesScheduling(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	occurrence := loadJob(t, jobSet(t)[0])
+	if err := s.deferJob(ctx, occurrence, 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	deferredAt := loadJob(t, occurrence.ID).ScheduledAt
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored := loadJob(t, occurrence.ID); !stored.ScheduledAt.Equal(deferredAt) {
+		t.Fatalf("scheduling moved the deferred occurrence back to %s", stored.ScheduledAt)
+	}
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// discardCorruptJob logs a job whose details cannot be decoded and removes it,
+// quarantining the raw data when QUARANTINE_CORRUPT_JOBS is set
+func (s *Scheduler) discardCorruptJob(ctx context.Context, jobID string, data []byte, decodeErr error) {
+	s.logger.Error("Discarding undecodable job",
+		"job_id", jobID,
+		"quarantined", s.config.QuarantineCorruptJobs,
+		"error", decodeErr,
+	)
+	if err := command.DiscardCorruptJob(ctx, s.redisClient.GetClient(), jobID, data, s.config.QuarantineCorruptJobs); err != nil {
+		s.logger.Error("Failed to discard undecodable job", "job_id", jobID, "error", err)
+	}
+}
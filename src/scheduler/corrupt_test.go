@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// seedCorruptJob stores a job set member whose details are not valid JSON
+func seedCorruptJob(t *testing.T, jobID string) {
+	t.Helper()
+	ctx := context.Background()
+	client := testClient.GetClient()
+	if err := client.Set(ctx, fmt.Sprintf(command.JobDetailsKey, jobID), "{not json", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: command.JobScore(testNow()), Member: jobID}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCorruptJobIsQuarantined(t *testing.T) {
+	for _, quarantine := range []bool{true, false} {
+		t.Run(fmt.Sprintf("quarantine=%v", quarantine), func(t *testing.T) {
+			config := testConfig()
+			config.QuarantineCorruptJobs = quarantine
+			s := newTestScheduler(t, config)
+			logs := observeLogs(s)
+			countRuns(s)
+			seedCorruptJob(t, "corrupt_1")
+
+			if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if ids := jobSet(t); len(ids) != 0 {
+				t.Fatalf("job set still holds %v", ids)
+			}
+			if testRedis.Exists(fmt.Sprintf(command.JobDetailsKey, "corrupt_1")) {
+				t.Fatal("details of the corrupt job were kept")
+			}
+			raw := testRedis.HGet(command.CorruptJobsKey, "corrupt_1")
+			if quarantine && raw != "{not json" {
+				t.Fatalf("quarantined data = %q, want the raw job details", raw)
+			}
+			if !quarantine && testRedis.Exists(command.CorruptJobsKey) {
+				t.Fatal("corrupt job was quarantined with quarantining disabled")
+			}
+			if logs.FilterMessageSnippet("Discarding undecodable job").Len() != 1 {
+				t.Fatal("corrupt job was not logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestDrainedClusterStartsNoJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	runs := countRuns(s)
+	s.RegisterCommand(everyMinute("tick"))
+	due := heldJob(t, testNow().Add(-time.Second), command.Assigned)
+
+	// Nothing runs, so the drain completes right away
+	if err := s.DrainCluster(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 0 || len(jobSet(t)) != 1 {
+		t.Fatalf("drained cluster ran %d jobs and holds %d, want the due job left alone and nothing scheduled", *runs, len(jobSet(t)))
+	}
+
+	if err := s.UndrainCluster(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 || loadJob(t, due.ID).Status != command.Success {
+		t.Fatalf("due job ran %d times after the drain was lifted, want 1", *runs)
+	}
+}
+
+func TestDrainClusterWaitsForRunningJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	running := heldJob(t, testNow().Add(-time.Minute), command.Running)
+
+	drained := make(chan error, 1)
+	go func() { drained <- s.DrainCluster(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		status, err := s.ClusterDrainStatus(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Draining {
+			if status.Running != 1 || status.Drained {
+				t.Fatalf("drain status %+v, want one job still running", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("drain did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-drained:
+		t.Fatalf("drain finished with %v while a job was running", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	running.Start()
+	running.Complete()
+	storeJob(t, running)
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain did not finish after the running job completed")
+	}
+	if status, err := s.ClusterDrainStatus(ctx); err != nil || !status.Drained {
+		t.Fatalf("drain status %+v, %v after the running job completed, want drained", status, err)
+	}
+}
+
+func TestPausedPodHoldsJobsWithoutStarting(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	runs := countRuns(s)
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true, Paused: true})
+	due := command.NewJob("work", nil, testNow().Add(-time.Second))
+	storeJob(t, due)
+
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored := loadJob(t, due.ID); *runs != 0 || stored.AssignedTo != testPodID || stored.Status != command.Assigned {
+		t.Fatalf("paused pod ran %d jobs and holds the job as %s on %q, want it assigned and not started", *runs, stored.Status, stored.AssignedTo)
+	}
+
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true})
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatalf("resumed pod ran %d jobs, want the held job", *runs)
+	}
+}
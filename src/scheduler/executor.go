@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// executeJob runs the job's command and records its output on the job. Commands
+// with a result cache TTL reuse the output of an identical successful run instead.
+func (s *Scheduler) executeJob(ctx context.Context, job *command.Job) (err error) {
+	ctx, span := s.startExecutionSpan(ctx, job)
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	output, hit, err := s.cachedResult(ctx, job)
+	if err != nil {
+		job.Logger(s.logger).Warn("Failed to read result cache", "error", err)
+	}
+	if hit {
+		job.Output = output
+		job.CacheHit = true
+		return nil
+	}
+
+	// Secrets are masked before the output is stored, logged or sent anywhere
+	err = s.runJob(ctx, job)
+	if code, ok := command.ExitCode(err); ok && code != 0 && slices.Contains(s.successExitCodes(job.CommandID), code) {
+		job.Logger(s.logger).Info("Command exited with a success exit code", "exit_code", code)
+		err = nil
+	}
+	job.Output = s.redactor.redact(job.CommandID, job.Output)
+	if err != nil {
+		return s.redactor.redactError(job.CommandID, err)
+	}
+
+	if err := s.cacheResult(ctx, job); err != nil {
+		job.Logger(s.logger).Warn("Failed to store result in cache", "error", err)
+	}
+	return nil
+}
+
+// startExecutionSpan starts the span of a job execution as a child of the span that
+// created the job, which may have been on another pod. Jobs without a trace context
+// start a new trace.
+func (s *Scheduler) startExecutionSpan(ctx context.Context, job *command.Job) (context.Context, trace.Span) {
+	if created := telemetry.Extract(job.TraceContext); created.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, created)
+	}
+	return telemetry.Tracer().Start(ctx, "executeJob", trace.WithAttributes(
+		attribute.String("schedulerx.job_id", job.ID),
+		attribute.String("schedulerx.command", job.CommandID),
+	))
+}
+
+// storeFinishedJob writes a job that just ran, with its output under a key of its own when SEPARATE_JOB_OUTPUT is set
+func (s *Scheduler) storeFinishedJob(ctx context.Context, job *command.Job) error {
+	if s.config.SeparateJobOutput {
+		return job.UpdateInRedisSeparateOutput(ctx, s.redisClient.GetClient())
+	}
+	return job.UpdateInRedis(ctx, s.redisClient.GetClient())
+}
+
+// runJob runs the job's command and records its output on the job.
+// Commands supporting cancellation are stopped when ctx is done or their
+// timeout expires. Other commands cannot be stopped, once their timeout
+// expires the job fails and the command is left to finish in the background.
+func (s *Scheduler) runJob(ctx context.Context, job *command.Job) error {
+	cmd, exists := s.GetCommand(job.CommandID)
+	if !exists {
+		return fmt.Errorf("unknown command: %s", job.CommandID)
+	}
+	timeout := s.commandTimeout(cmd, job.ExecutionParams())
+
+	if contextCmd, ok := cmd.(command.ContextCommand); ok {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		output, err := contextCmd.ExecuteContext(ctx, job.ExecutionParams())
+		job.Output = output
+		if err == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("command timed out after %s", timeout)
+		}
+		return err
+	}
+
+	if timeout <= 0 {
+		output, err := runCommand(cmd, job.ExecutionParams())
+		job.Output = output
+		return err
+	}
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := runCommand(cmd, job.ExecutionParams())
+		done <- result{output: output, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		job.Output = r.output
+		return r.err
+	case <-timer.C:
+		return fmt.Errorf("command timed out after %s", timeout)
+	}
+}
+
+// runCommand runs cmd without a context, returning its output when the command provides it
+func runCommand(cmd command.Command, params []string) (string, error) {
+	if outputCmd, ok := cmd.(command.OutputCommand); ok {
+		return outputCmd.ExecuteWithOutput(params)
+	}
+	return "", cmd.Execute(params)
+}
+
+// defaultSuccessExitCodes are the exit codes treated as success for commands that do not configure their own
+var defaultSuccessExitCodes = []int{0}
+
+// successExitCodes returns the exit codes treated as success for a command, from
+// CMD_SUCCESS_EXIT_CODES_<command id> or else the command's SuccessExitCodes
+func (s *Scheduler) successExitCodes(commandID string) []int {
+	if codes, ok := s.config.CommandSuccessExitCodes[commandID]; ok {
+		return codes
+	}
+	if cmd, exists := s.GetCommand(commandID); exists {
+		if provider, ok := cmd.(command.SuccessExitCodesProvider); ok {
+			return provider.SuccessExitCodes()
+		}
+	}
+	return defaultSuccessExitCodes
+}
+
+// commandTimeout returns the command's own timeout for a run with params, falling back to DEFAULT_COMMAND_TIMEOUT_SECONDS
+func (s *Scheduler) commandTimeout(cmd command.Command, params []string) time.Duration {
+	if timeout := command.CommandTimeout(cmd, params); timeout > 0 {
+		return timeout
+	}
+	return time.Duration(s.config.DefaultCommandTimeoutSeconds) * time.Second
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// timeoutCommand is a function command with a timeout of its own
+type timeoutCommand struct {
+	*command.FuncCommand
+	timeout time.Duration
+}
+
+func (c *timeoutCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
+// blockingCommand cannot be cancelled, Execute returns once release is closed
+type blockingCommand struct {
+	release chan struct{}
+}
+
+func (c *blockingCommand) ID() string                          { return "blocking" }
+func (c *blockingCommand) Description() string                 { return "test command" }
+func (c *blockingCommand) Schedule() (string, []string, error) { return "", nil, nil }
+func (c *blockingCommand) Parameters() []string                { return nil }
+
+func (c *blockingCommand) Execute(params []string) error {
+	<-c.release
+	return nil
+}
+
+// waitForCancel runs until ctx is done, at most 10 seconds
+func waitForCancel(ctx context.Context, params []string) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(10 * time.Second):
+		return "finished", nil
+	}
+}
+
+func TestDefaultCommandTimeout(t *testing.T) {
+	config := testConfig()
+	config.DefaultCommandTimeoutSeconds = 1
+	s := newTestScheduler(t, config)
+	blocking := &blockingCommand{release: make(chan struct{})}
+	t.Cleanup(func() { close(blocking.release) })
+	s.RegisterCommand(funcCommand("waits", waitForCancel))
+	s.RegisterCommand(blocking)
+
+	for _, id := range []string{"waits", "blocking"} {
+		start := time.Now()
+		err := s.executeJob(context.Background(), command.NewJob(id, nil, testNow()))
+		if err == nil {
+			t.Fatalf("%s outlived the default timeout", id)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("%s returned after %s, want the 1s default timeout", id, elapsed)
+		}
+	}
+}
+
+func TestCommandTimeoutOverridesDefault(t *testing.T) {
+	config := testConfig()
+	config.DefaultCommandTimeoutSeconds = 30
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(&timeoutCommand{FuncCommand: funcCommand("waits", waitForCancel), timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	err := s.executeJob(context.Background(), command.NewJob("waits", nil, testNow()))
+	if err == nil {
+		t.Fatal("command outlived its own timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("command returned after %s, want its 100ms timeout", elapsed)
+	}
+	if !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("executeJob = %v, want the deadline exceeded", err)
+	}
+}
+
+func TestSeparateJobOutput(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.SeparateJobOutput = true
+	s := newTestScheduler(t, config)
+	countRuns(s)
+	job := heldJob(t, testNow().Add(-time.Second), command.Assigned)
+
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored := loadJob(t, job.ID); stored.Status != command.Success || stored.Output != "" {
+		t.Fatalf("details stored as %s with output %q, want success without the output", stored.Status, stored.Output)
+	}
+	if output, err := command.GetJobOutput(ctx, testClient.GetClient(), job.ID); err != nil || output != "done" {
+		t.Fatalf("GetJobOutput = %q, %v, want the command's output", output, err)
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// exitWith returns a command function whose process exits with code
+func exitWith(code int) command.Func {
+	return func(ctx context.Context, params []string) (string, error) {
+		return "", exec.Command("sh", "-c", "exit "+strconv.Itoa(code)).Run()
+	}
+}
+
+// grepCommand declares exit code 1 a success, as grep exits 1 when nothing matches
+type grepCommand struct {
+	*command.FuncCommand
+}
+
+func (c *grepCommand) SuccessExitCodes() []int {
+	return []int{0, 1}
+}
+
+func TestSuccessExitCodes(t *testing.T) {
+	config := testConfig()
+	config.CommandSuccessExitCodes = map[string][]int{"configured": {0, 1}}
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("configured", exitWith(1)))
+	s.RegisterCommand(&grepCommand{funcCommand("grep", exitWith(1))})
+	s.RegisterCommand(funcCommand("unlisted", exitWith(2)))
+	s.RegisterCommand(&grepCommand{funcCommand("grep-error", exitWith(2))})
+	s.RegisterCommand(funcCommand("default", exitWith(1)))
+
+	want := map[string]command.JobStatus{
+		"configured": command.Success,
+		"grep":       command.Success,
+		"unlisted":   command.Failed,
+		"grep-error": command.Failed,
+		"default":    command.Failed,
+	}
+	jobIDs := make(map[string]string, len(want))
+	for commandID := range want {
+		job := command.NewJob(commandID, nil, testNow())
+		job.AssignedTo, job.Status = testPodID, command.Assigned
+		storeJob(t, job)
+		jobIDs[commandID] = job.ID
+	}
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for commandID, status := range want {
+		if job := loadJob(t, jobIDs[commandID]); job.Status != status {
+			t.Errorf("%s exiting with its code finished as %s, want %s", commandID, job.Status, status)
+		}
+	}
+}
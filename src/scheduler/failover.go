@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// lastFailedPodKey holds the pod a command last failed on
+	lastFailedPodKey = "schedulerx:last_failed_pod:%s"
+
+	// lastFailedPodTTL bounds how long a failure keeps a pod deprioritized
+	lastFailedPodTTL = 24 * time.Hour
+)
+
+// recordFailedPod remembers the pod a command failed on so its next job prefers another pod
+func (s *Scheduler) recordFailedPod(ctx context.Context, commandID, podID string) error {
+	key := fmt.Sprintf(lastFailedPodKey, commandID)
+	if err := s.redisClient.GetClient().Set(ctx, key, podID, lastFailedPodTTL).Err(); err != nil {
+		return fmt.Errorf("failed to record failed pod for command %s: %w", commandID, err)
+	}
+	return nil
+}
+
+// clearFailedPod forgets the failed pod once the command succeeds again
+func (s *Scheduler) clearFailedPod(ctx context.Context, commandID string) error {
+	key := fmt.Sprintf(lastFailedPodKey, commandID)
+	if err := s.redisClient.GetClient().Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to clear failed pod for command %s: %w", commandID, err)
+	}
+	return nil
+}
+
+// lastFailedPod returns the pod a command last failed on, empty if none is recorded
+func (s *Scheduler) lastFailedPod(ctx context.Context, commandID string) (string, error) {
+	key := fmt.Sprintf(lastFailedPodKey, commandID)
+	podID, err := s.redisClient.GetClient().Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get failed pod for command %s: %w", commandID, err)
+	}
+	return podID, nil
+}
+
+// avoidFailedPod picks the next pod in rotation when the chosen one last failed the
+// job's command. With a single pod there is nothing to fall back to, so it is kept.
+func (s *Scheduler) avoidFailedPod(ctx context.Context, commandID string, pods []string, podID string) string {
+	podIndex := slices.Index(pods, podID)
+	if len(pods) < 2 || podIndex < 0 {
+		return podID
+	}
+
+	failedPod, err := s.lastFailedPod(ctx, commandID)
+	if err != nil {
+		s.logger.Error("Failed to get last failed pod", "command", commandID, "error", err)
+		return podID
+	}
+	if failedPod != podID {
+		return podID
+	}
+	return pods[(podIndex+1)%len(pods)]
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// failOnTestPod runs a failing "work" job on the test pod, recording it as the pod the command last failed on
+func failOnTestPod(t *testing.T, s *Scheduler) {
+	t.Helper()
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		return "", errors.New("disk full")
+	}))
+	heldJob(t, testNow().Add(-time.Second), command.Assigned)
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assignRetry stores a pending "work" job, assigns it across pods and returns the pod it went to
+func assignRetry(t *testing.T, s *Scheduler, pods ...string) string {
+	t.Helper()
+	retry := command.NewJob("work", nil, testNow())
+	storeJob(t, retry)
+	if err := s.AssignJobs(context.Background(), pods); err != nil {
+		t.Fatal(err)
+	}
+	return loadJob(t, retry.ID).AssignedTo
+}
+
+func TestRetryAfterFailurePrefersAnotherPod(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	// Round-robin starts at the test pod, sorting before worker-pod
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "worker-pod"})
+	failOnTestPod(t, s)
+
+	if pod := assignRetry(t, s, testPodID, "worker-pod"); pod != "worker-pod" {
+		t.Fatalf("retry assigned to %q, want worker-pod as the command failed on %s", pod, testPodID)
+	}
+}
+
+func TestRetryAfterFailureStaysOnOnlyPod(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	failOnTestPod(t, s)
+
+	if pod := assignRetry(t, s, testPodID); pod != testPodID {
+		t.Fatalf("retry assigned to %q, want the only pod %s", pod, testPodID)
+	}
+}
+
+func TestSuccessClearsFailedPod(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	failOnTestPod(t, s)
+
+	countRuns(s)
+	heldJob(t, testNow().Add(-time.Second), command.Assigned)
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pod, err := s.lastFailedPod(ctx, "work"); err != nil || pod != "" {
+		t.Fatalf("last failed pod is %q (%v) after a success, want none", pod, err)
+	}
+}
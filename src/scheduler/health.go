@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// weightedPods repeats each pod by its health slots, interleaved so that round-robin
+// over the result gives degraded pods proportionally fewer jobs. Pods missing from
+// the registry, e.g. in a simulation, count as healthy.
+func (s *Scheduler) weightedPods(ctx context.Context, pods []string) []string {
+	var registry map[string]leader.PodInfo
+	if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &registry); err != nil {
+		s.logger.Warn("Failed to read pod health, assigning evenly", "error", err)
+		return pods
+	}
+
+	ttl, staleness := leader.PodTTL(s.config), leader.LeadershipStaleness(s.config)
+	slots := make([]int, len(pods))
+	for i, podID := range pods {
+		slots[i] = leader.HealthSlots(1)
+		if info, ok := registry[podID]; ok {
+			slots[i] = leader.HealthSlots(info.HealthScore(ttl, staleness))
+		}
+	}
+	if len(slots) == 0 || slices.Min(slots) == slices.Max(slots) {
+		return pods
+	}
+
+	weighted := make([]string, 0, len(pods)*slices.Max(slots))
+	for round := range slices.Max(slots) {
+		for i, podID := range pods {
+			if slots[i] > round {
+				weighted = append(weighted, podID)
+			}
+		}
+	}
+	return weighted
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestFailingPodReceivesFewerAssignments(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+	setTestPods(t,
+		leader.PodInfo{ID: "pod-healthy", RecentJobs: 50},
+		leader.PodInfo{ID: "pod-failing", RecentJobs: 50, RecentFailures: 40},
+	)
+
+	now := testNow()
+	for i := range 20 {
+		storeJob(t, command.NewJob("work", nil, now.Add(-time.Duration(i+1)*time.Second)))
+	}
+	if err := s.AssignJobs(context.Background(), []string{"pod-healthy", "pod-failing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(map[string]int)
+	for _, id := range jobSet(t) {
+		received[loadJob(t, id).AssignedTo]++
+	}
+	if received["pod-failing"] == 0 || received["pod-failing"]*2 >= received["pod-healthy"] {
+		t.Fatalf("assigned %v, want the failing pod to receive some but far fewer jobs", received)
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+const (
+	// neverFiresHorizon is how far ahead a cron schedule must have an occurrence
+	// to not be reported as effectively never running, e.g. "0 0 29 2 *"
+	neverFiresHorizon = 366 * 24 * time.Hour
+
+	// neverFiresCheckInterval is how often the leader re-checks schedules against neverFiresHorizon
+	neverFiresCheckInterval = time.Hour
+)
+
+// CheckNeverFiring returns the registered commands whose cron schedule has no
+// occurrence within the next year and updates schedulerx_command_never_fires.
+// Empty, one-off @at and @after schedules are not checked.
+func (s *Scheduler) CheckNeverFiring(ctx context.Context) []string {
+	parser := NewParser()
+	now := time.Now()
+
+	var neverFiring []string
+	for cmdID, cmd := range s.Commands() {
+		scheduleStr, _, err := cmd.Schedule()
+		if err != nil {
+			continue
+		}
+		schedule, err := parser.ParseSchedule(scheduleStr)
+		if err != nil {
+			// Invalid schedules are already reported by ScheduleJobs
+			continue
+		}
+		if schedule.Type == NoSchedule || schedule.Type == AtSchedule || schedule.Type == AfterSchedule {
+			metrics.CommandNeverFires.Delete(cmdID)
+			continue
+		}
+
+		next := schedule.Next(now)
+		if !next.IsZero() && next.Before(now.Add(neverFiresHorizon)) {
+			metrics.CommandNeverFires.Set(cmdID, 0)
+			continue
+		}
+
+		metrics.CommandNeverFires.Set(cmdID, 1)
+		neverFiring = append(neverFiring, cmdID)
+		if next.IsZero() {
+			s.logger.Warn("Schedule never fires", "command", cmdID, "schedule", scheduleStr)
+		} else {
+			s.logger.Warn("Schedule does not fire within the next year", "command", cmdID, "schedule", scheduleStr, "next", next)
+		}
+	}
+	return neverFiring
+}
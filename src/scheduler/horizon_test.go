@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+// nextLeapDay returns the next February 29th midnight after now
+func nextLeapDay(now time.Time) time.Time {
+	for year := now.Year(); ; year++ {
+		day := time.Date(year, time.February, 29, 0, 0, 0, 0, now.Location())
+		if day.Month() == time.February && day.After(now) {
+			return day
+		}
+	}
+}
+
+func TestCheckNeverFiringFlagsRareSchedules(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	logs := observeLogs(s)
+	s.RegisterCommand(everyMinute("tick"))
+	s.RegisterCommand(command.NewFuncCommand("yearly", "test command", "0 0 1 1 *", nil))
+	s.RegisterCommand(command.NewFuncCommand("leap-day", "test command", "0 0 29 2 *", nil))
+	s.RegisterCommand(command.NewFuncCommand("never", "test command", "0 0 30 2 *", nil))
+	s.RegisterCommand(funcCommand("manual", nil))
+
+	want := []string{"never"}
+	// February 29th is within a year only in the run-up to a leap year
+	leapDayFlagged := !nextLeapDay(time.Now()).Before(time.Now().Add(neverFiresHorizon))
+	if leapDayFlagged {
+		want = append(want, "leap-day")
+	}
+
+	got := s.CheckNeverFiring(context.Background())
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("CheckNeverFiring = %q, want %q", got, want)
+	}
+
+	gauges := map[string]float64{"tick": 0, "yearly": 0, "never": 1}
+	if leapDayFlagged {
+		gauges["leap-day"] = 1
+	}
+	for cmdID, value := range gauges {
+		if got := metrics.CommandNeverFires.Value(cmdID); got != value {
+			t.Errorf("schedulerx_command_never_fires{command=%q} = %g, want %g", cmdID, got, value)
+		}
+	}
+	if logs.FilterMessageSnippet("Schedule never fires").Len() != 1 {
+		t.Fatal("schedule that never fires was not warned about")
+	}
+	if leapDayFlagged && logs.FilterMessageSnippet("Schedule does not fire within the next year").Len() != 1 {
+		t.Fatal("leap day schedule was not warned about")
+	}
+}
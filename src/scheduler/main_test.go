@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// testPodID is the ID the pod manager of the test binary registers with
+const testPodID = "test-pod"
+
+var (
+	testRedis  *miniredis.Miniredis
+	testClient *cache.Client
+)
+
+// TestMain starts an in-memory Redis and registers the process-wide pod manager,
+// whose heartbeat is stopped right away so tests control the pod registry
+func TestMain(m *testing.M) {
+	testRedis = miniredis.NewMiniRedis()
+	if err := testRedis.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start miniredis:", err)
+		os.Exit(1)
+	}
+	testClient = cache.NewClientFromRedis(redis.NewClient(&redis.Options{Addr: testRedis.Addr()}))
+
+	config := testConfig()
+	config.PodID = testPodID
+	podManager := leader.NewPodManager(testClient, testLogger(), config)
+	podManager.SetOutput(io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := podManager.Initialize(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize pod manager:", err)
+		os.Exit(1)
+	}
+	cancel()
+
+	code := m.Run()
+	testRedis.Close()
+	os.Exit(code)
+}
+
+// testConfig returns the config with every variable at its default
+func testConfig() *utils.Config {
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// testLogger returns a logger discarding everything
+func testLogger() *utils.StandardLogger {
+	return &utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}
+}
+
+// observeLogs makes s log to an observer recording every level, and returns the recorded entries
+func observeLogs(s *Scheduler) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	s.logger = &utils.StandardLogger{SugaredLogger: zap.New(core).Sugar()}
+	return logs
+}
+
+// newTestScheduler empties Redis and returns a scheduler on it, with the test pod registered as the only pod and leader
+func newTestScheduler(t *testing.T, config *utils.Config) *Scheduler {
+	t.Helper()
+	testRedis.FlushAll()
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true})
+	return NewScheduler(testClient, testLogger(), config)
+}
+
+// setTestPods replaces the pod registry, pods are stamped as just seen
+func setTestPods(t *testing.T, pods ...leader.PodInfo) {
+	t.Helper()
+	registry := make(map[string]leader.PodInfo, len(pods))
+	for _, pod := range pods {
+		if pod.StartTime.IsZero() {
+			pod.StartTime = time.Now()
+		}
+		if pod.LastSeen.IsZero() {
+			pod.LastSeen = time.Now()
+		}
+		registry[pod.ID] = pod
+	}
+	if err := testClient.SetJSON(context.Background(), "schedulerx:pods", registry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testNow returns the current time at the millisecond precision jobs are stored with
+func testNow() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+// funcCommand returns an unscheduled command running fn
+func funcCommand(id string, fn command.Func) *command.FuncCommand {
+	return command.NewFuncCommand(id, "test command", "", fn)
+}
+
+// storeJob stores job in the job set as it is
+func storeJob(t *testing.T, job *command.Job) {
+	t.Helper()
+	if err := job.StoreInRedis(context.Background(), testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// heldJob stores a job of the "work" command assigned to the test pod with the given status
+func heldJob(t *testing.T, scheduledAt time.Time, status command.JobStatus) *command.Job {
+	t.Helper()
+	job := command.NewJob("work", nil, scheduledAt)
+	job.AssignedTo, job.Status = testPodID, status
+	storeJob(t, job)
+	return job
+}
+
+// raiseFencingToken makes another pod the newest leader, so this pod's fenced writes are rejected
+func raiseFencingToken(t *testing.T) {
+	t.Helper()
+	if err := testClient.GetClient().Set(context.Background(), leader.FencingTokenKey, leader.FencingToken()+1, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countRuns registers the "work" command and returns a pointer to the number of times it ran
+func countRuns(s *Scheduler) *int {
+	runs := 0
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		runs++
+		return "done", nil
+	}))
+	return &runs
+}
+
+// loadJob returns the stored details of a job, failing the test when they are missing
+func loadJob(t *testing.T, jobID string) *command.Job {
+	t.Helper()
+	data, err := testClient.GetClient().Get(context.Background(), fmt.Sprintf(command.JobDetailsKey, jobID)).Bytes()
+	if err != nil {
+		t.Fatalf("failed to load job %s: %v", jobID, err)
+	}
+	var job command.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatal(err)
+	}
+	return &job
+}
+
+// jobSet returns the members of the job sorted set
+func jobSet(t *testing.T) []string {
+	t.Helper()
+	ids, err := testClient.GetClient().ZRange(context.Background(), command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
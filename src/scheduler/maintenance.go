@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// historyKey is a per-command job history list, newest entries first, trimmed by maintenance
+const historyKey = "schedulerx:history:%s"
+
+// MaintenanceResult summarizes the keys cleaned by a maintenance run
+type MaintenanceResult struct {
+	Reconcile        *ReconcileResult // Stale sorted set members removed by ReconcileJobs
+	OrphanedLocks    int              // Job locks whose job is no longer pending
+	StaleCommandKeys int              // Per-command state and history of commands that are no longer registered, and output hashes no longer compared
+	TrimmedHistory   int              // History entries dropped to keep lists within JobHistoryCap
+}
+
+// RunMaintenance removes stale and orphaned scheduler keys. It reconciles the
+// job sorted set first, then sweeps job locks of jobs that are no longer
+// pending and per-command state left behind by unregistered commands, and
+// finally trims history lists to JobHistoryCap.
+func (s *Scheduler) RunMaintenance(ctx context.Context) (*MaintenanceResult, error) {
+	reconciled, err := s.ReconcileJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &MaintenanceResult{Reconcile: reconciled}
+
+	client := s.redisClient.GetClient()
+
+	// A lock is orphaned once its job has left the sorted set
+	result.OrphanedLocks, err = s.sweepKeys(ctx, jobLockKey, func(jobID string) (bool, error) {
+		_, err := client.ZScore(ctx, command.JobsSortedSetKey, jobID).Result()
+		if err == redis.Nil {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Output hashes are stale once the command no longer notifies on change
+	result.StaleCommandKeys, err = s.sweepKeys(ctx, outputHashKey, func(commandID string) (bool, error) {
+		_, exists := s.GetCommand(commandID)
+		return !exists || !slices.Contains(s.config.NotifyOnChangeCommands, commandID), nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Per-command state is stale once the command is gone
+	for _, pattern := range []string{lastFailedPodKey, historyKey} {
+		removed, err := s.sweepKeys(ctx, pattern, func(commandID string) (bool, error) {
+			_, exists := s.GetCommand(commandID)
+			return !exists, nil
+		})
+		result.StaleCommandKeys += removed
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.TrimmedHistory, err = s.trimHistory(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	s.logger.Info("Maintenance completed",
+		"orphaned_locks", result.OrphanedLocks,
+		"stale_command_keys", result.StaleCommandKeys,
+		"trimmed_history", result.TrimmedHistory,
+	)
+	return result, nil
+}
+
+// trimHistory trims every history list to JobHistoryCap entries, returning how many were dropped
+func (s *Scheduler) trimHistory(ctx context.Context) (int, error) {
+	limit := int64(s.config.JobHistoryCap)
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	client := s.redisClient.GetClient()
+	pattern := strings.TrimSuffix(historyKey, "%s") + "*"
+
+	trimmed := 0
+	iter := client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		length, err := client.LLen(ctx, key).Result()
+		if err != nil {
+			return trimmed, fmt.Errorf("failed to get length of %s: %w", key, err)
+		}
+		if length <= limit {
+			continue
+		}
+		if err := client.LTrim(ctx, key, 0, limit-1).Err(); err != nil {
+			return trimmed, fmt.Errorf("failed to trim %s: %w", key, err)
+		}
+		trimmed += int(length - limit)
+	}
+	if err := iter.Err(); err != nil {
+		return trimmed, fmt.Errorf("failed to scan keys %s: %w", pattern, err)
+	}
+	return trimmed, nil
+}
+
+// sweepKeys scans keys built from the format string keyFormat, which must end in %s,
+// and deletes those whose suffix is reported stale
+func (s *Scheduler) sweepKeys(ctx context.Context, keyFormat string, stale func(suffix string) (bool, error)) (int, error) {
+	client := s.redisClient.GetClient()
+	prefix := strings.TrimSuffix(keyFormat, "%s")
+
+	removed := 0
+	iter := client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		isStale, err := stale(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return removed, fmt.Errorf("failed to check key %s: %w", key, err)
+		}
+		if !isStale {
+			continue
+		}
+		if err := client.Del(ctx, key).Err(); err != nil {
+			return removed, fmt.Errorf("failed to delete key %s: %w", key, err)
+		}
+		removed++
+	}
+	if err := iter.Err(); err != nil {
+		return removed, fmt.Errorf("failed to scan keys %s*: %w", prefix, err)
+	}
+	return removed, nil
+}
+
+// MaintenanceCommand runs RunMaintenance on a schedule. Its jobs are assigned to the
+// leader; one that reaches a pod after it lost leadership succeeds without doing anything.
+type MaintenanceCommand struct {
+	scheduler *Scheduler
+	schedule  string
+}
+
+// NewMaintenanceCommand creates a new MaintenanceCommand sweeping through the given scheduler
+func NewMaintenanceCommand(scheduler *Scheduler, schedule string) *MaintenanceCommand {
+	return &MaintenanceCommand{
+		scheduler: scheduler,
+		schedule:  schedule,
+	}
+}
+
+// ID returns the command identifier
+func (c *MaintenanceCommand) ID() string {
+	return "maintenance"
+}
+
+// Description returns the command description
+func (c *MaintenanceCommand) Description() string {
+	return "Purge stale and orphaned scheduler keys from Redis"
+}
+
+// LeaderOnly keeps maintenance jobs on the leader
+func (c *MaintenanceCommand) LeaderOnly() bool {
+	return true
+}
+
+// Execute runs maintenance and prints the counts cleaned
+func (c *MaintenanceCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput runs maintenance and returns the counts cleaned
+func (c *MaintenanceCommand) ExecuteWithOutput(params []string) (string, error) {
+	if !leader.IsLeader() {
+		return "skipped: maintenance only runs on the leader\n", nil
+	}
+
+	result, err := c.scheduler.RunMaintenance(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("maintenance failed: %w", err)
+	}
+	return fmt.Sprintf("missing_details=%d terminal=%d orphaned_locks=%d stale_command_keys=%d trimmed_history=%d\n",
+		result.Reconcile.MissingDetails, result.Reconcile.Terminal, result.OrphanedLocks, result.StaleCommandKeys, result.TrimmedHistory), nil
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *MaintenanceCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *MaintenanceCommand) Parameters() []string {
+	return []string{}
+}
@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestMaintenanceJobsAreAssignedToLeader(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "other-pod"})
+	s.RegisterCommand(NewMaintenanceCommand(s, ""))
+
+	now := testNow()
+	for i := range 4 {
+		storeJob(t, command.NewJob("maintenance", nil, now.Add(-time.Duration(i)*time.Second)))
+	}
+
+	if err := s.AssignJobs(ctx, []string{testPodID, "other-pod"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, jobID := range jobSet(t) {
+		if job := loadJob(t, jobID); job.AssignedTo != testPodID {
+			t.Fatalf("maintenance job %s assigned to %q, want the leader %s", jobID, job.AssignedTo, testPodID)
+		}
+	}
+}
+
+func TestRunMaintenanceSweepsStaleKeys(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.NotifyOnChangeCommands = []string{"work"}
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", nil))
+	s.RegisterCommand(funcCommand("quiet", nil))
+
+	pending := command.NewJob("work", nil, testNow())
+	storeJob(t, pending)
+	testRedis.Set("schedulerx:job_lock:"+pending.ID, testPodID)
+	testRedis.Set("schedulerx:job_lock:work_1", testPodID)
+	testRedis.HSet("schedulerx:output_hash:removed", "hash", "abc")
+	testRedis.HSet("schedulerx:output_hash:work", "hash", "abc")
+	testRedis.HSet("schedulerx:output_hash:quiet", "hash", "abc")
+
+	result, err := s.RunMaintenance(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.OrphanedLocks != 1 || result.StaleCommandKeys != 2 {
+		t.Fatalf("RunMaintenance = %+v, want 1 orphaned lock and 2 stale command keys", result)
+	}
+	if !testRedis.Exists("schedulerx:job_lock:"+pending.ID) || !testRedis.Exists("schedulerx:output_hash:work") {
+		t.Fatal("maintenance removed keys still in use")
+	}
+}
+
+func TestRunMaintenanceTrimsHistory(t *testing.T) {
+	config := testConfig()
+	config.JobHistoryCap = 3
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", nil))
+	s.RegisterCommand(funcCommand("quiet", nil))
+
+	testRedis.Push("schedulerx:history:work", "e5", "e4", "e3", "e2", "e1")
+	testRedis.Push("schedulerx:history:quiet", "e2", "e1")
+	testRedis.Push("schedulerx:history:removed", "e4", "e3", "e2", "e1")
+
+	output, err := NewMaintenanceCommand(s, "").ExecuteWithOutput(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "stale_command_keys=1 trimmed_history=2") {
+		t.Fatalf("maintenance reported %q, want 1 stale command key and 2 trimmed entries", output)
+	}
+	if history, _ := testRedis.List("schedulerx:history:work"); !slices.Equal(history, []string{"e5", "e4", "e3"}) {
+		t.Fatalf("work history trimmed to %q, want the 3 newest entries", history)
+	}
+	if history, _ := testRedis.List("schedulerx:history:quiet"); len(history) != 2 {
+		t.Fatalf("quiet history within its cap has %d entries, want 2", len(history))
+	}
+	if testRedis.Exists("schedulerx:history:removed") {
+		t.Fatal("history of an unregistered command was kept")
+	}
+}
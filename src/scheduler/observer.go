@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// Observer receives scheduler lifecycle events in-process. Methods are called
+// synchronously from the scheduler's goroutines, so they must return quickly
+// and must not modify the job.
+type Observer interface {
+	// JobScheduled is called after a job has been enqueued
+	JobScheduled(job *command.Job)
+	// JobStarted is called when this pod starts executing a job
+	JobStarted(job *command.Job)
+	// JobFinished is called when a job executed by this pod succeeded, failed or was cancelled
+	JobFinished(job *command.Job)
+	// LeaderChanged is called when this pod gains or loses leadership
+	LeaderChanged(isLeader bool)
+}
+
+// NopObserver ignores all events, embed it to implement only some Observer methods
+type NopObserver struct{}
+
+func (NopObserver) JobScheduled(job *command.Job) {}
+func (NopObserver) JobStarted(job *command.Job)   {}
+func (NopObserver) JobFinished(job *command.Job)  {}
+func (NopObserver) LeaderChanged(isLeader bool)   {}
+
+// RegisterObserver adds an observer notified of lifecycle events, must be called before Start
+func (s *Scheduler) RegisterObserver(observer Observer) {
+	s.observers = append(s.observers, observer)
+}
+
+// NotifyLeaderChanged forwards a leadership change to all observers. On
+// promotion it also requests an immediate scheduling pass, so the new leader
+// does not wait for the next tick.
+func (s *Scheduler) NotifyLeaderChanged(isLeader bool) {
+	if isLeader {
+		requestPass(s.scheduleNow)
+	}
+	for _, observer := range s.observers {
+		observer.LeaderChanged(isLeader)
+	}
+}
+
+// jobScheduled notifies observers of an enqueued job
+func (s *Scheduler) jobScheduled(job *command.Job) {
+	for _, observer := range s.observers {
+		observer.JobScheduled(job)
+	}
+}
+
+// jobStarted notifies observers of a job this pod started
+func (s *Scheduler) jobStarted(job *command.Job) {
+	for _, observer := range s.observers {
+		observer.JobStarted(job)
+	}
+}
+
+// jobFinished retains and exports a finished job and notifies observers
+func (s *Scheduler) jobFinished(ctx context.Context, job *command.Job) {
+	if err := s.retainCompleted(ctx, job); err != nil {
+		job.Logger(s.logger).Error("Failed to retain completed job", "error", err)
+	}
+	if s.exporter != nil {
+		s.exporter.Export(job)
+	}
+	for _, observer := range s.observers {
+		observer.JobFinished(job)
+	}
+}
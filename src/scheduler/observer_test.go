@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// scheduledRecorder records the IDs of the jobs it is notified of
+type scheduledRecorder struct {
+	NopObserver
+	scheduled []string
+}
+
+func (r *scheduledRecorder) JobScheduled(job *command.Job) {
+	r.scheduled = append(r.scheduled, job.ID)
+}
+
+func TestJobScheduledFiresOncePerCreatedJob(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	recorder := &scheduledRecorder{}
+	s.RegisterObserver(recorder)
+	s.RegisterCommand(everyMinute("tick"))
+
+	for range 2 {
+		if err := s.ScheduleJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := len(recorder.scheduled), len(jobSet(t)); got != want {
+		t.Fatalf("2 passes notified %d scheduled jobs for %d occurrences", got, want)
+	}
+}
+
+func TestEnqueueMovesJobOffTakenID(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	recorder := &scheduledRecorder{}
+	s.RegisterObserver(recorder)
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		return "", nil
+	}))
+
+	at := testNow().Add(time.Minute)
+	first, err := s.newJob("work", nil, nil, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.enqueueJob(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.newJob("work", []string{"other"}, nil, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.enqueueJob(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+
+	if second.ID == first.ID || !second.ScheduledAt.Equal(at.Add(time.Millisecond)) {
+		t.Fatalf("second job %s due at %s, want it moved to the next millisecond", second.ID, second.ScheduledAt)
+	}
+	if stored := loadJob(t, first.ID); len(stored.Params) != 0 {
+		t.Fatalf("first job was overwritten with params %v", stored.Params)
+	}
+	if stored := loadJob(t, second.ID); !slices.Equal(stored.Params, []string{"other"}) {
+		t.Fatalf("second job stored with params %v", stored.Params)
+	}
+	if len(recorder.scheduled) != 2 {
+		t.Fatalf("observer was notified %d times, want 2", len(recorder.scheduled))
+	}
+}
+
+// lifecycleRecorder records every event it is notified of, in order
+type lifecycleRecorder struct {
+	events []string
+}
+
+func (r *lifecycleRecorder) JobScheduled(job *command.Job) {
+	r.events = append(r.events, "scheduled "+job.ID)
+}
+
+func (r *lifecycleRecorder) JobStarted(job *command.Job) {
+	r.events = append(r.events, "started "+job.ID)
+}
+
+func (r *lifecycleRecorder) JobFinished(job *command.Job) {
+	r.events = append(r.events, "finished "+job.ID+" "+string(job.Status))
+}
+
+func (r *lifecycleRecorder) LeaderChanged(isLeader bool) {
+	if isLeader {
+		r.events = append(r.events, "leader")
+	} else {
+		r.events = append(r.events, "follower")
+	}
+}
+
+func TestObserverSeesJobLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	recorder := &lifecycleRecorder{}
+	s.RegisterObserver(recorder)
+	countRuns(s)
+
+	s.NotifyLeaderChanged(true)
+	job, err := s.EnqueueAfter(ctx, "work", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s.NotifyLeaderChanged(false)
+
+	want := []string{
+		"leader",
+		"scheduled " + job.ID,
+		"started " + job.ID,
+		"finished " + job.ID + " " + string(command.Success),
+		"follower",
+	}
+	if !slices.Equal(recorder.events, want) {
+		t.Fatalf("events = %q, want %q", recorder.events, want)
+	}
+}
@@ -1,9 +1,55 @@
 package scheduler
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/robfig/cron/v3"
 )
 
+const (
+	// afterDescriptor marks schedules that run a fixed delay after the previous run finished
+	afterDescriptor = "@after"
+	// everyDescriptor marks schedules that run at a fixed interval
+	everyDescriptor = "@every"
+	// atDescriptor marks schedules that run once at a given time
+	atDescriptor = "@at"
+
+	// scheduleSeparator separates the cron expressions of a command running on several schedules
+	scheduleSeparator = "|"
+)
+
+// ScheduleType identifies how a command's schedule fires
+type ScheduleType string
+
+const (
+	NoSchedule    ScheduleType = "none"  // Empty schedule, the command only runs when triggered
+	CronSchedule  ScheduleType = "cron"  // One or more cron expressions separated by "|"
+	EverySchedule ScheduleType = "every" // "@every <duration>", e.g. "@every 90s"
+	AtSchedule    ScheduleType = "at"    // "@at <RFC 3339 time>", a single run
+	AfterSchedule ScheduleType = "after" // "@after <duration>", a delay after the previous run finished
+)
+
+// Schedule is a parsed command schedule. It implements cron.Schedule, types
+// driven by job completion rather than the clock (none, after) never fire.
+type Schedule struct {
+	Type  ScheduleType
+	Spec  string        // Schedule as returned by the command
+	Delay time.Duration // Interval of every schedules and delay of after schedules
+	At    time.Time     // Time of at schedules
+
+	fires cron.Schedule // Activation times, nil for none and after schedules
+}
+
+// Next returns the next activation after t, zero if the schedule does not fire again
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.fires == nil {
+		return time.Time{}
+	}
+	return s.fires.Next(t)
+}
+
 // Parser handles cron expression parsing
 type Parser struct {
 	parser cron.Parser
@@ -16,7 +62,132 @@ func NewParser() *Parser {
 	}
 }
 
-// Parse parses a cron expression
+// ParseSchedule parses any supported schedule into its typed form, see ScheduleType
+func (p *Parser) ParseSchedule(spec string) (*Schedule, error) {
+	trimmed := strings.TrimSpace(spec)
+	if trimmed == "" {
+		return &Schedule{Type: NoSchedule, Spec: spec}, nil
+	}
+
+	fields := strings.Fields(trimmed)
+	switch fields[0] {
+	case afterDescriptor, everyDescriptor:
+		delay, err := parseDelay(spec, fields)
+		if err != nil {
+			return nil, err
+		}
+		if fields[0] == afterDescriptor {
+			return &Schedule{Type: AfterSchedule, Spec: spec, Delay: delay}, nil
+		}
+		return &Schedule{Type: EverySchedule, Spec: spec, Delay: delay, fires: everySchedule(delay)}, nil
+
+	case atDescriptor:
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid schedule %q: expected \"@at <RFC 3339 time>\"", spec)
+		}
+		at, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		return &Schedule{Type: AtSchedule, Spec: spec, At: at, fires: atSchedule(at)}, nil
+	}
+
+	fires, err := p.parseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Schedule{Type: CronSchedule, Spec: spec, fires: fires}, nil
+}
+
+// Parse parses a schedule that fires on the clock (cron, every or at) into its activation times.
+// Standard 5-field crontab expressions are minute based and run at second 0,
+// 6-field expressions include seconds and are used as-is.
+// Several expressions separated by "|", e.g. "0 9 * * 1-5 | 0 12 * * 0,6",
+// fire whenever any of them does, identical times fire once.
 func (p *Parser) Parse(spec string) (cron.Schedule, error) {
+	schedule, err := p.ParseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.fires == nil {
+		return nil, fmt.Errorf("schedule %q of type %s does not fire on the clock", spec, schedule.Type)
+	}
+	return schedule, nil
+}
+
+// parseCron parses one or more cron expressions separated by "|"
+func (p *Parser) parseCron(spec string) (cron.Schedule, error) {
+	exprs := strings.Split(spec, scheduleSeparator)
+	if len(exprs) == 1 {
+		return p.parseOne(spec)
+	}
+
+	union := make(unionSchedule, 0, len(exprs))
+	for _, expr := range exprs {
+		schedule, err := p.parseOne(strings.TrimSpace(expr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q in schedule %q: %w", expr, spec, err)
+		}
+		union = append(union, schedule)
+	}
+	return union, nil
+}
+
+// parseOne parses a single 5 or 6 field cron expression
+func (p *Parser) parseOne(spec string) (cron.Schedule, error) {
+	if len(strings.Fields(spec)) == 5 {
+		spec = "0 " + spec
+	}
 	return p.parser.Parse(spec)
 }
+
+// parseDelay parses the positive duration of a "<descriptor> <duration>" schedule
+func parseDelay(spec string, fields []string) (time.Duration, error) {
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("invalid schedule %q: expected \"%s <duration>\"", spec, fields[0])
+	}
+
+	delay, err := time.ParseDuration(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	if delay <= 0 {
+		return 0, fmt.Errorf("invalid schedule %q: delay must be positive", spec)
+	}
+	return delay, nil
+}
+
+// unionSchedule fires whenever any of its schedules fires
+type unionSchedule []cron.Schedule
+
+// Next returns the earliest activation of any schedule after t, zero if none fires again
+func (u unionSchedule) Next(t time.Time) time.Time {
+	var earliest time.Time
+	for _, schedule := range u {
+		next := schedule.Next(t)
+		if !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+			earliest = next
+		}
+	}
+	return earliest
+}
+
+// everySchedule fires at multiples of its interval since the zero time, as with
+// time.Truncate. Aligned activation times keep job IDs stable across ticks and pods.
+type everySchedule time.Duration
+
+// Next returns the first multiple of the interval after t
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// atSchedule fires once at the given time
+type atSchedule time.Time
+
+// Next returns the schedule's time if it is after t, zero otherwise
+func (a atSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(a); at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
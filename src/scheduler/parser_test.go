@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFiveAndSixFieldExpressions(t *testing.T) {
+	from := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)},
+		{"0 */15 * * * *", time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)},
+		{"30 */15 * * * *", time.Date(2025, 3, 14, 9, 30, 30, 0, time.UTC)},
+		{"0 10 * * *", time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)},
+		{"  0 10 * * *  ", time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)},
+		{"* * * * * *", time.Date(2025, 3, 14, 9, 26, 54, 0, time.UTC)},
+		{"0 10 * * * | 58 26 9 * * *", time.Date(2025, 3, 14, 9, 26, 58, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := NewParser().Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if next := schedule.Next(from); !next.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next = %v, want %v", tt.spec, next, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsOtherFieldCounts(t *testing.T) {
+	for _, spec := range []string{"* * * *", "0 0 0 * * * *", "0 10 * * * | * * * *"} {
+		if _, err := NewParser().Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestParseAfterSchedule(t *testing.T) {
+	schedule, err := NewParser().ParseSchedule("@after 30m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Type != AfterSchedule || schedule.Delay != 30*time.Minute || !schedule.Next(time.Now()).IsZero() {
+		t.Fatalf("ParseSchedule(@after 30m) = %+v, want an after schedule of 30m that never fires on the clock", schedule)
+	}
+
+	for _, spec := range []string{"@after", "@after soon", "@after -5m", "@after 0s", "@after 5m 10m"} {
+		if _, err := NewParser().ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+	if _, err := NewParser().Parse("@after 30m"); err == nil {
+		t.Error("Parse accepted an @after schedule, which does not fire on the clock")
+	}
+}
+
+func TestParseMultipleSchedulesUnionOccurrences(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		spec       string
+		start, end time.Time
+		want       []time.Time
+	}{
+		{
+			name:  "overlapping",
+			spec:  "*/15 * * * * | */10 * * * *",
+			start: at(14, 10, 0),
+			end:   at(14, 11, 0),
+			want: []time.Time{
+				at(14, 10, 0), at(14, 10, 10), at(14, 10, 15), at(14, 10, 20),
+				at(14, 10, 30), at(14, 10, 40), at(14, 10, 45), at(14, 10, 50),
+			},
+		},
+		{
+			// March 10th 2025 is a Monday
+			name:  "disjoint",
+			spec:  "0 9 * * 1-5 | 0 12 * * 0,6",
+			start: at(10, 0, 0),
+			end:   at(17, 0, 0),
+			want: []time.Time{
+				at(10, 9, 0), at(11, 9, 0), at(12, 9, 0), at(13, 9, 0),
+				at(14, 9, 0), at(15, 12, 0), at(16, 12, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := NewParser().Parse(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := occurrencesInWindow(schedule, tt.start, tt.end); !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Fatalf("occurrences of %q = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleTypes(t *testing.T) {
+	tests := []struct {
+		spec string
+		want ScheduleType
+	}{
+		{"", NoSchedule},
+		{"   ", NoSchedule},
+		{"*/5 * * * *", CronSchedule},
+		{"0 9 * * 1-5 | 0 12 * * 0,6", CronSchedule},
+		{"@every 90s", EverySchedule},
+		{"@at 2030-01-02T15:04:05Z", AtSchedule},
+		{"@after 10m", AfterSchedule},
+	}
+	for _, tt := range tests {
+		schedule, err := NewParser().ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if schedule.Type != tt.want || schedule.Spec != tt.spec {
+			t.Errorf("ParseSchedule(%q) = %s schedule of %q, want %s", tt.spec, schedule.Type, schedule.Spec, tt.want)
+		}
+	}
+
+	every, _ := NewParser().ParseSchedule("@every 90s")
+	if from := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC); !every.Next(from).Equal(from.Add(90*time.Second)) || every.Delay != 90*time.Second {
+		t.Fatalf("@every 90s fires at %v with delay %s, want every 90s aligned to the hour", every.Next(from), every.Delay)
+	}
+	at, _ := NewParser().ParseSchedule("@at 2030-01-02T15:04:05Z")
+	if want := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC); !at.At.Equal(want) || !at.Next(want.Add(-time.Second)).Equal(want) || !at.Next(want).IsZero() {
+		t.Fatalf("@at schedule = %+v, want it to fire once at %v", at, want)
+	}
+	if none, _ := NewParser().ParseSchedule(""); !none.Next(time.Now()).IsZero() {
+		t.Fatal("an empty schedule fires on the clock")
+	}
+
+	for _, spec := range []string{"@every", "@every never", "@at tomorrow", "@at 2030-01-02"} {
+		if _, err := NewParser().ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
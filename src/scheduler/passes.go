@@ -0,0 +1,21 @@
+package scheduler
+
+// requestPass asks the loop reading ch for an immediate pass, without blocking
+// when one is already pending
+func requestPass(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// ScheduleNow delivers requests for an immediate ScheduleJobs pass. The
+// scheduling loop should run one, then call RequestAssignment.
+func (s *Scheduler) ScheduleNow() <-chan struct{} {
+	return s.scheduleNow
+}
+
+// RequestAssignment asks the assignment loop started by Start for an immediate pass
+func (s *Scheduler) RequestAssignment() {
+	requestPass(s.assignNow)
+}
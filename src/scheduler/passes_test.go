@@ -0,0 +1,25 @@
+package scheduler
+
+import "testing"
+
+func TestPromotionRequestsOneSchedulingPass(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+
+	s.NotifyLeaderChanged(true)
+	s.NotifyLeaderChanged(true)
+	if pending := len(s.ScheduleNow()); pending != 1 {
+		t.Fatalf("%d scheduling passes pending after promotion, want requests coalesced into 1", pending)
+	}
+	<-s.ScheduleNow()
+
+	s.NotifyLeaderChanged(false)
+	if pending := len(s.ScheduleNow()); pending != 0 {
+		t.Fatalf("%d scheduling passes pending after losing leadership, want none", pending)
+	}
+
+	s.RequestAssignment()
+	s.RequestAssignment()
+	if pending := len(s.assignNow); pending != 1 {
+		t.Fatalf("%d assignment passes pending, want requests coalesced into 1", pending)
+	}
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+// ErrQueueFull is returned when enqueuing a job would grow the job set beyond MAX_QUEUED_JOBS
+var ErrQueueFull = errors.New("job queue is full")
+
+// admitJob checks that storing the job stays within MAX_QUEUED_JOBS. Jobs already
+// in the job set are always admitted, re-storing them does not grow the set.
+// Rejections are counted per command in schedulerx_jobs_rejected_total.
+func (s *Scheduler) admitJob(ctx context.Context, job *command.Job) error {
+	limit := int64(s.config.MaxQueuedJobs)
+	if limit <= 0 {
+		return nil
+	}
+
+	client := s.redisClient.GetClient()
+	err := client.ZScore(ctx, command.JobsSortedSetKey, job.ID).Err()
+	if err == nil {
+		return nil
+	}
+	if err != redis.Nil {
+		return fmt.Errorf("failed to check job %s: %w", job.ID, err)
+	}
+
+	queued, err := client.ZCard(ctx, command.JobsSortedSetKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count queued jobs: %w", err)
+	}
+	if queued >= limit {
+		metrics.JobsRejected.Inc(job.CommandID)
+		return fmt.Errorf("%w: %d of %d jobs queued, rejecting %s", ErrQueueFull, queued, limit, job.ID)
+	}
+	return nil
+}
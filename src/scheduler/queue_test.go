@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+)
+
+func TestEnqueueRejectedAtMaxQueuedJobs(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.MaxQueuedJobs = 2
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", nil))
+	before := metrics.JobsRejected.Value("work")
+
+	for i := range 2 {
+		if _, err := s.EnqueueAfter(ctx, "work", nil, time.Duration(i+1)*time.Minute); err != nil {
+			t.Fatalf("enqueueing job %d under the cap failed: %v", i+1, err)
+		}
+	}
+	if _, err := s.EnqueueAfter(ctx, "work", nil, 3*time.Minute); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("enqueueing past the cap returned %v, want %v", err, ErrQueueFull)
+	}
+	if got := len(jobSet(t)); got != 2 {
+		t.Fatalf("job set holds %d jobs, want the cap of 2", got)
+	}
+	if got := metrics.JobsRejected.Value("work") - before; got != 1 {
+		t.Fatalf("schedulerx_jobs_rejected_total{command=\"work\"} grew by %g, want 1", got)
+	}
+}
+
+func TestScheduleJobsStopsAtMaxQueuedJobs(t *testing.T) {
+	config := testConfig()
+	config.MaxQueuedJobs = 3
+	s := newTestScheduler(t, config)
+	logs := observeLogs(s)
+	s.RegisterCommand(everyMinute("tick"))
+
+	// The default 5m lookahead yields more occurrences than the cap
+	if err := s.ScheduleJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(jobSet(t)); got != 3 {
+		t.Fatalf("job set holds %d jobs, want the cap of 3", got)
+	}
+	if logs.FilterMessageSnippet("Job queue full").Len() != 1 {
+		t.Fatal("rejected occurrences were not logged once for the pass")
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// maxRebalanceMoves limits how many jobs a single rebalancing pass unassigns to avoid churn
+const maxRebalanceMoves = 10
+
+// RebalanceJobs unassigns jobs from pods holding more jobs than their capacity,
+// returning them to the pool for reassignment. Running jobs are never moved and
+// the latest scheduled jobs are offloaded first. Returns the number of jobs moved.
+func (s *Scheduler) RebalanceJobs(ctx context.Context, pods map[string]leader.PodInfo) (int, error) {
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for podID, info := range pods {
+		if info.Capacity <= 0 {
+			continue
+		}
+
+		jobs := assigned[podID]
+		excess := len(jobs) - info.Capacity
+		for i := len(jobs) - 1; i >= 0 && excess > 0 && moved < maxRebalanceMoves; i-- {
+			job := jobs[i]
+			if job.Status == command.Running {
+				continue
+			}
+
+			job.AssignedTo = ""
+			job.Status = command.Scheduled
+			if err := s.storeJobFenced(ctx, job); err != nil {
+				if errors.Is(err, command.ErrStaleFencingToken) {
+					return moved, err
+				}
+				continue
+			}
+
+			job.Logger(s.logger).Info("Unassigned job from pod over capacity", "previous_pod_id", podID, "capacity", info.Capacity)
+			excess--
+			moved++
+		}
+	}
+
+	return moved, nil
+}
+
+// assignedJobsByPod returns the non-terminal jobs currently assigned to each pod, earliest scheduled first
+func (s *Scheduler) assignedJobsByPod(ctx context.Context) (map[string][]*command.Job, error) {
+	jobIDs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	assigned := make(map[string][]*command.Job)
+	for _, jobID := range jobIDs {
+		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
+		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
+			continue
+		}
+
+		if job.AssignedTo == "" || job.Status.IsTerminal() {
+			continue
+		}
+		assigned[job.AssignedTo] = append(assigned[job.AssignedTo], &job)
+	}
+
+	return assigned, nil
+}
+
+// remainingCapacity returns how many more jobs each pod with a capacity may be assigned,
+// pods without a capacity are missing from the result
+func (s *Scheduler) remainingCapacity(ctx context.Context) (map[string]int, error) {
+	var pods map[string]leader.PodInfo
+	if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &pods); err != nil {
+		return nil, err
+	}
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[string]int)
+	for podID, info := range pods {
+		if info.Capacity > 0 {
+			remaining[podID] = max(info.Capacity-len(assigned[podID]), 0)
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestAssignJobsRespectsRemainingCapacity(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, IsLeader: true, Capacity: 2},
+		leader.PodInfo{ID: "other-pod", Capacity: 1},
+	)
+	s.RegisterCommand(funcCommand("work", nil))
+
+	now := testNow()
+	held := command.NewJob("work", nil, now.Add(-time.Minute))
+	held.AssignedTo, held.Status = testPodID, command.Assigned
+	storeJob(t, held)
+	for i := range 6 {
+		storeJob(t, command.NewJob("work", nil, now.Add(-time.Duration(i)*time.Second)))
+	}
+
+	if err := s.AssignJobs(ctx, []string{testPodID, "other-pod"}); err != nil {
+		t.Fatal(err)
+	}
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(assigned[testPodID]); got > 2 {
+		t.Fatalf("%s holds %d jobs, capacity is 2", testPodID, got)
+	}
+	if got := len(assigned["other-pod"]); got > 1 {
+		t.Fatalf("other-pod holds %d jobs, capacity is 1", got)
+	}
+	if got := len(assigned[testPodID]) + len(assigned["other-pod"]); got != 3 {
+		t.Fatalf("%d jobs assigned in total, want the 3 the pods have room for", got)
+	}
+}
+
+func TestRebalanceJobsOffloadsExcess(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	pods := map[string]leader.PodInfo{testPodID: {ID: testPodID, Capacity: 1}}
+
+	now := testNow()
+	for i := range 3 {
+		job := command.NewJob("work", nil, now.Add(time.Duration(i)*time.Second))
+		job.AssignedTo, job.Status = testPodID, command.Assigned
+		storeJob(t, job)
+	}
+
+	moved, err := s.RebalanceJobs(ctx, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 2 {
+		t.Fatalf("RebalanceJobs moved %d jobs, want 2", moved)
+	}
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned[testPodID]) != 1 || !assigned[testPodID][0].ScheduledAt.Equal(now) {
+		t.Fatal("the earliest job should stay on the pod, the latest are offloaded first")
+	}
+}
+
+func TestReducingCapacityOffloadsJobsToOtherPods(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+	now := testNow()
+	for i := range 3 {
+		heldJob(t, now.Add(time.Duration(i)*time.Second), command.Assigned)
+	}
+
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true, Capacity: 3}, leader.PodInfo{ID: "other-pod"})
+	s.assignmentPass(ctx)
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned[testPodID]) != 3 {
+		t.Fatalf("%s holds %d jobs within its capacity of 3, want all 3 kept", testPodID, len(assigned[testPodID]))
+	}
+
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true, Capacity: 1}, leader.PodInfo{ID: "other-pod"})
+	s.assignmentPass(ctx)
+	assigned, err = s.assignedJobsByPod(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned[testPodID]) != 1 || len(assigned["other-pod"]) != 2 {
+		t.Fatalf("after reducing capacity to 1, %s holds %d and other-pod %d jobs, want 1 and 2",
+			testPodID, len(assigned[testPodID]), len(assigned["other-pod"]))
+	}
+}
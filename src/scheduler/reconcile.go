@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// legacyScoreLimit separates job scores in Unix seconds, written before scores moved to
+// milliseconds, from current ones. Second scores stay below it until the year 5138,
+// millisecond scores have been above it since 1973.
+const legacyScoreLimit = 100_000_000_000
+
+// ReconcileResult summarizes the fixes applied by ReconcileJobs
+type ReconcileResult struct {
+	Scanned        int // Members found in the sorted set
+	MissingDetails int // Members dropped because their detail key was gone
+	Terminal       int // Completed jobs that were still lingering in the set
+	Corrupt        int // Jobs discarded because their details could not be decoded
+	Rescored       int // Members whose score did not match their scheduled time, e.g. legacy second scores
+}
+
+// ReconcileJobs scans the job sorted set once and removes stale members.
+// Members whose detail key is missing and jobs that already reached a terminal
+// status are dropped from the set. It is meant to be run by the leader on startup.
+func (s *Scheduler) ReconcileJobs(ctx context.Context) (*ReconcileResult, error) {
+	client := s.redisClient.GetClient()
+
+	members, err := client.ZRangeWithScores(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	result := &ReconcileResult{Scanned: len(members)}
+	stale := make([]interface{}, 0)
+
+	for _, member := range members {
+		jobID, _ := member.Member.(string)
+		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
+		jobData, err := client.Get(ctx, jobKey).Bytes()
+		if err == redis.Nil {
+			stale = append(stale, jobID)
+			result.MissingDetails++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
+			result.Corrupt++
+			continue
+		}
+
+		if job.Status.IsTerminal() {
+			stale = append(stale, jobID)
+			result.Terminal++
+			continue
+		}
+
+		// Scores written before millisecond precision would make the job look long overdue
+		if score := command.JobScore(job.ScheduledAt); member.Score != score {
+			if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: score, Member: jobID}).Err(); err != nil {
+				return nil, fmt.Errorf("failed to rescore job %s: %w", jobID, err)
+			}
+			result.Rescored++
+		}
+	}
+
+	if len(stale) > 0 {
+		if err := client.ZRem(ctx, command.JobsSortedSetKey, stale...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to remove stale jobs: %w", err)
+		}
+	}
+
+	s.logger.Info("Reconciled job sorted set",
+		"scanned", result.Scanned,
+		"missing_details", result.MissingDetails,
+		"terminal", result.Terminal,
+		"corrupt", result.Corrupt,
+		"rescored", result.Rescored,
+	)
+
+	return result, nil
+}
+
+// MigrateJobScores rescales job scores written in Unix seconds by earlier versions to
+// milliseconds and returns how many it migrated. Left as they are, such jobs look decades
+// overdue and are assigned ahead of jobs that are actually due. Only legacy scores are
+// touched, so any pod may run it, and running it again is a no-op.
+func (s *Scheduler) MigrateJobScores(ctx context.Context) (int, error) {
+	client := s.redisClient.GetClient()
+
+	legacy, err := client.ZRangeByScoreWithScores(ctx, command.JobsSortedSetKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(legacyScoreLimit, 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch legacy job scores: %w", err)
+	}
+	if len(legacy) == 0 {
+		return 0, nil
+	}
+
+	for i := range legacy {
+		legacy[i].Score *= 1000
+	}
+	// XX leaves out jobs that finished and left the set in the meantime
+	if err := client.ZAddXX(ctx, command.JobsSortedSetKey, legacy...).Err(); err != nil {
+		return 0, fmt.Errorf("failed to migrate legacy job scores: %w", err)
+	}
+
+	s.logger.Info("Migrated job scores from seconds to milliseconds", "jobs", len(legacy))
+	return len(legacy), nil
+}
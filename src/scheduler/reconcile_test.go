@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestReconcileJobsFixesInconsistentState(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	client := testClient.GetClient()
+	now := testNow()
+
+	live := command.NewJob("work", nil, now.Add(time.Minute))
+	storeJob(t, live)
+
+	finished := command.NewJob("work", nil, now.Add(-time.Minute))
+	finished.Status = command.Success
+	storeJob(t, finished)
+
+	legacy := command.NewJob("work", nil, now.Add(2*time.Minute))
+	storeJob(t, legacy)
+	legacyScore := float64(legacy.ScheduledAt.Unix())
+	if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: legacyScore, Member: legacy.ID}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: command.JobScore(now), Member: "orphan_1"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(ctx, fmt.Sprintf(command.JobDetailsKey, "corrupt_1"), "{not json", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: command.JobScore(now), Member: "corrupt_1"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.ReconcileJobs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ReconcileResult{Scanned: 5, MissingDetails: 1, Terminal: 1, Corrupt: 1, Rescored: 1}
+	if *result != want {
+		t.Fatalf("reconcile result %+v, want %+v", *result, want)
+	}
+
+	members := jobSet(t)
+	slices.Sort(members)
+	wantMembers := []string{legacy.ID, live.ID}
+	slices.Sort(wantMembers)
+	if !slices.Equal(members, wantMembers) {
+		t.Fatalf("job set holds %v after reconciling, want %v", members, wantMembers)
+	}
+	score, err := client.ZScore(ctx, command.JobsSortedSetKey, legacy.ID).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != command.JobScore(legacy.ScheduledAt) {
+		t.Fatalf("legacy job scored %v, want %v", score, command.JobScore(legacy.ScheduledAt))
+	}
+	if loadJob(t, finished.ID).Status != command.Success {
+		t.Fatal("details of the finished job were changed")
+	}
+}
+
+func TestReconcileJobsLeavesConsistentStateAlone(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	job := command.NewJob("work", nil, testNow().Add(time.Minute))
+	storeJob(t, job)
+
+	result, err := s.ReconcileJobs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ReconcileResult{Scanned: 1}) {
+		t.Fatalf("reconcile result %+v for a consistent job set", *result)
+	}
+	if members := jobSet(t); !slices.Equal(members, []string{job.ID}) {
+		t.Fatalf("job set holds %v, want %v", members, []string{job.ID})
+	}
+}
+
+func TestAssignmentPassMigratesSecondScores(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.NextJobCount = 2
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", nil))
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true})
+	client := testClient.GetClient()
+	later := testNow().Truncate(time.Second).Add(time.Hour)
+
+	// Written by an earlier version, the second scores sort ahead of the due job
+	legacy := make([]*command.Job, 0, 2)
+	for i := range 2 {
+		job := command.NewJob("work", nil, later.Add(time.Duration(i)*time.Second))
+		storeJob(t, job)
+		if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: float64(job.ScheduledAt.Unix()), Member: job.ID}).Err(); err != nil {
+			t.Fatal(err)
+		}
+		legacy = append(legacy, job)
+	}
+	due := command.NewJob("work", nil, testNow().Add(-time.Second))
+	storeJob(t, due)
+
+	s.assignmentPass(ctx)
+	if job := loadJob(t, due.ID); job.AssignedTo != testPodID {
+		t.Fatalf("due job is assigned to %q, want %s ahead of the migrated jobs", job.AssignedTo, testPodID)
+	}
+	for _, job := range legacy {
+		score, err := client.ZScore(ctx, command.JobsSortedSetKey, job.ID).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if score != command.JobScore(job.ScheduledAt) {
+			t.Fatalf("job %s scored %v after migrating, want %v", job.ID, score, command.JobScore(job.ScheduledAt))
+		}
+		if loadJob(t, job.ID).AssignedTo != "" {
+			t.Fatalf("job %s due in an hour was assigned", job.ID)
+		}
+	}
+
+	if migrated, err := s.MigrateJobScores(ctx); err != nil || migrated != 0 {
+		t.Fatalf("migrating again moved %d scores (%v), want none", migrated, err)
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// redactedText replaces every match of a redaction pattern
+const redactedText = "***"
+
+// outputRedactor masks secrets in command output using OUTPUT_REDACT_PATTERNS
+// and the CMD_REDACT_PATTERNS_<command id> patterns of the job's command
+type outputRedactor struct {
+	global     []*regexp.Regexp
+	perCommand map[string][]*regexp.Regexp
+}
+
+// newOutputRedactor compiles the configured patterns, invalid ones are logged and skipped
+func newOutputRedactor(config *utils.Config, logger *utils.StandardLogger) *outputRedactor {
+	compile := func(commandID string, patterns []string) []*regexp.Regexp {
+		compiled := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pattern := range patterns {
+			if strings.TrimSpace(pattern) == "" {
+				continue
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				logger.Error("Ignoring invalid redaction pattern", "command", commandID, "pattern", pattern, "error", err)
+				continue
+			}
+			compiled = append(compiled, re)
+		}
+		return compiled
+	}
+
+	r := &outputRedactor{
+		global:     compile("", config.OutputRedactPatterns),
+		perCommand: make(map[string][]*regexp.Regexp, len(config.CommandRedactPatterns)),
+	}
+	for commandID, patterns := range config.CommandRedactPatterns {
+		r.perCommand[commandID] = compile(commandID, patterns)
+	}
+	return r
+}
+
+// redact replaces every match of the global and the command's patterns in text with ***
+func (r *outputRedactor) redact(commandID, text string) string {
+	for _, re := range r.global {
+		text = re.ReplaceAllString(text, redactedText)
+	}
+	for _, re := range r.perCommand[commandID] {
+		text = re.ReplaceAllString(text, redactedText)
+	}
+	return text
+}
+
+// redactError returns err with its message redacted, err itself when nothing matched
+func (r *outputRedactor) redactError(commandID string, err error) error {
+	msg := r.redact(commandID, err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactedError keeps the wrapped error reachable, e.g. for exit codes, while hiding its message
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
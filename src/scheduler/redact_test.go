@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// echoParams outputs its params, or fails with them as the error message after exiting with code 3
+func echoParams(ctx context.Context, params []string) (string, error) {
+	text := strings.Join(params, " ")
+	if strings.HasPrefix(text, "fail") {
+		return "", &redactTestError{msg: text, err: exec.Command("sh", "-c", "exit 3").Run()}
+	}
+	return text, nil
+}
+
+// redactTestError carries a message next to the exit error it wraps
+type redactTestError struct {
+	msg string
+	err error
+}
+
+func (e *redactTestError) Error() string { return e.msg }
+func (e *redactTestError) Unwrap() error { return e.err }
+
+func TestOutputIsRedacted(t *testing.T) {
+	config := testConfig()
+	config.OutputRedactPatterns = []string{`token=\S+`}
+	config.CommandRedactPatterns = map[string][]string{"work": {"hunter2"}}
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", echoParams))
+	s.RegisterCommand(funcCommand("other", echoParams))
+
+	tests := []struct {
+		commandID string
+		output    string
+		want      string
+	}{
+		{"work", "url?token=abc123 password hunter2", "url?*** password ***"},
+		{"other", "url?token=abc123 password hunter2", "url?*** password hunter2"},
+		{"work", "nothing to hide", "nothing to hide"},
+	}
+	for _, tt := range tests {
+		job := command.NewJob(tt.commandID, strings.Fields(tt.output), testNow())
+		if err := s.executeJob(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+		if job.Output != tt.want {
+			t.Errorf("%s output %q stored as %q, want %q", tt.commandID, tt.output, job.Output, tt.want)
+		}
+	}
+
+	job := command.NewJob("work", []string{"fail", "with", "hunter2"}, testNow())
+	err := s.executeJob(context.Background(), job)
+	if err == nil || err.Error() != "fail with ***" {
+		t.Fatalf("executeJob = %v, want the error message redacted", err)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("redacted error %v lost its exit code", err)
+	}
+}
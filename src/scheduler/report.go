@@ -0,0 +1,258 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// StatusReport is a read-only consistency check of the scheduler state in Redis.
+// Healthy is true when none of the inconsistencies below were found.
+type StatusReport struct {
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Healthy     bool                      `json:"healthy"`
+	JobCounts   map[command.JobStatus]int `json:"job_counts"` // Jobs in the job set per status
+
+	MissingDetails  []string       `json:"missing_details"`  // Job set members whose details are gone
+	CorruptJobs     []string       `json:"corrupt_jobs"`     // Job set members whose details cannot be decoded
+	TerminalInSet   []string       `json:"terminal_in_set"`  // Finished jobs still in the job set
+	OrphanedDetails []string       `json:"orphaned_details"` // Pending jobs whose details exist outside the job set
+	StuckRunning    []string       `json:"stuck_running"`    // Running jobs without a job lock or on a dead pod
+	DeadPods        []string       `json:"dead_pods"`        // Registry entries that missed heartbeats for LEADERSHIP_STALENESS
+	CounterDrift    []CounterDrift `json:"counter_drift"`    // Sorted sets whose size differs from the jobs they index
+}
+
+// CounterDrift compares the size of a sorted set with the number of its members that still have usable details
+type CounterDrift struct {
+	Key      string `json:"key"`
+	Recorded int    `json:"recorded"` // Members in the sorted set
+	Actual   int    `json:"actual"`   // Members whose details exist and decode
+}
+
+// StatusReport compiles a StatusReport without changing anything, see ReconcileJobs
+// and RunMaintenance for the fixes. It answers "is everything healthy?" in one call.
+func (s *Scheduler) StatusReport(ctx context.Context) (*StatusReport, error) {
+	client := s.redisClient.GetClient()
+	report := &StatusReport{
+		GeneratedAt: time.Now(),
+		JobCounts:   make(map[command.JobStatus]int),
+	}
+
+	jobIDs, err := client.ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	var running []*command.Job
+	inSet := make(map[string]bool, len(jobIDs))
+	for _, jobID := range jobIDs {
+		inSet[jobID] = true
+		job, err := s.inspectJob(ctx, jobID)
+		switch {
+		case err == redis.Nil:
+			report.MissingDetails = append(report.MissingDetails, jobID)
+			continue
+		case err != nil:
+			return nil, err
+		case job == nil:
+			report.CorruptJobs = append(report.CorruptJobs, jobID)
+			continue
+		}
+
+		report.JobCounts[job.Status]++
+		if job.Status.IsTerminal() {
+			report.TerminalInSet = append(report.TerminalInSet, jobID)
+		}
+		if job.Status == command.Running {
+			running = append(running, job)
+		}
+	}
+	report.CounterDrift = appendDrift(report.CounterDrift, command.JobsSortedSetKey,
+		len(jobIDs), len(jobIDs)-len(report.MissingDetails)-len(report.CorruptJobs))
+
+	if report.OrphanedDetails, err = s.orphanedDetails(ctx, inSet); err != nil {
+		return nil, err
+	}
+
+	var pods map[string]leader.PodInfo
+	if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &pods); err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+	// Pods are pruned from the registry once stale, briefly unresponsive ones are not dead yet
+	staleness := leader.LeadershipStaleness(s.config)
+	for podID, info := range pods {
+		if !info.Alive(staleness) {
+			report.DeadPods = append(report.DeadPods, podID)
+		}
+	}
+	slices.Sort(report.DeadPods)
+
+	for _, job := range running {
+		locked, err := client.Exists(ctx, fmt.Sprintf(jobLockKey, job.ID)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check lock of job %s: %w", job.ID, err)
+		}
+		if info, ok := pods[job.AssignedTo]; locked == 0 || !ok || !info.Alive(staleness) {
+			report.StuckRunning = append(report.StuckRunning, job.ID)
+		}
+	}
+
+	completedIDs, err := client.ZRange(ctx, command.CompletedJobsKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch completed jobs: %w", err)
+	}
+	completed := 0
+	for _, jobID := range completedIDs {
+		job, err := s.inspectJob(ctx, jobID)
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		if job != nil {
+			completed++
+		}
+	}
+	report.CounterDrift = appendDrift(report.CounterDrift, command.CompletedJobsKey, len(completedIDs), completed)
+
+	report.Healthy = len(report.MissingDetails) == 0 && len(report.CorruptJobs) == 0 &&
+		len(report.TerminalInSet) == 0 && len(report.OrphanedDetails) == 0 &&
+		len(report.StuckRunning) == 0 && len(report.DeadPods) == 0 && len(report.CounterDrift) == 0
+	return report, nil
+}
+
+// inspectJob reads a job's details without discarding them when they are corrupt.
+// It returns redis.Nil when the details are gone and a nil job when they cannot be decoded.
+func (s *Scheduler) inspectJob(ctx context.Context, jobID string) (*command.Job, error) {
+	data, err := s.redisClient.GetClient().Get(ctx, fmt.Sprintf(command.JobDetailsKey, jobID)).Bytes()
+	if err == redis.Nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+
+	var job command.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// orphanedDetails returns the IDs of jobs that are not finished but missing from the job set.
+// Details of finished jobs outside the set are expected, they expire on their own.
+func (s *Scheduler) orphanedDetails(ctx context.Context, inSet map[string]bool) ([]string, error) {
+	client := s.redisClient.GetClient()
+	prefix := strings.TrimSuffix(command.JobDetailsKey, "%s")
+
+	var orphaned []string
+	iter := client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		jobID := strings.TrimPrefix(iter.Val(), prefix)
+		if inSet[jobID] {
+			continue
+		}
+		job, err := s.inspectJob(ctx, jobID)
+		if err == redis.Nil {
+			continue // Expired since the scan returned it
+		}
+		if err != nil {
+			return nil, err
+		}
+		if job != nil && !job.Status.IsTerminal() {
+			orphaned = append(orphaned, jobID)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan keys %s*: %w", prefix, err)
+	}
+	slices.Sort(orphaned)
+	return orphaned, nil
+}
+
+// appendDrift adds a CounterDrift for key when recorded and actual differ
+func appendDrift(drift []CounterDrift, key string, recorded, actual int) []CounterDrift {
+	if recorded == actual {
+		return drift
+	}
+	return append(drift, CounterDrift{Key: key, Recorded: recorded, Actual: actual})
+}
+
+// Summary returns the report as a single line of counts, e.g. for logs and job output
+func (r *StatusReport) Summary() string {
+	return fmt.Sprintf("healthy=%t missing_details=%d corrupt=%d terminal_in_set=%d orphaned_details=%d stuck_running=%d dead_pods=%d counter_drift=%d",
+		r.Healthy, len(r.MissingDetails), len(r.CorruptJobs), len(r.TerminalInSet), len(r.OrphanedDetails),
+		len(r.StuckRunning), len(r.DeadPods), len(r.CounterDrift))
+}
+
+// StatusCommand logs a StatusReport on a schedule, warning when the state is inconsistent
+type StatusCommand struct {
+	scheduler *Scheduler
+	schedule  string
+}
+
+// NewStatusCommand creates a new StatusCommand reporting on the given scheduler
+func NewStatusCommand(scheduler *Scheduler, schedule string) *StatusCommand {
+	return &StatusCommand{
+		scheduler: scheduler,
+		schedule:  schedule,
+	}
+}
+
+// ID returns the command identifier
+func (c *StatusCommand) ID() string {
+	return "status"
+}
+
+// Description returns the command description
+func (c *StatusCommand) Description() string {
+	return "Report inconsistencies in the scheduler state"
+}
+
+// Execute compiles the report and prints its summary
+func (c *StatusCommand) Execute(params []string) error {
+	output, err := c.ExecuteWithOutput(params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// ExecuteWithOutput compiles and logs the report, returning its summary
+func (c *StatusCommand) ExecuteWithOutput(params []string) (string, error) {
+	report, err := c.scheduler.StatusReport(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("status report failed: %w", err)
+	}
+
+	if report.Healthy {
+		c.scheduler.logger.Info("Scheduler state is consistent", "job_counts", report.JobCounts)
+	} else {
+		c.scheduler.logger.Warn("Scheduler state is inconsistent",
+			"missing_details", report.MissingDetails,
+			"corrupt_jobs", report.CorruptJobs,
+			"terminal_in_set", report.TerminalInSet,
+			"orphaned_details", report.OrphanedDetails,
+			"stuck_running", report.StuckRunning,
+			"dead_pods", report.DeadPods,
+			"counter_drift", report.CounterDrift,
+		)
+	}
+	return report.Summary() + "\n", nil
+}
+
+// Schedule returns the cron schedule and parameters for the command
+func (c *StatusCommand) Schedule() (string, []string, error) {
+	return c.schedule, c.Parameters(), nil
+}
+
+// Parameters returns the default parameters for the command
+func (c *StatusCommand) Parameters() []string {
+	return []string{}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestStatusReportOfConsistentState(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	storeJob(t, command.NewJob("work", nil, testNow()))
+	heldJob(t, testNow().Add(time.Minute), command.Assigned)
+
+	report, err := s.StatusReport(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Healthy || report.JobCounts[command.Scheduled] != 1 || report.JobCounts[command.Assigned] != 1 {
+		t.Fatalf("report = %s with counts %v, want a healthy report counting both jobs", report.Summary(), report.JobCounts)
+	}
+}
+
+func TestStatusReportFindsEachInconsistency(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	client := testClient.GetClient()
+	now := testNow()
+
+	missing := "work_1000"
+	if err := client.ZAdd(ctx, command.JobsSortedSetKey, redis.Z{Score: command.JobScore(now), Member: missing}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := "work_1001"
+	seedCorruptJob(t, corrupt)
+	terminal := command.NewJob("work", nil, now.Add(time.Second))
+	terminal.Status = command.Success
+	storeJob(t, terminal)
+	orphaned := command.NewJob("work", nil, now.Add(2*time.Second))
+	storeJob(t, orphaned)
+	if err := client.ZRem(ctx, command.JobsSortedSetKey, orphaned.ID).Err(); err != nil {
+		t.Fatal(err)
+	}
+	stuck := heldJob(t, now.Add(3*time.Second), command.Running) // Running without a job lock
+	setTestPods(t,
+		leader.PodInfo{ID: testPodID, IsLeader: true},
+		leader.PodInfo{ID: "dead-pod", LastSeen: time.Now().Add(-time.Hour)},
+	)
+	if err := client.ZAdd(ctx, command.CompletedJobsKey, redis.Z{Score: command.JobScore(now), Member: "work_1002"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.StatusReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Healthy {
+		t.Fatal("report of inconsistent state is healthy")
+	}
+	found := map[string][]string{
+		"missing details":  report.MissingDetails,
+		"corrupt jobs":     report.CorruptJobs,
+		"terminal in set":  report.TerminalInSet,
+		"orphaned details": report.OrphanedDetails,
+		"stuck running":    report.StuckRunning,
+		"dead pods":        report.DeadPods,
+	}
+	want := map[string][]string{
+		"missing details":  {missing},
+		"corrupt jobs":     {corrupt},
+		"terminal in set":  {terminal.ID},
+		"orphaned details": {orphaned.ID},
+		"stuck running":    {stuck.ID},
+		"dead pods":        {"dead-pod"},
+	}
+	for name, ids := range want {
+		if !slices.Equal(found[name], ids) {
+			t.Errorf("report lists %s %q, want %q", name, found[name], ids)
+		}
+	}
+	wantDrift := []CounterDrift{
+		{Key: command.JobsSortedSetKey, Recorded: 4, Actual: 2},
+		{Key: command.CompletedJobsKey, Recorded: 1, Actual: 0},
+	}
+	if !slices.Equal(report.CounterDrift, wantDrift) {
+		t.Errorf("report lists counter drift %+v, want %+v", report.CounterDrift, wantDrift)
+	}
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// resultCacheKey stores the output of a successful run, keyed by command and params hash
+const resultCacheKey = "schedulerx:result_cache:%s:%s"
+
+// resultCacheKeyFor returns the cache key of the job's command and the params it runs with,
+// which include the shard index so the shards of a job do not share a result
+func resultCacheKeyFor(job *command.Job) (string, error) {
+	params, err := json.Marshal(job.ExecutionParams())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal params: %w", err)
+	}
+	sum := sha256.Sum256(params)
+	return fmt.Sprintf(resultCacheKey, job.CommandID, hex.EncodeToString(sum[:])), nil
+}
+
+// cachedResult returns the output of an earlier successful run with the same params.
+// hit is false when nothing is cached or the command has no COMMAND_RESULT_CACHE_TTL.
+func (s *Scheduler) cachedResult(ctx context.Context, job *command.Job) (output string, hit bool, err error) {
+	if s.config.CommandResultCacheTTL[job.CommandID] <= 0 {
+		return "", false, nil
+	}
+
+	key, err := resultCacheKeyFor(job)
+	if err != nil {
+		return "", false, err
+	}
+	output, err = s.redisClient.GetClient().Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get cached result of command %s: %w", job.CommandID, err)
+	}
+	return output, true, nil
+}
+
+// cacheResult stores the output of a successful run for the command's COMMAND_RESULT_CACHE_TTL
+func (s *Scheduler) cacheResult(ctx context.Context, job *command.Job) error {
+	ttl := s.config.CommandResultCacheTTL[job.CommandID]
+	if ttl <= 0 {
+		return nil
+	}
+
+	key, err := resultCacheKeyFor(job)
+	if err != nil {
+		return err
+	}
+	if err := s.redisClient.GetClient().Set(ctx, key, job.Output, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache result of command %s: %w", job.CommandID, err)
+	}
+	return nil
+}
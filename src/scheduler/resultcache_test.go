@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestResultCacheKeepsShardsApart(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CommandResultCacheTTL = map[string]time.Duration{"work": time.Minute}
+	s := newTestScheduler(t, config)
+
+	shard := func(index int) *command.Job {
+		job := command.NewJob("work", []string{"/var"}, testNow())
+		job.Shards, job.Shard = 2, index
+		return job
+	}
+
+	first := shard(0)
+	first.Output = "shard 0 output"
+	if err := s.cacheResult(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	if output, hit, err := s.cachedResult(ctx, shard(0)); err != nil || !hit || output != first.Output {
+		t.Fatalf("cachedResult of the same shard = %q, %v, %v, want a hit", output, hit, err)
+	}
+	if output, hit, err := s.cachedResult(ctx, shard(1)); err != nil || hit {
+		t.Fatalf("cachedResult of another shard = %q, %v, %v, want a miss", output, hit, err)
+	}
+}
+
+func TestResultCacheHitMissAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CommandResultCacheTTL = map[string]time.Duration{"work": time.Minute}
+	s := newTestScheduler(t, config)
+	runs := countRuns(s)
+
+	run := func(params ...string) *command.Job {
+		t.Helper()
+		job := command.NewJob("work", params, testNow())
+		if err := s.executeJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+
+	if job := run("/var"); job.CacheHit || *runs != 1 {
+		t.Fatalf("first run: cache hit %v after %d runs, want a miss", job.CacheHit, *runs)
+	}
+	if job := run("/var"); !job.CacheHit || job.Output != "done" || *runs != 1 {
+		t.Fatalf("repeated run: cache hit %v with %q after %d runs, want the cached output", job.CacheHit, job.Output, *runs)
+	}
+	if job := run("/tmp"); job.CacheHit || *runs != 2 {
+		t.Fatalf("other params: cache hit %v after %d runs, want a miss", job.CacheHit, *runs)
+	}
+
+	testRedis.FastForward(2 * time.Minute)
+	if job := run("/var"); job.CacheHit || *runs != 3 {
+		t.Fatalf("after expiry: cache hit %v after %d runs, want a miss", job.CacheHit, *runs)
+	}
+}
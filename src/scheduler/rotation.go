@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// assignmentCursorKey holds the pod that received the last job of the previous
+// assignment round, the next round starts with the pod after it
+const assignmentCursorKey = "schedulerx:assignment_cursor"
+
+// rotatedPods returns the pods in ID order, rotated to start right after the pod
+// in the assignment cursor. Without it small rounds would always favour the first
+// pods. The cursor holds a pod ID rather than an index, so pods joining or leaving
+// do not shift the rotation, and a cursor pod that left is simply skipped over.
+func (s *Scheduler) rotatedPods(ctx context.Context, pods []string) []string {
+	sorted := slices.Sorted(slices.Values(pods))
+	if len(sorted) < 2 {
+		return sorted
+	}
+
+	cursor, err := s.redisClient.GetClient().Get(ctx, assignmentCursorKey).Result()
+	if err != nil {
+		if err != redis.Nil {
+			s.logger.Warn("Failed to read assignment cursor, starting at the first pod", "error", err)
+		}
+		return sorted
+	}
+	return rotateAfter(sorted, cursor)
+}
+
+// rotateAfter rotates sorted pods to start with the first pod ordered after cursor, wrapping around
+func rotateAfter(sorted []string, cursor string) []string {
+	offset, found := slices.BinarySearch(sorted, cursor)
+	if found {
+		offset++
+	}
+	offset %= len(sorted)
+	return slices.Concat(sorted[offset:], sorted[:offset])
+}
+
+// advanceAssignmentCursor records the pod that received the last job of a round
+func (s *Scheduler) advanceAssignmentCursor(ctx context.Context, lastPod string) {
+	if lastPod == "" {
+		return
+	}
+	if err := s.redisClient.GetClient().Set(ctx, assignmentCursorKey, lastPod, 0).Err(); err != nil {
+		s.logger.Warn("Failed to advance assignment cursor", "error", err)
+	}
+}
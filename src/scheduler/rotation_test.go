@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// assignTicks runs one assignment round per tick with a single due job each,
+// returning how many jobs each pod received
+func assignTicks(t *testing.T, s *Scheduler, ticks int, pods []string) map[string]int {
+	t.Helper()
+	received := make(map[string]int, len(pods))
+	for range ticks {
+		job := command.NewJob("work", nil, testNow().Add(-time.Duration(len(jobSet(t))+1)*time.Second))
+		storeJob(t, job)
+		if err := s.AssignJobs(context.Background(), pods); err != nil {
+			t.Fatal(err)
+		}
+		received[loadJob(t, job.ID).AssignedTo]++
+	}
+	return received
+}
+
+func TestAssignmentRotatesAcrossTicks(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+	pods := []string{"pod-c", "pod-a", "pod-b"}
+
+	received := assignTicks(t, s, 6, pods)
+	for _, pod := range pods {
+		if received[pod] != 2 {
+			t.Fatalf("6 single-job ticks over 3 pods assigned %v, want 2 jobs each", received)
+		}
+	}
+}
+
+func TestAssignmentCursorSurvivesPodsJoiningAndLeaving(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+
+	assignTicks(t, s, 2, []string{"pod-a", "pod-b", "pod-c"})
+	// pod-b received the last job, pod-c leaves and pod-d joins
+	received := assignTicks(t, s, 9, []string{"pod-a", "pod-b", "pod-d"})
+	for _, pod := range []string{"pod-a", "pod-b", "pod-d"} {
+		if received[pod] != 3 {
+			t.Fatalf("9 single-job ticks over 3 pods assigned %v, want 3 jobs each", received)
+		}
+	}
+}
+
+func TestRotateAfter(t *testing.T) {
+	sorted := []string{"pod-a", "pod-c", "pod-e"}
+	tests := []struct {
+		cursor string
+		want   string
+	}{
+		{"pod-a", "pod-c"},
+		{"pod-e", "pod-a"},
+		{"pod-b", "pod-c"}, // Cursor pod left, continue with the next one in order
+		{"pod-f", "pod-a"},
+		{"pod-0", "pod-a"},
+	}
+	for _, tt := range tests {
+		if got := rotateAfter(sorted, tt.cursor); got[0] != tt.want || len(got) != len(sorted) {
+			t.Errorf("rotateAfter(%s) = %v, want it to start with %s", tt.cursor, got, tt.want)
+		}
+	}
+}
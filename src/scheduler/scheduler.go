@@ -3,18 +3,43 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
+	"github.com/yashkumarverma/schedulerx/src/assignment"
 	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/export"
 	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+	"github.com/yashkumarverma/schedulerx/src/telemetry"
 	"github.com/yashkumarverma/schedulerx/src/utils"
 	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
-	// SchedulingWindow is the time window for which we schedule jobs
+	// jobLockKey is held by the pod executing a job
+	jobLockKey = "schedulerx:job_lock:%s"
+
+	// maxOccurrencesPerWindow bounds the occurrences computed per command and window,
+	// guarding against degenerate schedules such as one firing every second over a long lookahead
+	maxOccurrencesPerWindow = 10000
+
+	// assignmentInterval is how often the leader assigns due jobs to pods
+	assignmentInterval = 30 * time.Second
+
+	// SchedulingWindow is the default time window for which we schedule jobs, see SCHEDULING_LOOKAHEAD
 	SchedulingWindow = 5 * time.Minute
 )
 
@@ -23,39 +48,118 @@ type Scheduler struct {
 	redisClient *cache.Client
 	logger      *utils.StandardLogger
 	config      *utils.Config
+	commandsMu  sync.RWMutex
 	commands    map[string]command.Command
+	strategy    assignment.AssignmentStrategy
+	exporter    *export.Exporter
+	observers   []Observer
+	redactor    *outputRedactor
+
+	// backpressure is set while pending jobs are above the high-water mark
+	backpressure atomic.Bool
+
+	// scheduleNow and assignNow request an immediate scheduling or assignment
+	// pass, e.g. on promotion. Requests coalesce while one is pending.
+	scheduleNow chan struct{}
+	assignNow   chan struct{}
 }
 
 // NewScheduler creates a new scheduler instance
 func NewScheduler(redisClient *cache.Client, logger *utils.StandardLogger, config *utils.Config) *Scheduler {
+	strategy, err := assignment.StrategyByName(config.AssignmentStrategy)
+	if err != nil {
+		logger.Warn("Invalid assignment strategy", "error", err)
+	}
+
 	return &Scheduler{
 		redisClient: redisClient,
 		logger:      logger,
 		config:      config,
 		commands:    make(map[string]command.Command),
+		strategy:    strategy,
+		redactor:    newOutputRedactor(config, logger),
+		scheduleNow: make(chan struct{}, 1),
+		assignNow:   make(chan struct{}, 1),
 	}
 }
 
-// RegisterCommand adds a command to the scheduler
+// SetExporter sets where finished jobs are exported to, nil disables exporting
+func (s *Scheduler) SetExporter(exporter *export.Exporter) {
+	s.exporter = exporter
+}
+
+// SetAssignmentStrategy replaces the strategy used to pick a pod for each job
+func (s *Scheduler) SetAssignmentStrategy(strategy assignment.AssignmentStrategy) {
+	s.strategy = strategy
+}
+
+// RegisterCommand adds a command to the scheduler. Commands with an unsafe ID
+// (see command.ValidateCommandID) are logged and skipped.
 func (s *Scheduler) RegisterCommand(cmd command.Command) {
+	if err := command.ValidateCommandID(cmd.ID()); err != nil {
+		s.logger.Error("Refusing to register command", "error", err)
+		return
+	}
+
+	s.commandsMu.Lock()
+	defer s.commandsMu.Unlock()
 	s.commands[cmd.ID()] = cmd
 }
 
+// GetCommand returns the registered command with the given ID
+func (s *Scheduler) GetCommand(id string) (command.Command, bool) {
+	s.commandsMu.RLock()
+	defer s.commandsMu.RUnlock()
+	cmd, exists := s.commands[id]
+	return cmd, exists
+}
+
+// Commands returns a snapshot of the registered commands, keyed by ID. It is safe
+// to iterate while commands are being registered concurrently.
+func (s *Scheduler) Commands() map[string]command.Command {
+	s.commandsMu.RLock()
+	defer s.commandsMu.RUnlock()
+	return maps.Clone(s.commands)
+}
+
 // ScheduleJobs schedules the next batch of jobs
 func (s *Scheduler) ScheduleJobs(ctx context.Context) error {
 	if !leader.IsLeader() {
 		return nil
 	}
 
+	ctx, span := telemetry.Tracer().Start(ctx, "ScheduleJobs")
+	defer span.End()
+
+	// Nothing is scheduled while the cluster is drained for maintenance
+	if draining, err := s.isClusterDraining(ctx); err != nil {
+		s.logger.Error("Failed to check cluster drain", "error", err)
+	} else if draining {
+		s.logger.Debug("Cluster is draining, skipping scheduling")
+		return nil
+	}
+
 	s.logger.Info("Scheduling jobs for all registered commands")
 
+	paused, err := s.applyBackpressure(ctx)
+	if err != nil {
+		s.logger.Error("Failed to check pending jobs", "error", err)
+	}
+
 	// Get current time and end of scheduling window
 	now := time.Now()
-	endTime := now.Add(SchedulingWindow)
+	endTime := now.Add(s.lookahead())
+
+	// Schedule the commands registered with this scheduler, including custom ones
+	commands := s.Commands()
+
+	// Nothing is enqueued while backpressure is applied
+	if paused {
+		commands = nil
+	}
 
-	// Get all commands from registry
-	cmdRegistry := command.NewCommandRegistry()
-	commands := cmdRegistry.GetCommands()
+	// Jobs rejected because the queue is full, logged once per pass
+	rejected := 0
 
 	// For each command, find execution times in the window
 	for cmdID, cmd := range commands {
@@ -65,33 +169,120 @@ func (s *Scheduler) ScheduleJobs(ctx context.Context) error {
 			continue
 		}
 
-		// Parse cron expression
-		parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-		schedule, err := parser.Parse(scheduleStr)
+		schedule, err := NewParser().ParseSchedule(scheduleStr)
 		if err != nil {
-			s.logger.Error("Failed to parse cron expression", "command", cmdID, "error", err)
+			s.logger.Error("Failed to parse schedule", "command", cmdID, "error", err)
+			continue
+		}
+
+		// An empty schedule means the command is not self-scheduled and only runs when triggered
+		if schedule.Type == NoSchedule {
+			continue
+		}
+
+		params, err = command.CoerceCommandParams(cmd, params)
+		if err != nil {
+			s.logger.Error("Invalid params for command", "command", cmdID, "error", err)
+			continue
+		}
+
+		// Run-after-finish schedules are driven by job completion, only the first run is enqueued here
+		if schedule.Type == AfterSchedule {
+			if err := s.bootstrapAfterSchedule(ctx, cmd, params); err != nil {
+				s.logger.Error("Failed to enqueue first run", "command", cmdID, "error", err)
+				if errors.Is(err, command.ErrStaleFencingToken) {
+					return err
+				}
+			}
 			continue
 		}
 
-		// Get next execution times until end of window
-		next := schedule.Next(now)
-		for next.Before(endTime) {
-			// Create job
-			job := command.NewJob(cmdID, params, next)
+		labels := command.MergeLabels(cmd, nil)
 
-			// Store job in Redis
-			if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
-				s.logger.Error("Failed to store job", "job_id", job.ID, "error", err)
+		// A schedule that never fires again (e.g. a date in the past) has nothing to enqueue.
+		// A one-off @at schedule is expected to end up here once its time passed.
+		if schedule.Next(now).IsZero() {
+			if schedule.Type == AtSchedule {
 				continue
 			}
+			s.logger.Warn("Schedule yields no future occurrences", "command", cmdID, "schedule", scheduleStr)
+			continue
+		}
 
-			next = schedule.Next(next)
+		// Commands that must not overlap skip occurrences while an earlier run is still active
+		var active []*command.Job
+		skipIfRunning := slices.Contains(s.config.SkipIfStillRunningCommands, cmdID)
+		if skipIfRunning {
+			active, err = s.jobsForCommand(ctx, cmdID, command.Running, command.Assigned)
+			if err != nil {
+				s.logger.Error("Failed to check active jobs", "command", cmdID, "error", err)
+				continue
+			}
 		}
+
+		// Create a job (or one per shard) for every execution time in the window
+		occurrences := limitOccurrences(occurrencesInWindow(schedule, now, endTime), s.config.SchedulingLookaheadOccurrences)
+		for _, next := range occurrences {
+			if skipIfRunning {
+				if previousID := previousRunActive(active, next); previousID != "" {
+					s.logger.Info("Skipping occurrence, previous run is still active",
+						"command", cmdID, "scheduled_at", next, "previous_job_id", previousID)
+					continue
+				}
+			}
+
+			for _, job := range command.NewShardedJobs(cmdID, params, next, s.config.CommandParallelism[cmdID]) {
+				// Occurrences stored by an earlier pass keep their details, e.g. an assignment, a pin or their outcome
+				exists, err := s.jobExists(ctx, job.ID)
+				if err != nil {
+					s.logger.Error("Failed to check job", "job_id", job.ID, "error", err)
+					continue
+				}
+				if exists {
+					continue
+				}
+
+				job.Labels = labels
+				job.TraceContext = telemetry.Inject(ctx)
+				if err := s.admitJob(ctx, job); err != nil {
+					if !errors.Is(err, ErrQueueFull) {
+						s.logger.Error("Failed to check queue size", "job_id", job.ID, "error", err)
+					}
+					rejected++
+					continue
+				}
+				// Store job in Redis, guarded by the leader fencing token
+				created, err := s.createJobFenced(ctx, job)
+				if err != nil {
+					s.logger.Error("Failed to store job", "job_id", job.ID, "error", err)
+					if errors.Is(err, command.ErrStaleFencingToken) {
+						return err
+					}
+					continue
+				}
+				if !created {
+					continue
+				}
+				metrics.JobsEnqueued.Inc(cmdID)
+				s.jobScheduled(job)
+			}
+		}
+	}
+
+	if rejected > 0 {
+		s.logger.Warn("Job queue full, jobs were not enqueued", "rejected", rejected, "max_queued_jobs", s.config.MaxQueuedJobs)
 	}
 
+	return nil
+}
+
+// Start runs the job assignment loop (acting only while this pod is leader) and
+// the job execution loop in the background until ctx is cancelled. It must be
+// called once per pod, followers need it to execute the jobs assigned to them.
+func (s *Scheduler) Start(ctx context.Context) {
 	// Start job assignment routine
 	go func() {
-		ticker := time.NewTicker(30 * time.Second) // Reduced frequency to 30 seconds
+		ticker := time.NewTicker(assignmentInterval)
 		defer ticker.Stop()
 
 		for {
@@ -99,27 +290,29 @@ func (s *Scheduler) ScheduleJobs(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if !leader.IsLeader() {
-					continue
-				}
+				s.assignmentPass(ctx)
+			case <-s.assignNow:
+				// Requested out of band, e.g. after promotion, restart the regular interval from here
+				s.assignmentPass(ctx)
+				ticker.Reset(assignmentInterval)
+			}
+		}
+	}()
 
-				// Get all pods from Redis
-				var pods map[string]leader.PodInfo
-				if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &pods); err != nil {
-					s.logger.Error("Failed to get pods", "error", err)
-					continue
-				}
+	// Warn about schedules that effectively never run
+	go func() {
+		ticker := time.NewTicker(neverFiresCheckInterval)
+		defer ticker.Stop()
 
-				// Get all available pods (including the leader)
-				availablePods := make([]string, 0, len(pods))
-				for podID := range pods {
-					availablePods = append(availablePods, podID)
-				}
+		for {
+			if leader.IsLeader() {
+				s.CheckNeverFiring(ctx)
+			}
 
-				// Assign jobs to available pods
-				if err := s.AssignJobs(ctx, availablePods); err != nil {
-					s.logger.Error("Failed to assign jobs", "error", err)
-				}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
 			}
 		}
 	}()
@@ -140,13 +333,90 @@ func (s *Scheduler) ScheduleJobs(ctx context.Context) error {
 			}
 		}
 	}()
+}
 
-	return nil
+// assignmentPass rebalances and assigns due jobs to available pods, acting only on the leader
+func (s *Scheduler) assignmentPass(ctx context.Context) {
+	if !leader.IsLeader() {
+		return
+	}
+
+	// No new assignments while the cluster is drained
+	if draining, err := s.isClusterDraining(ctx); err != nil || draining {
+		return
+	}
+
+	// Pods of earlier versions may still write second scores during a rolling upgrade
+	if _, err := s.MigrateJobScores(ctx); err != nil {
+		s.logger.Error("Failed to migrate job scores", "error", err)
+	}
+
+	// Get all pods from Redis
+	var pods map[string]leader.PodInfo
+	if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &pods); err != nil {
+		s.logger.Error("Failed to get pods", "error", err)
+		return
+	}
+
+	// Offload jobs from pods holding more than their capacity
+	if _, err := s.RebalanceJobs(ctx, pods); err != nil {
+		s.logger.Error("Failed to rebalance jobs", "error", err)
+	}
+
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		s.logger.Error("Failed to count assigned jobs", "error", err)
+		return
+	}
+
+	// Get all available pods (including the leader), skipping unresponsive and unhealthy pods and pods at capacity.
+	// Pods at capacity keep the jobs they hold, the jobs of unresponsive and unhealthy pods are released.
+	alivePods := make([]string, 0, len(pods))
+	availablePods := make([]string, 0, len(pods))
+	for podID, info := range pods {
+		if !info.Alive(leader.PodTTL(s.config)) {
+			continue
+		}
+		if info.Unhealthy {
+			continue
+		}
+		alivePods = append(alivePods, podID)
+		if info.Capacity > 0 && len(assigned[podID]) >= info.Capacity {
+			continue
+		}
+		availablePods = append(availablePods, podID)
+	}
+
+	// Assign jobs to available pods
+	if err := s.assignJobs(ctx, availablePods, alivePods); err != nil {
+		s.logger.Error("Failed to assign jobs", "error", err)
+	}
 }
 
-// ExecuteAssignedJobs executes jobs assigned to the current pod
+// ExecuteAssignedJobs executes jobs assigned to the current pod. Cancelling ctx stops
+// it from claiming further jobs, a claimed job still runs to completion and is recorded
+// so that shutdown can wait for it.
 func (s *Scheduler) ExecuteAssignedJobs(ctx context.Context) error {
-	currentPodID := leader.GetLeader()
+	currentPodID := leader.GetPodID()
+
+	// No new jobs start while the cluster is drained, running ones finish normally
+	draining, err := s.isClusterDraining(ctx)
+	if err != nil {
+		return err
+	}
+	if draining {
+		return nil
+	}
+
+	// A paused pod keeps its assigned jobs without starting them
+	paused, err := s.isPodPaused(ctx, currentPodID)
+	if err != nil {
+		return err
+	}
+	if paused {
+		s.logger.Debug("Pod is paused, not starting jobs", "pod_id", currentPodID)
+		return nil
+	}
 
 	// Get all jobs from Redis
 	jobs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
@@ -155,70 +425,228 @@ func (s *Scheduler) ExecuteAssignedJobs(ctx context.Context) error {
 	}
 
 	for _, jobID := range jobs {
+		// Shutting down, leave the remaining jobs for DrainPod to unassign
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Stop claiming jobs while disconnected, state read from Redis may be stale
+		if !leader.IsConnected() {
+			s.logger.Warn("Pod is disconnected, skipping job execution")
+			return nil
+		}
+
 		// Try to acquire lock for this job
-		lockKey := fmt.Sprintf("schedulerx:job_lock:%s", jobID)
+		lockKey := fmt.Sprintf(jobLockKey, jobID)
 		acquired, err := s.redisClient.GetClient().SetNX(ctx, lockKey, currentPodID, 10*time.Minute).Result()
 		if err != nil {
 			s.logger.Error("Failed to acquire job lock", "job_id", jobID, "error", err)
 			continue
 		}
 		if !acquired {
+			metrics.JobLockContention.Inc()
 			continue // Another pod is already processing this job
 		}
 
+		// The claimed job must be released and recorded even once ctx is cancelled
+		jobCtx := context.WithoutCancel(ctx)
+
 		// Get job details
 		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
-		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
+		jobData, err := s.redisClient.GetClient().Get(jobCtx, jobKey).Bytes()
 		if err != nil {
-			s.redisClient.GetClient().Del(ctx, lockKey) // Release lock if job not found
+			s.redisClient.GetClient().Del(jobCtx, lockKey) // Release lock if job not found
 			continue
 		}
 
 		var job command.Job
 		if err := json.Unmarshal(jobData, &job); err != nil {
-			s.redisClient.GetClient().Del(ctx, lockKey) // Release lock if job data is invalid
+			s.discardCorruptJob(jobCtx, jobID, jobData, err)
+			s.redisClient.GetClient().Del(jobCtx, lockKey) // Release lock if job data is invalid
 			continue
 		}
 
 		// Skip if job is not assigned to current pod or is already running/completed
 		if job.AssignedTo != currentPodID || job.Status == command.Running || job.Status == command.Success {
-			s.redisClient.GetClient().Del(ctx, lockKey) // Release lock if job shouldn't be processed
+			s.redisClient.GetClient().Del(jobCtx, lockKey) // Release lock if job shouldn't be processed
+			continue
+		}
+
+		// Jobs are assigned up to ASSIGNMENT_HORIZON ahead, they only start once due
+		if job.ScheduledAt.After(time.Now()) {
+			s.redisClient.GetClient().Del(jobCtx, lockKey)
+			continue
+		}
+
+		// Cancel jobs that are too old to be worth running
+		if s.config.MaxJobAge > 0 && job.Age() > s.config.MaxJobAge {
+			job.Cancel(fmt.Sprintf("job too old: scheduled %s ago, max age %s", job.Age().Round(time.Second), s.config.MaxJobAge))
+			if err := job.UpdateInRedis(jobCtx, s.redisClient.GetClient()); err != nil {
+				job.Logger(s.logger).Error("Failed to cancel old job", "error", err)
+			} else {
+				job.Logger(s.logger).Warn("Cancelled job exceeding max age", "scheduled_at", job.ScheduledAt)
+			}
+			s.jobFinished(jobCtx, &job)
+			s.redisClient.GetClient().Del(jobCtx, lockKey)
+			continue
+		}
+
+		// Fail jobs whose params no longer match the command's schema
+		if cmd, exists := s.GetCommand(job.CommandID); exists {
+			params, err := command.CoerceCommandParams(cmd, job.Params)
+			if err != nil {
+				job.Fail(fmt.Errorf("invalid params: %w", err))
+				if err := job.UpdateInRedis(jobCtx, s.redisClient.GetClient()); err != nil {
+					job.Logger(s.logger).Error("Failed to fail job with invalid params", "error", err)
+				} else {
+					job.Logger(s.logger).Error("Failed job with invalid params", "error", job.Error)
+				}
+				s.jobFinished(jobCtx, &job)
+				s.redisClient.GetClient().Del(jobCtx, lockKey)
+				continue
+			}
+			job.Params = params
+		}
+
+		// Defer jobs started too soon after the previous job of their command
+		wait, err := s.claimCooldown(jobCtx, &job)
+		if err != nil {
+			job.Logger(s.logger).Error("Failed to check command cooldown", "error", err)
+			s.redisClient.GetClient().Del(jobCtx, lockKey)
+			continue
+		}
+		if wait > 0 {
+			if err := s.deferJob(jobCtx, &job, wait); err != nil {
+				job.Logger(s.logger).Error("Failed to defer job within cooldown", "error", err)
+			} else {
+				job.Logger(s.logger).Info("Deferred job within command cooldown", "cooldown", s.config.CommandCooldown[job.CommandID], "scheduled_at", job.ScheduledAt)
+			}
+			s.redisClient.GetClient().Del(jobCtx, lockKey)
 			continue
 		}
 
 		// Mark job as running
-		job.Status = command.Running
-		if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
-			s.redisClient.GetClient().Del(ctx, lockKey) // Release lock if update fails
+		job.Start()
+		if err := job.StoreInRedis(jobCtx, s.redisClient.GetClient()); err != nil {
+			s.redisClient.GetClient().Del(jobCtx, lockKey) // Release lock if update fails
 			continue
 		}
 
-		s.logger.Info("Starting job execution", "job_id", job.ID)
+		job.Logger(s.logger).Log(s.successLogLevel(job.CommandID), "Starting job execution")
+		s.jobStarted(&job)
 
-		// Simulate job execution with sleep
-		time.Sleep(5 * time.Second)
+		// Starting beyond the tolerance points at a backlog rather than the usual ticker lag
+		if job.IsOverdue(s.config.OverdueTolerance) {
+			metrics.JobsOverdue.Inc(job.CommandID)
+			job.Logger(s.logger).Warn("Job started overdue", "scheduled_at", job.ScheduledAt,
+				"lateness", job.Age().Round(time.Millisecond), "tolerance", s.config.OverdueTolerance)
+		}
+
+		// Run the command, a failed run is recorded on the job and in the pod's health
+		err = s.executeJob(jobCtx, &job)
+		leader.RecordJobOutcome(err != nil)
+		if err != nil {
+			job.Fail(err)
+			if err := s.scheduleNextAfterRun(jobCtx, &job); err != nil {
+				job.Logger(s.logger).Error("Failed to schedule next run", "error", err)
+			}
+			if err := s.recordFailedPod(jobCtx, job.CommandID, job.AssignedTo); err != nil {
+				job.Logger(s.logger).Error("Failed to record failed pod", "error", err)
+			}
+			if err := s.storeFinishedJob(jobCtx, &job); err != nil {
+				job.Logger(s.logger).Error("Failed to store failed job", "error", err)
+			}
+			job.Logger(s.logger).Error("Job execution failed", "error", job.Error)
+			s.jobFinished(jobCtx, &job)
+			if err := s.enqueueFollowUps(jobCtx, &job); err != nil {
+				job.Logger(s.logger).Error("Failed to enqueue follow-up jobs", "error", err)
+			}
+			s.redisClient.GetClient().Del(jobCtx, lockKey)
+			continue
+		}
 
 		// Mark job as completed
-		job.Status = command.Success
-		if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
-			s.redisClient.GetClient().Del(ctx, lockKey) // Release lock if update fails
+		job.Complete()
+		if err := s.scheduleNextAfterRun(jobCtx, &job); err != nil {
+			job.Logger(s.logger).Error("Failed to schedule next run", "error", err)
+		}
+		if err := s.storeFinishedJob(jobCtx, &job); err != nil {
+			s.redisClient.GetClient().Del(jobCtx, lockKey) // Release lock if update fails
 			continue
 		}
 
-		s.logger.Info("Completed job execution", "job_id", job.ID)
+		job.Logger(s.logger).Log(s.successLogLevel(job.CommandID), "Completed job execution")
+		s.jobFinished(jobCtx, &job)
+
+		if err := s.clearFailedPod(jobCtx, job.CommandID); err != nil {
+			job.Logger(s.logger).Error("Failed to clear failed pod", "error", err)
+		}
+
+		if err := s.notifyOnChange(jobCtx, &job); err != nil {
+			job.Logger(s.logger).Error("Failed to check output change", "error", err)
+		}
+
+		if err := s.enqueueFollowUps(jobCtx, &job); err != nil {
+			job.Logger(s.logger).Error("Failed to enqueue follow-up jobs", "error", err)
+		}
 
 		// Release the lock after successful completion
-		s.redisClient.GetClient().Del(ctx, lockKey)
+		s.redisClient.GetClient().Del(jobCtx, lockKey)
 	}
 
 	return nil
 }
 
+// applyBackpressure reports whether enqueuing should be paused because executions fall behind.
+// Enqueuing pauses once the job set, which holds the scheduled, assigned and running jobs,
+// reaches the high-water mark and resumes only after it drains below the low-water mark.
+// The set is counted with ZCARD, it runs on every scheduling pass.
+func (s *Scheduler) applyBackpressure(ctx context.Context) (bool, error) {
+	high := s.config.SchedulingHighWaterMark
+	if high <= 0 {
+		return false, nil
+	}
+	low := s.config.SchedulingLowWaterMark
+	if low <= 0 || low >= high {
+		low = high / 2
+	}
+
+	queued, err := s.redisClient.GetClient().ZCard(ctx, command.JobsSortedSetKey).Result()
+	if err != nil {
+		return s.backpressure.Load(), fmt.Errorf("failed to count queued jobs: %w", err)
+	}
+	pending := int(queued)
+
+	switch {
+	case pending >= high && !s.backpressure.Load():
+		s.backpressure.Store(true)
+		s.logger.Warn("Pending jobs above high-water mark, pausing scheduling", "pending", pending, "high_water_mark", high)
+	case pending < low && s.backpressure.Load():
+		s.backpressure.Store(false)
+		s.logger.Info("Pending jobs below low-water mark, resuming scheduling", "pending", pending, "low_water_mark", low)
+	case s.backpressure.Load():
+		s.logger.Warn("Scheduling paused by backpressure", "pending", pending, "low_water_mark", low)
+	}
+
+	return s.backpressure.Load(), nil
+}
+
+// successLogLevel returns the level routine successes of a command are logged at.
+// Defaults to info, failures are always logged at error regardless of this level.
+func (s *Scheduler) successLogLevel(commandID string) zapcore.Level {
+	override, ok := s.config.CommandLogLevels[commandID]
+	if !ok {
+		return zapcore.InfoLevel
+	}
+	level, err := zapcore.ParseLevel(override)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return level
+}
+
 // getNextExecutionTimesInWindow calculates the next execution times for a command within a time window
 func (s *Scheduler) getNextExecutionTimesInWindow(cmd command.Command, start, end time.Time) ([]time.Time, error) {
-	nextTimes := make([]time.Time, 0)
-	currentTime := start
-
 	// Get the cron schedule for this command
 	schedule, _, err := cmd.Schedule()
 	if err != nil {
@@ -232,35 +660,117 @@ func (s *Scheduler) getNextExecutionTimesInWindow(cmd command.Command, start, en
 		return nil, fmt.Errorf("failed to parse cron expression %s: %w", schedule, err)
 	}
 
-	// Find all execution times within the window
-	for currentTime.Before(end) {
-		nextTime := expr.Next(currentTime)
-		if nextTime.IsZero() || nextTime.After(end) {
-			break // No more future executions in the window
+	return occurrencesInWindow(expr, start, end), nil
+}
+
+// lookahead returns how far ahead jobs are materialized
+func (s *Scheduler) lookahead() time.Duration {
+	if s.config.SchedulingLookahead > 0 {
+		return s.config.SchedulingLookahead
+	}
+	return SchedulingWindow
+}
+
+// limitOccurrences keeps only the first limit occurrences, limit <= 0 keeps all of them
+func limitOccurrences(occurrences []time.Time, limit int) []time.Time {
+	if limit > 0 && len(occurrences) > limit {
+		return occurrences[:limit]
+	}
+	return occurrences
+}
+
+// occurrencesInWindow returns all times the schedule fires in the window [start, end).
+// An occurrence exactly at start is included, one exactly at end is not. Iteration
+// stops when the schedule yields a zero time, stops advancing, or after
+// maxOccurrencesPerWindow occurrences.
+func occurrencesInWindow(schedule cron.Schedule, start, end time.Time) []time.Time {
+	occurrences := make([]time.Time, 0)
+
+	// Next returns times strictly after its argument, step back so start itself can match
+	next := schedule.Next(start.Add(-time.Nanosecond))
+	for !next.IsZero() && next.Before(end) && len(occurrences) < maxOccurrencesPerWindow {
+		occurrences = append(occurrences, next)
+		following := schedule.Next(next)
+		if !following.After(next) {
+			break
 		}
-		nextTimes = append(nextTimes, nextTime)
-		currentTime = nextTime.Add(time.Second) // Move to next second to avoid duplicates
+		next = following
 	}
 
-	return nextTimes, nil
+	return occurrences
 }
 
 // AssignJobs assigns unassigned jobs to available pods in a round-robin fashion
 func (s *Scheduler) AssignJobs(ctx context.Context, pods []string) error {
+	return s.assignJobs(ctx, pods, pods)
+}
+
+// assignJobs assigns unassigned jobs to pods. Jobs held by pods missing from alive are
+// released first, alive also holds live pods that get no new jobs, e.g. pods at capacity.
+func (s *Scheduler) assignJobs(ctx context.Context, pods, alive []string) error {
 	if len(pods) == 0 {
 		return fmt.Errorf("no pods available for job assignment")
 	}
 
+	ctx, span := telemetry.Tracer().Start(ctx, "AssignJobs", trace.WithAttributes(attribute.Int("schedulerx.pods", len(pods))))
+	defer span.End()
+
+	pending, err := s.collectPendingJobs(ctx, alive, false)
+	if err != nil {
+		return err
+	}
+
+	assignments, err := s.planAssignments(ctx, pending, pods)
+	if err != nil {
+		return err
+	}
+
+	// The next round continues the rotation after the pod that got the last job
+	lastPod := ""
+	defer func() { s.advanceAssignmentCursor(ctx, lastPod) }()
+
+	for _, job := range pending {
+		podID, ok := assignments[job.ID]
+		if !ok {
+			continue
+		}
+		job.AssignedTo = podID
+		job.Status = command.Assigned
+
+		// Store updated job in Redis
+		if err := s.storeJobFenced(ctx, job); err != nil {
+			if errors.Is(err, command.ErrStaleFencingToken) {
+				return err
+			}
+			continue
+		}
+		lastPod = podID
+
+		job.Logger(s.logger).Info("Assigned job to pod")
+	}
+
+	return nil
+}
+
+// collectPendingJobs returns the due and near-due jobs that need a pod, releasing
+// those held by dead pods. With dryRun set nothing is written to Redis, jobs of
+// dead pods are only released in memory and corrupt jobs are skipped.
+func (s *Scheduler) collectPendingJobs(ctx context.Context, pods []string, dryRun bool) ([]*command.Job, error) {
 	// Get the number of jobs to assign from config
 	jobCount := s.config.NextJobCount
 	if jobCount <= 0 {
 		jobCount = 3 // Default value if not set
 	}
 
-	// Get unassigned jobs from Redis sorted set
-	jobs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, int64(jobCount)-1).Result()
+	// Get due and near-due jobs from Redis sorted set, far-future jobs wait for a later round
+	horizon := time.Now().Add(s.config.AssignmentHorizon)
+	jobs, err := s.redisClient.GetClient().ZRangeByScore(ctx, command.JobsSortedSetKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(horizon.UnixMilli(), 10),
+		Count: int64(jobCount),
+	}).Result()
 	if err != nil {
-		return fmt.Errorf("failed to fetch jobs: %w", err)
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
 	}
 
 	// Create a set of alive pods for quick lookup
@@ -269,11 +779,9 @@ func (s *Scheduler) AssignJobs(ctx context.Context, pods []string) error {
 		alivePods[podID] = true
 	}
 
-	// Round-robin assignment
-	for i, jobID := range jobs {
-		podIndex := i % len(pods)
-		podID := pods[podIndex]
-
+	// Collect jobs that need a pod, releasing those held by dead pods
+	pending := make([]*command.Job, 0, len(jobs))
+	for _, jobID := range jobs {
 		// Get job details
 		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
 		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
@@ -283,6 +791,9 @@ func (s *Scheduler) AssignJobs(ctx context.Context, pods []string) error {
 
 		var job command.Job
 		if err := json.Unmarshal(jobData, &job); err != nil {
+			if !dryRun {
+				s.discardCorruptJob(ctx, jobID, jobData, err)
+			}
 			continue
 		}
 
@@ -291,34 +802,210 @@ func (s *Scheduler) AssignJobs(ctx context.Context, pods []string) error {
 			continue
 		}
 
-		// Handle job assignment
+		// Handle job assignment, if assigned to a dead pod unassign it first
 		if job.AssignedTo != "" {
 			if alivePods[job.AssignedTo] {
 				continue
 			}
-			// If assigned to a dead pod, unassign it first
-			oldPodID := job.AssignedTo
-			job.AssignedTo = ""
-			job.Status = command.Scheduled
-			if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
+			released, err := s.releaseFromDeadPod(ctx, &job, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			if !released {
 				continue
 			}
-			s.logger.Info("Unassigned job from dead pod", "job_id", job.ID, "pod_id", oldPodID)
 		}
+		pending = append(pending, &job)
+	}
 
-		// Update job with pod assignment
-		job.AssignedTo = podID
-		job.Status = command.Assigned
+	return pending, nil
+}
 
-		// Store updated job in Redis
-		if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
+// ReleaseJobsOfDeadPods returns the jobs of pods missing from alivePods to the pool,
+// including pods already removed from the registry, and reports how many it released.
+// Running jobs and jobs whose execution lock is still held are left alone. Writes are
+// fenced, so a pod that is no longer leader gets command.ErrStaleFencingToken.
+func (s *Scheduler) ReleaseJobsOfDeadPods(ctx context.Context, alivePods map[string]bool) (int, error) {
+	jobs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	released := 0
+	for _, jobID := range jobs {
+		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
+		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
+		if err != nil {
 			continue
 		}
 
-		s.logger.Info("Assigned job to pod", "job_id", job.ID, "pod_id", podID)
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
+			continue
+		}
+
+		if job.AssignedTo == "" || job.Status == command.Running || alivePods[job.AssignedTo] {
+			continue
+		}
+		ok, err := s.releaseFromDeadPod(ctx, &job, false)
+		if err != nil {
+			return released, err
+		}
+		if ok {
+			released++
+		}
 	}
 
-	return nil
+	return released, nil
+}
+
+// releaseFromDeadPod unassigns a job held by a dead pod and reports whether it did. A pod
+// that missed heartbeats may still be executing the job, so it is left alone while its
+// execution lock is held to avoid running it twice. With dryRun set the job is only
+// released in memory.
+func (s *Scheduler) releaseFromDeadPod(ctx context.Context, job *command.Job, dryRun bool) (bool, error) {
+	locked, err := s.redisClient.GetClient().Exists(ctx, fmt.Sprintf(jobLockKey, job.ID)).Result()
+	if err != nil {
+		job.Logger(s.logger).Error("Failed to check job lock", "error", err)
+		return false, nil
+	}
+	if locked > 0 {
+		if !dryRun {
+			job.Logger(s.logger).Warn("Job assigned to dead pod still holds its execution lock, not reassigning")
+		}
+		return false, nil
+	}
+
+	oldPodID := job.AssignedTo
+	job.AssignedTo = ""
+	job.Status = command.Scheduled
+	if dryRun {
+		return true, nil
+	}
+	if err := s.storeJobFenced(ctx, job); err != nil {
+		if errors.Is(err, command.ErrStaleFencingToken) {
+			return false, err
+		}
+		return false, nil
+	}
+	job.Logger(s.logger).Info("Unassigned job from dead pod", "previous_pod_id", oldPodID)
+	return true, nil
+}
+
+// planAssignments picks a pod for each pending job with the assignment strategy,
+// steering away from the pod a command last failed on and honouring pinned pods and leader-only commands.
+// The strategy sees the pods rotated by the assignment cursor, which is only read here,
+// and weighted by their health. Jobs placed on a pod lacking the capabilities their
+// command requires move to a capable pod, in turns, and no pod gets more jobs than its
+// remaining capacity. Jobs missing from the result stay unassigned.
+func (s *Scheduler) planAssignments(ctx context.Context, pending []*command.Job, pods []string) (map[string]string, error) {
+	weighted := s.weightedPods(ctx, s.rotatedPods(ctx, pods))
+	assignments, err := s.strategy.Assign(ctx, pending, weighted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute assignments: %w", err)
+	}
+
+	capable, err := s.capabilityFilter(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read pod capabilities, assigning to any pod", "error", err)
+	}
+	remaining, err := s.remainingCapacity(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read pod capacity, assigning without limits", "error", err)
+	}
+	turns := make(map[string]int) // Next capable pod per set of required capabilities
+
+	planned := make(map[string]string, len(assignments))
+	for _, job := range pending {
+		podID, ok := assignments[job.ID]
+		if !ok {
+			continue
+		}
+
+		// Leader-only commands run on the leader, which is the pod planning the assignments
+		if s.leaderOnly(job.CommandID) {
+			if leaderPod := leader.GetPodID(); slices.Contains(pods, leaderPod) {
+				if takeCapacity(remaining, leaderPod) {
+					planned[job.ID] = leaderPod
+				}
+			} else {
+				job.Logger(s.logger).Debug("Leader unavailable for leader-only command, leaving job unassigned")
+			}
+			continue
+		}
+
+		// Prefer a pod other than the one the command last failed on
+		podID = s.avoidFailedPod(ctx, job.CommandID, pods, podID)
+
+		required := s.requiredCapabilities(job.CommandID)
+
+		// Pinned jobs bypass round-robin, and wait for their pod unless PinnedPodFallback is set
+		if job.PinnedPod != "" {
+			if slices.Contains(pods, job.PinnedPod) && capable(job.PinnedPod, required) {
+				podID = job.PinnedPod
+			} else if !s.config.PinnedPodFallback {
+				job.Logger(s.logger).Debug("Pinned pod unavailable, leaving job unassigned", "pinned_pod", job.PinnedPod)
+				continue
+			}
+		}
+
+		if !capable(podID, required) {
+			candidates := slices.DeleteFunc(slices.Clone(weighted), func(candidate string) bool {
+				return !capable(candidate, required)
+			})
+			if len(candidates) == 0 {
+				job.Logger(s.logger).Warn("No pod has the capabilities the command requires, leaving job unassigned", "capabilities", required)
+				continue
+			}
+			key := strings.Join(required, ",")
+			podID = candidates[turns[key]%len(candidates)]
+			turns[key]++
+		}
+
+		// Jobs beyond the pod's remaining capacity wait for a later round, which skips full pods
+		if !takeCapacity(remaining, podID) {
+			job.Logger(s.logger).Debug("Pod at capacity, leaving job unassigned", "pod_id", podID)
+			continue
+		}
+		planned[job.ID] = podID
+	}
+
+	return planned, nil
+}
+
+// takeCapacity reserves a slot on the pod, reporting false when it has no capacity left.
+// Pods missing from remaining are unlimited.
+func takeCapacity(remaining map[string]int, podID string) bool {
+	left, limited := remaining[podID]
+	if !limited {
+		return true
+	}
+	if left <= 0 {
+		return false
+	}
+	remaining[podID] = left - 1
+	return true
+}
+
+// storeJobFenced stores the job only if this pod still holds the newest leader fencing token
+func (s *Scheduler) storeJobFenced(ctx context.Context, job *command.Job) error {
+	return job.StoreInRedisFenced(ctx, s.redisClient.GetClient(), leader.FencingTokenKey, leader.FencingToken())
+}
+
+// createJobFenced creates the job only if this pod still holds the newest leader fencing token
+// and the job does not exist yet, reporting whether it was created
+func (s *Scheduler) createJobFenced(ctx context.Context, job *command.Job) (bool, error) {
+	return job.CreateInRedisFenced(ctx, s.redisClient.GetClient(), leader.FencingTokenKey, leader.FencingToken())
+}
+
+// jobExists reports whether details are stored for the job, whether it is pending or finished
+func (s *Scheduler) jobExists(ctx context.Context, jobID string) (bool, error) {
+	exists, err := s.redisClient.GetClient().Exists(ctx, fmt.Sprintf(command.JobDetailsKey, jobID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check job %s: %w", jobID, err)
+	}
+	return exists > 0, nil
 }
 
 // UnassignJobsFromPod marks all jobs assigned to a specific pod as unassigned
@@ -338,6 +1025,7 @@ func (s *Scheduler) UnassignJobsFromPod(ctx context.Context, podID string) error
 
 		var job command.Job
 		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
 			continue
 		}
 
@@ -347,12 +1035,15 @@ func (s *Scheduler) UnassignJobsFromPod(ctx context.Context, podID string) error
 			job.Status = command.Scheduled
 
 			// Store updated job in Redis
-			if err := job.StoreInRedis(ctx, s.redisClient.GetClient()); err != nil {
-				s.logger.Error("Failed to unassign job", "job_id", job.ID, "pod_id", podID, "error", err)
+			if err := s.storeJobFenced(ctx, &job); err != nil {
+				job.Logger(s.logger).Error("Failed to unassign job", "previous_pod_id", podID, "error", err)
+				if errors.Is(err, command.ErrStaleFencingToken) {
+					return err
+				}
 				continue
 			}
 
-			s.logger.Info("Unassigned job from pod", "job_id", job.ID, "pod_id", podID)
+			job.Logger(s.logger).Info("Unassigned job from pod", "previous_pod_id", podID)
 		}
 	}
 
@@ -0,0 +1,850 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestExecuteAssignedJobsWaitsUntilDue(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	runs := 0
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		runs++
+		return "done", nil
+	}))
+
+	future := command.NewJob("work", nil, time.Now().Add(30*time.Second))
+	future.AssignedTo = testPodID
+	future.Status = command.Assigned
+	storeJob(t, future)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 0 {
+		t.Fatalf("job due in 30s ran %d times", runs)
+	}
+	stored := loadJob(t, future.ID)
+	if stored.Status != command.Assigned || stored.StartedAt != nil {
+		t.Fatalf("job due in 30s was started: status %s", stored.Status)
+	}
+	if testRedis.Exists("schedulerx:job_lock:" + future.ID) {
+		t.Fatal("lock of a job that is not due was not released")
+	}
+
+	due := command.NewJob("work", nil, time.Now().Add(-time.Second))
+	due.AssignedTo = testPodID
+	due.Status = command.Assigned
+	storeJob(t, due)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Fatalf("due job ran %d times, want 1", runs)
+	}
+	if stored := loadJob(t, due.ID); stored.Status != command.Success {
+		t.Fatalf("due job has status %s, want %s", stored.Status, command.Success)
+	}
+}
+
+func TestScheduleJobsKeepsStoredOccurrences(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ids := jobSet(t)
+	if len(ids) == 0 {
+		t.Fatal("no occurrences were scheduled")
+	}
+
+	// An occurrence that already ran leaves the job set but must not come back
+	finished := loadJob(t, ids[0])
+	finished.Start()
+	finished.Complete()
+	if err := finished.UpdateInRedis(ctx, testClient.GetClient()); err != nil {
+		t.Fatal(err)
+	}
+	assigned := loadJob(t, ids[1])
+	assigned.AssignedTo = testPodID
+	assigned.Status = command.Assigned
+	storeJob(t, assigned)
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(jobSet(t), finished.ID) {
+		t.Fatal("finished occurrence was scheduled again")
+	}
+	if stored := loadJob(t, finished.ID); stored.Status != command.Success {
+		t.Fatalf("finished occurrence was overwritten with status %s", stored.Status)
+	}
+	if stored := loadJob(t, assigned.ID); stored.Status != command.Assigned || stored.AssignedTo != testPodID {
+		t.Fatalf("assigned occurrence was overwritten with status %s on %q", stored.Status, stored.AssignedTo)
+	}
+}
+
+func TestScheduleJobsCountsEachOccurrenceOnce(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("count"))
+
+	before := metrics.JobsEnqueued.Value("count")
+	for range 3 {
+		if err := s.ScheduleJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	enqueued := metrics.JobsEnqueued.Value("count") - before
+	if want := float64(len(jobSet(t))); enqueued != want {
+		t.Fatalf("3 passes counted %v enqueued jobs for %v occurrences", enqueued, want)
+	}
+}
+
+func TestExecuteAssignedJobsRunsOnFollower(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	setTestPods(t,
+		leader.PodInfo{ID: "leader-pod", StartTime: time.Now().Add(-time.Hour)},
+		leader.PodInfo{ID: testPodID},
+	)
+	runs := 0
+	s.RegisterCommand(funcCommand("work", func(ctx context.Context, params []string) (string, error) {
+		runs++
+		return "done", nil
+	}))
+
+	mine := command.NewJob("work", nil, testNow().Add(-time.Second))
+	mine.AssignedTo, mine.Status = testPodID, command.Assigned
+	storeJob(t, mine)
+	leaders := command.NewJob("work", nil, testNow().Add(-2*time.Second))
+	leaders.AssignedTo, leaders.Status = "leader-pod", command.Assigned
+	storeJob(t, leaders)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Fatalf("%d jobs ran, want only the one assigned to this pod", runs)
+	}
+	if stored := loadJob(t, mine.ID); stored.Status != command.Success {
+		t.Fatalf("job assigned to this pod has status %s, want %s", stored.Status, command.Success)
+	}
+	if stored := loadJob(t, leaders.ID); stored.Status != command.Assigned {
+		t.Fatalf("job assigned to the leader has status %s, want it left %s", stored.Status, command.Assigned)
+	}
+}
+
+func TestExecuteAssignedJobsCancelsJobsPastMaxAge(t *testing.T) {
+	config := testConfig()
+	config.MaxJobAge = time.Hour
+	s := newTestScheduler(t, config)
+	runs := countRuns(s)
+
+	old := heldJob(t, testNow().Add(-2*time.Hour), command.Assigned)
+	recent := heldJob(t, testNow().Add(-time.Minute), command.Assigned)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatalf("%d jobs ran, want only the one within the max age", *runs)
+	}
+	cancelled := loadJob(t, old.ID)
+	if cancelled.Status != command.Cancelled || cancelled.FinishedAt == nil || cancelled.Error == "" {
+		t.Fatalf("old job is %s with error %q, want it cancelled with a reason", cancelled.Status, cancelled.Error)
+	}
+	if slices.Contains(jobSet(t), old.ID) {
+		t.Fatal("cancelled job is still in the job set")
+	}
+	if stored := loadJob(t, recent.ID); stored.Status != command.Success {
+		t.Fatalf("recent job has status %s, want %s", stored.Status, command.Success)
+	}
+}
+
+func TestExecuteAssignedJobsRunsOldJobsWithoutMaxAge(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	runs := countRuns(s)
+	heldJob(t, testNow().Add(-48*time.Hour), command.Assigned)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatal("old job did not run although MAX_JOB_AGE is disabled")
+	}
+}
+
+func TestStaleLeaderSchedulesNothing(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("work"))
+	raiseFencingToken(t)
+
+	if err := s.ScheduleJobs(context.Background()); !errors.Is(err, command.ErrStaleFencingToken) {
+		t.Fatalf("scheduling with a stale token returned %v, want %v", err, command.ErrStaleFencingToken)
+	}
+	if ids := jobSet(t); len(ids) != 0 {
+		t.Fatalf("stale leader enqueued %v", ids)
+	}
+}
+
+func TestJobTransitionsAreLoggedWithJobFields(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	logs := observeLogs(s)
+	countRuns(s)
+	job := heldJob(t, testNow().Add(-time.Second), command.Assigned)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, message := range []string{"Starting job execution", "Completed job execution"} {
+		entries := logs.FilterMessage(message).All()
+		if len(entries) != 1 {
+			t.Fatalf("%q logged %d times, want once", message, len(entries))
+		}
+		fields := entries[0].ContextMap()
+		for _, key := range []string{"job_id", "command_id", "status", "pod_id", "attempt", "duration_ms"} {
+			if _, ok := fields[key]; !ok {
+				t.Errorf("%q is missing field %s", message, key)
+			}
+		}
+		if fields["job_id"] != job.ID || fields["pod_id"] != testPodID {
+			t.Errorf("%q logged job %v on %v, want %s on %s", message, fields["job_id"], fields["pod_id"], job.ID, testPodID)
+		}
+	}
+}
+
+func TestOccurrencesInWindowEdges(t *testing.T) {
+	schedule, err := NewParser().Parse("0 * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       []time.Time
+	}{
+		{"occurrence at start is included", start, start.Add(30 * time.Second), []time.Time{start}},
+		{"occurrence mid-window", start.Add(time.Second), start.Add(90 * time.Second), []time.Time{start.Add(time.Minute)}},
+		{"occurrence at end is excluded", start.Add(time.Second), start.Add(time.Minute), []time.Time{}},
+		{"start and end occurrences", start, start.Add(2 * time.Minute), []time.Time{start, start.Add(time.Minute)}},
+		{"empty window", start, start, []time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := occurrencesInWindow(schedule, tt.start, tt.end); !slices.Equal(got, tt.want) {
+				t.Fatalf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextExecutionTimesInWindowEdges(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	times, err := s.getNextExecutionTimesInWindow(everyMinute("work"), start, start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{start, start.Add(time.Minute)}; !slices.Equal(times, want) {
+		t.Fatalf("execution times = %v, want %v with the start included and the end excluded", times, want)
+	}
+}
+
+func TestCommandLogLevels(t *testing.T) {
+	config := testConfig()
+	config.CommandLogLevels = map[string]string{"chatty": "debug", "broken": "debug", "typo": "loud"}
+	s := newTestScheduler(t, config)
+	logs := observeLogs(s)
+	for _, id := range []string{"chatty", "typo", "plain"} {
+		s.RegisterCommand(funcCommand(id, func(ctx context.Context, params []string) (string, error) {
+			return "ok", nil
+		}))
+	}
+	s.RegisterCommand(funcCommand("broken", func(ctx context.Context, params []string) (string, error) {
+		return "", errors.New("boom")
+	}))
+	for _, id := range []string{"chatty", "typo", "plain", "broken"} {
+		job := command.NewJob(id, nil, testNow().Add(-time.Second))
+		job.AssignedTo, job.Status = testPodID, command.Assigned
+		storeJob(t, job)
+	}
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	levels := map[string]zapcore.Level{}
+	for _, entry := range logs.FilterMessage("Completed job execution").All() {
+		levels[entry.ContextMap()["command_id"].(string)] = entry.Level
+	}
+	want := map[string]zapcore.Level{"chatty": zapcore.DebugLevel, "typo": zapcore.InfoLevel, "plain": zapcore.InfoLevel}
+	for id, level := range want {
+		if got, ok := levels[id]; !ok || got != level {
+			t.Errorf("success of %s logged at %v, want %s", id, got, level)
+		}
+	}
+
+	failures := logs.FilterMessageSnippet("Job execution failed").All()
+	if len(failures) != 1 || failures[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("failure of the debug-level command logged as %+v, want one entry at error", failures)
+	}
+}
+
+func TestAssignJobsOnlyAssignsWithinHorizon(t *testing.T) {
+	config := testConfig()
+	config.AssignmentHorizon = time.Minute
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(funcCommand("work", nil))
+	now := testNow()
+	due := command.NewJob("work", nil, now.Add(-time.Second))
+	nearDue := command.NewJob("work", nil, now.Add(30*time.Second))
+	farFuture := command.NewJob("work", nil, now.Add(10*time.Minute))
+	for _, job := range []*command.Job{due, nearDue, farFuture} {
+		storeJob(t, job)
+	}
+
+	if err := s.AssignJobs(context.Background(), []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range []*command.Job{due, nearDue} {
+		if stored := loadJob(t, job.ID); stored.AssignedTo != testPodID {
+			t.Fatalf("job due at %s was not assigned", job.ScheduledAt)
+		}
+	}
+	if stored := loadJob(t, farFuture.ID); stored.AssignedTo != "" || stored.Status != command.Scheduled {
+		t.Fatalf("job due in 10m was assigned to %q beyond the 1m horizon", stored.AssignedTo)
+	}
+}
+
+func TestScheduleJobsEnqueuesShardsPerOccurrence(t *testing.T) {
+	ctx := context.Background()
+	config := testConfig()
+	config.CommandParallelism = map[string]int{"tick": 3}
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ids := jobSet(t)
+	if len(ids) == 0 {
+		t.Fatal("no occurrences were scheduled")
+	}
+
+	shards := make(map[time.Time][]int)
+	for _, id := range ids {
+		job := loadJob(t, id)
+		if job.Shards != 3 {
+			t.Fatalf("job %s has %d shards, want 3", id, job.Shards)
+		}
+		if params := job.ExecutionParams(); !slices.Equal(params, []string{strconv.Itoa(job.Shard)}) {
+			t.Fatalf("shard %d of %s runs with params %v, want its index", job.Shard, id, params)
+		}
+		shards[job.ScheduledAt] = append(shards[job.ScheduledAt], job.Shard)
+	}
+	for scheduledAt, indices := range shards {
+		slices.Sort(indices)
+		if !slices.Equal(indices, []int{0, 1, 2}) {
+			t.Fatalf("occurrence at %s has shards %v, want 0, 1 and 2", scheduledAt, indices)
+		}
+	}
+}
+
+func TestScheduleJobsSkipIfStillRunning(t *testing.T) {
+	tests := []struct {
+		name        string
+		skip        bool
+		status      command.JobStatus
+		wantSkipped bool
+	}{
+		{"previous run still running", true, command.Running, true},
+		{"previous run still assigned", true, command.Assigned, true},
+		{"previous run finished", true, command.Success, false},
+		{"option off", false, command.Running, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			if tt.skip {
+				config.SkipIfStillRunningCommands = []string{"tick"}
+			}
+			s := newTestScheduler(t, config)
+			logs := observeLogs(s)
+			s.RegisterCommand(everyMinute("tick"))
+			previous := command.NewJob("tick", nil, testNow().Add(-time.Minute))
+			previous.AssignedTo, previous.Status = testPodID, tt.status
+			storeJob(t, previous)
+
+			if err := s.ScheduleJobs(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			enqueued := len(jobSet(t)) - 1
+			skipped := logs.FilterMessageSnippet("Skipping occurrence, previous run is still active").Len()
+			if tt.wantSkipped && (enqueued != 0 || skipped == 0) {
+				t.Fatalf("%d occurrences enqueued and %d skips logged, want every occurrence skipped with a reason", enqueued, skipped)
+			}
+			if !tt.wantSkipped && (enqueued == 0 || skipped != 0) {
+				t.Fatalf("%d occurrences enqueued and %d skipped, want them enqueued", enqueued, skipped)
+			}
+		})
+	}
+}
+
+func TestScheduleJobsLookahead(t *testing.T) {
+	tests := []struct {
+		name        string
+		lookahead   time.Duration
+		occurrences int
+		want        int
+	}{
+		{"next occurrence only", 5 * time.Minute, 1, 1},
+		{"full window", 5 * time.Minute, 0, 5},
+		{"cap above the window", 5 * time.Minute, 20, 5},
+		{"longer window", 10 * time.Minute, 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.SchedulingLookahead = tt.lookahead
+			config.SchedulingLookaheadOccurrences = tt.occurrences
+			s := newTestScheduler(t, config)
+			s.RegisterCommand(everyMinute("tick"))
+
+			if err := s.ScheduleJobs(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(jobSet(t)); got != tt.want {
+				t.Fatalf("%d occurrences enqueued, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// stoppingSchedule fires every minute until its last time, then returns the zero time
+type stoppingSchedule time.Time
+
+func (s stoppingSchedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	if next.After(time.Time(s)) {
+		return time.Time{}
+	}
+	return next
+}
+
+// stuckSchedule always returns the same time, never advancing
+type stuckSchedule time.Time
+
+func (s stuckSchedule) Next(time.Time) time.Time { return time.Time(s) }
+
+// denseSchedule fires every nanosecond
+type denseSchedule struct{}
+
+func (denseSchedule) Next(t time.Time) time.Time { return t.Add(time.Nanosecond) }
+
+func TestOccurrencesInWindowDegenerateSchedules(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	if got := occurrencesInWindow(stoppingSchedule(start.Add(130*time.Second)), start, end); len(got) != 2 {
+		t.Fatalf("schedule stopping after 2 occurrences yielded %v", got)
+	}
+	if got := occurrencesInWindow(stuckSchedule(start.Add(time.Minute)), start, end); len(got) != 1 {
+		t.Fatalf("schedule that stops advancing yielded %d occurrences, want 1", len(got))
+	}
+	if got := occurrencesInWindow(denseSchedule{}, start, end); len(got) != maxOccurrencesPerWindow {
+		t.Fatalf("dense schedule yielded %d occurrences, want the bound of %d", len(got), maxOccurrencesPerWindow)
+	}
+}
+
+func TestScheduleJobsSkipsScheduleThatNeverMatches(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	logs := observeLogs(s)
+	// February 30th never comes
+	s.RegisterCommand(command.NewFuncCommand("never", "test command", "0 0 0 30 2 *", nil))
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range jobSet(t) {
+		if job := loadJob(t, id); job.CommandID != "tick" {
+			t.Fatalf("enqueued %s for a schedule that never matches", id)
+		}
+	}
+	if len(jobSet(t)) == 0 {
+		t.Fatal("a schedule that never matches stopped other commands from being scheduled")
+	}
+	if logs.FilterMessageSnippet("Schedule yields no future occurrences").Len() != 1 {
+		t.Fatal("schedule without future occurrences was not logged")
+	}
+}
+
+func TestScheduleJobsCountsEnqueuedPerCommand(t *testing.T) {
+	config := testConfig()
+	config.SchedulingLookahead = 5 * time.Minute
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(everyMinute("per-minute"))
+	s.RegisterCommand(command.NewFuncCommand("half-minute", "test command", "@every 30s", nil))
+
+	before := map[string]float64{
+		"per-minute":  metrics.JobsEnqueued.Value("per-minute"),
+		"half-minute": metrics.JobsEnqueued.Value("half-minute"),
+	}
+	if err := s.ScheduleJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]float64{"per-minute": 5, "half-minute": 10} {
+		if got := metrics.JobsEnqueued.Value(id) - before[id]; got != want {
+			t.Fatalf("%s counted %v enqueued jobs, want its %v occurrences in the window", id, got, want)
+		}
+	}
+}
+
+func TestAssignJobsLeavesLockedJobsOfDeadPods(t *testing.T) {
+	for _, locked := range []bool{true, false} {
+		s := newTestScheduler(t, testConfig())
+		setTestPods(t,
+			leader.PodInfo{ID: testPodID, IsLeader: true},
+			leader.PodInfo{ID: "worker-pod", LastSeen: time.Now().Add(-time.Hour)},
+		)
+		s.RegisterCommand(funcCommand("work", nil))
+
+		job := command.NewJob("work", nil, testNow().Add(-time.Second))
+		job.AssignedTo, job.Status = "worker-pod", command.Assigned
+		storeJob(t, job)
+		if locked {
+			// The pod missed its heartbeats but still executes the job
+			testRedis.Set("schedulerx:job_lock:"+job.ID, "worker-pod")
+		}
+
+		if err := s.AssignJobs(context.Background(), []string{testPodID}); err != nil {
+			t.Fatal(err)
+		}
+		stored := loadJob(t, job.ID)
+		if locked && stored.AssignedTo != "worker-pod" {
+			t.Fatalf("job locked by its dead pod was reassigned to %q", stored.AssignedTo)
+		}
+		if !locked && stored.AssignedTo != testPodID {
+			t.Fatalf("unlocked job of a dead pod is assigned to %q, want %s", stored.AssignedTo, testPodID)
+		}
+	}
+}
+
+func TestReleaseJobsOfDeadPodsLeavesLockedJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	now := testNow()
+	held := func(podID string, status command.JobStatus) *command.Job {
+		job := command.NewJob("work", nil, now.Add(time.Duration(len(jobSet(t)))*time.Second))
+		job.AssignedTo, job.Status = podID, status
+		storeJob(t, job)
+		return job
+	}
+	locked := held("dead-pod", command.Assigned)
+	// The pod missed its heartbeats but still executes the job
+	testRedis.Set("schedulerx:job_lock:"+locked.ID, "dead-pod")
+	unlocked := held("dead-pod", command.Assigned)
+	running := held("dead-pod", command.Running)
+	alive := held(testPodID, command.Assigned)
+
+	released, err := s.ReleaseJobsOfDeadPods(ctx, map[string]bool{testPodID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Fatalf("released %d jobs, want only the unlocked job of the dead pod", released)
+	}
+	requireUnassigned(t, unlocked.ID)
+	for _, job := range []*command.Job{locked, running, alive} {
+		if stored := loadJob(t, job.ID); stored.AssignedTo != job.AssignedTo || stored.Status != job.Status {
+			t.Fatalf("job %s is %s on %q, want it left %s on %s", job.ID, stored.Status, stored.AssignedTo, job.Status, job.AssignedTo)
+		}
+	}
+
+	// A pod that lost leadership must not write
+	stale := held("dead-pod", command.Assigned)
+	raiseFencingToken(t)
+	if _, err := s.ReleaseJobsOfDeadPods(ctx, map[string]bool{testPodID: true}); !errors.Is(err, command.ErrStaleFencingToken) {
+		t.Fatalf("release returned %v, want %v", err, command.ErrStaleFencingToken)
+	}
+	if stored := loadJob(t, stale.ID); stored.AssignedTo != "dead-pod" {
+		t.Fatalf("job moved to %q without the leader's fencing token", stored.AssignedTo)
+	}
+}
+
+func TestShellJobFailsOnTimeout(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(command.NewShellCommandWithTimeout("sleep 10", 200*time.Millisecond))
+	job := command.NewJob("shell", nil, testNow().Add(-time.Second))
+	job.AssignedTo, job.Status = testPodID, command.Assigned
+	storeJob(t, job)
+
+	if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stored := loadJob(t, job.ID); stored.Status != command.Failed || !strings.Contains(stored.Error, "timed out") {
+		t.Fatalf("job has status %s and error %q, want it failed with a timeout", stored.Status, stored.Error)
+	}
+}
+
+func TestScheduleJobsSkipsEmptyScheduleQuietly(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		logged   bool
+	}{
+		{"empty", "", false},
+		{"blank", "  ", false},
+		{"invalid", "not a cron", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, testConfig())
+			logs := observeLogs(s)
+			s.RegisterCommand(command.NewFuncCommand("manual", "test command", tt.schedule, nil))
+
+			if err := s.ScheduleJobs(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if ids := jobSet(t); len(ids) != 0 {
+				t.Fatalf("enqueued %v for schedule %q", ids, tt.schedule)
+			}
+			if logged := logs.FilterLevelExact(zapcore.ErrorLevel).Len() > 0; logged != tt.logged {
+				t.Fatalf("schedule %q logged an error: %v, want %v", tt.schedule, logged, tt.logged)
+			}
+		})
+	}
+}
+
+func TestExecuteAssignedJobsCountsLockContention(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	runs := countRuns(s)
+	job := heldJob(t, testNow(), command.Assigned)
+	testRedis.Set("schedulerx:job_lock:"+job.ID, "worker-pod")
+
+	before := metrics.JobLockContention.Value()
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 0 {
+		t.Fatal("job locked by another pod was run")
+	}
+	if got := metrics.JobLockContention.Value() - before; got != 1 {
+		t.Fatalf("lock contention counted %d times, want 1", got)
+	}
+
+	testRedis.Del("schedulerx:job_lock:" + job.ID)
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatalf("job ran %d times once its lock was free, want 1", *runs)
+	}
+	if got := metrics.JobLockContention.Value() - before; got != 1 {
+		t.Fatalf("acquired lock was counted as contention, total %d", got)
+	}
+}
+
+// Run with -race, registering used to race with the scheduling pass ranging over the commands
+func TestRegisterCommandWhileScheduling(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("tick"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 50 {
+			s.RegisterCommand(everyMinute(fmt.Sprintf("dynamic-%d", i)))
+		}
+	}()
+	for range 10 {
+		if err := s.ScheduleJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.GetCommand("tick"); !ok {
+			t.Fatal("registered command went missing")
+		}
+	}
+	<-done
+
+	if got := len(s.Commands()); got != 51 {
+		t.Fatalf("%d commands registered, want 51", got)
+	}
+}
+
+func TestScheduleJobsSchedulesRegisteredCommands(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("custom"))
+
+	if err := s.ScheduleJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ids := jobSet(t)
+	if len(ids) == 0 {
+		t.Fatal("custom command registered with the scheduler was not scheduled")
+	}
+	// Built-in commands are only scheduled once registered
+	for _, id := range ids {
+		if job := loadJob(t, id); job.CommandID != "custom" {
+			t.Fatalf("enqueued %s of unregistered command %s", id, job.CommandID)
+		}
+	}
+}
+
+func TestScheduleJobsSubSecondScheduleCreatesDistinctJobs(t *testing.T) {
+	config := testConfig()
+	config.SchedulingLookahead = 3 * time.Second
+	s := newTestScheduler(t, config)
+	s.RegisterCommand(command.NewFuncCommand("fast", "test command", "@every 500ms", nil))
+
+	if err := s.ScheduleJobs(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	members, err := testClient.GetClient().ZRangeWithScores(context.Background(), command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) < 5 {
+		t.Fatalf("enqueued %d jobs for a 500ms schedule over 3s, want one per occurrence", len(members))
+	}
+	for i, member := range members {
+		job := loadJob(t, member.Member.(string))
+		if member.Score != command.JobScore(job.ScheduledAt) {
+			t.Fatalf("job %s scored %f, want its scheduled time in milliseconds", job.ID, member.Score)
+		}
+		if i > 0 && !job.ScheduledAt.After(loadJob(t, members[i-1].Member.(string)).ScheduledAt) {
+			t.Fatalf("job %s is not ordered after the previous occurrence", job.ID)
+		}
+	}
+}
+
+func TestRegisterCommandRefusesUnsafeID(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	logs := observeLogs(s)
+
+	s.RegisterCommand(everyMinute("bad id:*"))
+	if _, ok := s.GetCommand("bad id:*"); ok {
+		t.Fatal("command with an unsafe ID was registered")
+	}
+	if logs.FilterMessageSnippet("Refusing to register command").Len() != 1 {
+		t.Fatal("refused registration was not logged")
+	}
+	if _, err := s.PinJob(context.Background(), "../"+command.JobDetailsKey, testPodID); err == nil {
+		t.Fatal("pinning a job with an unsafe ID succeeded")
+	}
+}
+
+func TestFuncCommandIsScheduledAndExecuted(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	var got []string
+	s.RegisterCommand(command.NewFuncCommand("native", "Native task", "@every 1m", func(ctx context.Context, params []string) (string, error) {
+		got = params
+		return "native output", nil
+	}, "default"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ids := jobSet(t)
+	if len(ids) == 0 {
+		t.Fatal("scheduling pass created no jobs for the function")
+	}
+	// Make the first occurrence due
+	job := loadJob(t, ids[0])
+	job.ScheduledAt = testNow().Add(-time.Second)
+	storeJob(t, job)
+
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"default"}) {
+		t.Fatalf("function ran with %q, want its default params", got)
+	}
+	if stored := loadJob(t, job.ID); stored.Status != command.Success || stored.Output != "native output" {
+		t.Fatalf("job finished as %s with output %q, want success with the function's output", stored.Status, stored.Output)
+	}
+}
+
+func TestJobsStartingBeyondToleranceAreOverdue(t *testing.T) {
+	tests := []struct {
+		name     string
+		lateness time.Duration
+		overdue  bool
+	}{
+		{"ticker lag", 5 * time.Second, false},
+		{"backlog", time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.OverdueTolerance = 15 * time.Second
+			s := newTestScheduler(t, config)
+			logs := observeLogs(s)
+			runs := countRuns(s)
+			heldJob(t, testNow().Add(-tt.lateness), command.Assigned)
+
+			before := metrics.JobsOverdue.Value("work")
+			if err := s.ExecuteAssignedJobs(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if *runs != 1 {
+				t.Fatalf("job ran %d times, want 1", *runs)
+			}
+			counted := metrics.JobsOverdue.Value("work") - before
+			logged := logs.FilterMessageSnippet("Job started overdue").Len()
+			want := 0
+			if tt.overdue {
+				want = 1
+			}
+			if counted != float64(want) || logged != want {
+				t.Fatalf("job %s late counted %v times and logged %d times as overdue, want %d", tt.lateness, counted, logged, want)
+			}
+		})
+	}
+}
+
+func TestUnhealthyPodGetsNoAssignments(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "sick-pod", Unhealthy: true})
+	now := testNow()
+	held := command.NewJob("work", nil, now.Add(-time.Minute))
+	held.AssignedTo, held.Status = "sick-pod", command.Assigned
+	storeJob(t, held)
+	for i := range 2 {
+		storeJob(t, command.NewJob("work", nil, now.Add(-time.Duration(i+1)*time.Second)))
+	}
+
+	s.assignmentPass(ctx)
+	for _, id := range jobSet(t) {
+		if podID := loadJob(t, id).AssignedTo; podID != testPodID {
+			t.Fatalf("job %s assigned to %q, want every job on the healthy %s", id, podID, testPodID)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// shutdownPollInterval is how often running jobs are checked while draining on shutdown
+const shutdownPollInterval = 500 * time.Millisecond
+
+// DrainPod waits up to grace for the pod's running jobs to finish, then unassigns
+// every job still held by the pod so that other pods can pick them up
+func (s *Scheduler) DrainPod(ctx context.Context, podID string, grace time.Duration) error {
+	deadline := time.Now().Add(grace)
+
+	for {
+		assigned, err := s.assignedJobsByPod(ctx)
+		if err != nil {
+			return err
+		}
+
+		running := make([]string, 0)
+		for _, job := range assigned[podID] {
+			if job.Status == command.Running {
+				running = append(running, job.ID)
+			}
+		}
+		if len(running) == 0 {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			s.logger.Warn("Jobs still running at shutdown deadline", "pod_id", podID, "job_ids", running)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(shutdownPollInterval):
+		}
+	}
+
+	return s.forceUnassignJobsFromPod(ctx, podID)
+}
+
+// forceUnassignJobsFromPod returns all of the pod's jobs to the pool, including running ones.
+// Only the leader may write assignments, on other pods the jobs are left for the leader
+// to unassign once the pod stops sending heartbeats.
+func (s *Scheduler) forceUnassignJobsFromPod(ctx context.Context, podID string) error {
+	assigned, err := s.assignedJobsByPod(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range assigned[podID] {
+		job.AssignedTo = ""
+		job.Status = command.Scheduled
+		if err := s.storeJobFenced(ctx, job); err != nil {
+			if errors.Is(err, command.ErrStaleFencingToken) {
+				s.logger.Info("Not the leader, leaving jobs for the leader to unassign", "pod_id", podID, "jobs", len(assigned[podID]))
+				return nil
+			}
+			job.Logger(s.logger).Error("Failed to unassign job on shutdown", "previous_pod_id", podID, "error", err)
+			continue
+		}
+		job.Logger(s.logger).Info("Unassigned job on shutdown", "previous_pod_id", podID)
+	}
+
+	return nil
+}
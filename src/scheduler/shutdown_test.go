@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// requireUnassigned fails the test unless the job was returned to the pool
+func requireUnassigned(t *testing.T, jobID string) {
+	t.Helper()
+	if job := loadJob(t, jobID); job.AssignedTo != "" || job.Status != command.Scheduled {
+		t.Fatalf("job %s is %s on %q, want it scheduled and unassigned", jobID, job.Status, job.AssignedTo)
+	}
+}
+
+func TestDrainPodWaitsForRunningJobs(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	now := testNow()
+	running := heldJob(t, now.Add(-time.Minute), command.Running)
+	waiting := heldJob(t, now.Add(time.Minute), command.Assigned)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		running.Status = command.Success
+		storeJob(t, running)
+	}()
+
+	start := time.Now()
+	if err := s.DrainPod(context.Background(), testPodID, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Fatalf("drain took %s, want it to stop once the running job finished", elapsed)
+	}
+	if job := loadJob(t, running.ID); job.Status != command.Success {
+		t.Fatalf("finished job is %s after drain, want %s", job.Status, command.Success)
+	}
+	requireUnassigned(t, waiting.ID)
+}
+
+func TestDrainPodUnassignsRunningJobsAtDeadline(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	running := heldJob(t, testNow().Add(-time.Minute), command.Running)
+
+	start := time.Now()
+	if err := s.DrainPod(context.Background(), testPodID, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("drain returned after %s, before the grace period ended", elapsed)
+	}
+	requireUnassigned(t, running.ID)
+}
+
+func TestDrainPodLeavesJobsWhenNotLeader(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	job := heldJob(t, testNow().Add(time.Minute), command.Assigned)
+	raiseFencingToken(t)
+
+	if err := s.DrainPod(context.Background(), testPodID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadJob(t, job.ID); got.AssignedTo != testPodID {
+		t.Fatalf("job moved to %q without the leader's fencing token", got.AssignedTo)
+	}
+}
+
+func TestUnassignJobsFromPodIsFenced(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	job := heldJob(t, testNow().Add(time.Minute), command.Assigned)
+	raiseFencingToken(t)
+
+	if err := s.UnassignJobsFromPod(context.Background(), testPodID); !errors.Is(err, command.ErrStaleFencingToken) {
+		t.Fatalf("unassign returned %v, want %v", err, command.ErrStaleFencingToken)
+	}
+	if got := loadJob(t, job.ID); got.AssignedTo != testPodID {
+		t.Fatalf("job moved to %q without the leader's fencing token", got.AssignedTo)
+	}
+}
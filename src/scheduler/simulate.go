@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// SimulateAssignment returns the pod AssignJobs would assign each currently
+// due job to, keyed by job ID, without writing anything to Redis
+func (s *Scheduler) SimulateAssignment(ctx context.Context, pods []string) (map[string]string, error) {
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods available for job assignment")
+	}
+
+	pending, err := s.collectPendingJobs(ctx, pods, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.planAssignments(ctx, pending, pods)
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestSimulateAssignmentMatchesAssignJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(funcCommand("work", nil))
+	pods := []string{testPodID, "worker-pod"}
+	setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "worker-pod"})
+
+	now := testNow()
+	for i := range 4 {
+		storeJob(t, command.NewJob("work", nil, now.Add(-time.Duration(i)*time.Second)))
+	}
+	// Held by a pod that is gone, released and reassigned
+	orphaned := command.NewJob("work", nil, now.Add(-time.Minute))
+	orphaned.AssignedTo, orphaned.Status = "gone-pod", command.Assigned
+	storeJob(t, orphaned)
+
+	before := testRedis.Dump()
+	simulated, err := s.SimulateAssignment(ctx, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testRedis.Dump() != before {
+		t.Fatal("simulating assignment changed Redis")
+	}
+	if len(simulated) != 5 || simulated[orphaned.ID] == "" {
+		t.Fatalf("simulated %v, want all 5 due jobs including the orphaned one", simulated)
+	}
+
+	if err := s.AssignJobs(ctx, pods); err != nil {
+		t.Fatal(err)
+	}
+	assigned := make(map[string]string, len(simulated))
+	for _, jobID := range jobSet(t) {
+		assigned[jobID] = loadJob(t, jobID).AssignedTo
+	}
+	if !maps.Equal(simulated, assigned) {
+		t.Fatalf("simulated %v, AssignJobs assigned %v", simulated, assigned)
+	}
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// Snapshot is a point-in-time copy of the scheduler state in Redis, for backup and migration
+type Snapshot struct {
+	ExportedAt   time.Time                 `json:"exported_at"`
+	Jobs         []*command.Job            `json:"jobs"`          // Jobs in the job sorted set
+	Completed    []*command.Job            `json:"completed"`     // Retained finished jobs, see COMPLETED_JOB_RETENTION
+	Pods         map[string]leader.PodInfo `json:"pods"`          // Pod registry
+	FencingToken int64                     `json:"fencing_token"` // Latest leader fencing token, informational, ImportState leaves the live token alone
+}
+
+// ImportResult summarizes what ImportState wrote
+type ImportResult struct {
+	Jobs      int `json:"jobs"`
+	Completed int `json:"completed"`
+	Pods      int `json:"pods"`
+}
+
+// ExportState reads all jobs, retained completed jobs, the pod registry and the
+// fencing token. It only reads, undecodable jobs are skipped rather than discarded.
+func (s *Scheduler) ExportState(ctx context.Context) (*Snapshot, error) {
+	client := s.redisClient.GetClient()
+	snapshot := &Snapshot{ExportedAt: time.Now()}
+
+	jobIDs, err := client.ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+	if snapshot.Jobs, err = s.loadJobs(ctx, jobIDs); err != nil {
+		return nil, err
+	}
+	if snapshot.Completed, err = s.CompletedJobs(ctx, time.Time{}, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &snapshot.Pods); err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	token, err := client.Get(ctx, leader.FencingTokenKey).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get fencing token: %w", err)
+	}
+	snapshot.FencingToken = token
+
+	return snapshot, nil
+}
+
+// ImportState writes a snapshot into Redis, keeping job statuses and scheduled
+// times. Imported pods are added to the live registry, where pods that stopped
+// sending heartbeats are cleaned up as usual. The fencing token is not imported:
+// raising it above the running leader's token would reject all of its writes
+// until leadership changes.
+func (s *Scheduler) ImportState(ctx context.Context, snapshot *Snapshot) (*ImportResult, error) {
+	client := s.redisClient.GetClient()
+	result := &ImportResult{}
+
+	for _, job := range snapshot.Jobs {
+		// Terminal jobs keep their details but do not return to the sorted set
+		store := job.StoreInRedis
+		if job.Status.IsTerminal() {
+			store = job.UpdateInRedis
+		}
+		if err := store(ctx, client); err != nil {
+			return result, fmt.Errorf("failed to import job %s: %w", job.ID, err)
+		}
+		result.Jobs++
+	}
+
+	for _, job := range snapshot.Completed {
+		if err := job.UpdateInRedis(ctx, client); err != nil {
+			return result, fmt.Errorf("failed to import completed job %s: %w", job.ID, err)
+		}
+		if err := s.retainCompleted(ctx, job); err != nil {
+			return result, err
+		}
+		result.Completed++
+	}
+
+	if len(snapshot.Pods) > 0 {
+		var pods map[string]leader.PodInfo
+		if err := s.redisClient.GetJSON(ctx, "schedulerx:pods", &pods); err != nil {
+			return result, fmt.Errorf("failed to get pods: %w", err)
+		}
+		if pods == nil {
+			pods = make(map[string]leader.PodInfo, len(snapshot.Pods))
+		}
+		for podID, info := range snapshot.Pods {
+			if _, exists := pods[podID]; exists {
+				continue
+			}
+			pods[podID] = info
+			result.Pods++
+		}
+		if err := s.redisClient.SetJSONWithExpiry(ctx, "schedulerx:pods", pods, 24*time.Hour); err != nil {
+			return result, fmt.Errorf("failed to store pods: %w", err)
+		}
+	}
+
+	s.logger.Info("Imported scheduler state",
+		"jobs", result.Jobs,
+		"completed", result.Completed,
+		"pods", result.Pods,
+		"exported_at", snapshot.ExportedAt,
+	)
+	return result, nil
+}
+
+// loadJobs returns the details of the given jobs, skipping expired and undecodable ones
+func (s *Scheduler) loadJobs(ctx context.Context, jobIDs []string) ([]*command.Job, error) {
+	client := s.redisClient.GetClient()
+
+	jobs := make([]*command.Job, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		jobData, err := client.Get(ctx, fmt.Sprintf(command.JobDetailsKey, jobID)).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.logger.Warn("Skipping undecodable job", "job_id", jobID, "error", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
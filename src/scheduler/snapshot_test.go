@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+func TestExportImportStateRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+
+	pending := command.NewJob("work", []string{"a"}, time.Now().Add(time.Minute))
+	pending.Labels = map[string]string{"team": "payments"}
+	assigned := command.NewJob("work", nil, time.Now())
+	assigned.AssignedTo = testPodID
+	assigned.Status = command.Assigned
+	storeJob(t, pending)
+	storeJob(t, assigned)
+
+	snapshot, err := s.ExportState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Jobs) != 2 || len(snapshot.Pods) != 1 {
+		t.Fatalf("exported %d jobs and %d pods, want 2 and 1", len(snapshot.Jobs), len(snapshot.Pods))
+	}
+
+	s = newTestScheduler(t, testConfig())
+	setTestPods(t, leader.PodInfo{ID: "other-pod"})
+	result, err := s.ImportState(ctx, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Jobs != 2 || result.Pods != 1 {
+		t.Fatalf("imported %+v, want 2 jobs and 1 pod", result)
+	}
+
+	if ids := jobSet(t); !slices.Contains(ids, pending.ID) || !slices.Contains(ids, assigned.ID) {
+		t.Fatalf("job set after import is %v", ids)
+	}
+	restored := loadJob(t, assigned.ID)
+	if restored.Status != command.Assigned || restored.AssignedTo != testPodID {
+		t.Fatalf("assigned job restored as %s on %q", restored.Status, restored.AssignedTo)
+	}
+	if loadJob(t, pending.ID).Labels["team"] != "payments" {
+		t.Fatal("labels were not restored")
+	}
+}
+
+func TestImportStateKeepsFencingToken(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	client := testClient.GetClient()
+	if err := client.Set(ctx, leader.FencingTokenKey, 0, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ImportState(ctx, &Snapshot{FencingToken: 99}); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := client.Get(ctx, leader.FencingTokenKey).Int64(); token != 0 {
+		t.Fatalf("fencing token is %d after import, want it unchanged at 0", token)
+	}
+
+	// The running leader still holds the newest token and can keep writing
+	if err := s.storeJobFenced(ctx, command.NewJob("work", nil, time.Now())); err != nil {
+		t.Fatalf("leader write rejected after import: %v", err)
+	}
+}
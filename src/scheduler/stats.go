@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+// countJobsByStatus returns the number of jobs in the sorted set per status
+func (s *Scheduler) countJobsByStatus(ctx context.Context) (map[command.JobStatus]int, error) {
+	jobIDs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	counts := make(map[command.JobStatus]int)
+	for _, jobID := range jobIDs {
+		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
+		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
+			continue
+		}
+		counts[job.Status]++
+	}
+
+	return counts, nil
+}
+
+// jobsForCommand returns the jobs of a command in the sorted set that have one of the given statuses
+func (s *Scheduler) jobsForCommand(ctx context.Context, commandID string, statuses ...command.JobStatus) ([]*command.Job, error) {
+	jobIDs, err := s.redisClient.GetClient().ZRange(ctx, command.JobsSortedSetKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	// Job IDs are not filtered by the command ID prefix, command IDs may contain "_"
+	// so the IDs of jobs of "du" would also match those of "du_x"
+	matched := make([]*command.Job, 0)
+	for _, jobID := range jobIDs {
+		jobKey := fmt.Sprintf(command.JobDetailsKey, jobID)
+		jobData, err := s.redisClient.GetClient().Get(ctx, jobKey).Bytes()
+		if err != nil {
+			continue
+		}
+
+		var job command.Job
+		if err := json.Unmarshal(jobData, &job); err != nil {
+			s.discardCorruptJob(ctx, jobID, jobData, err)
+			continue
+		}
+		if job.CommandID == commandID && slices.Contains(statuses, job.Status) {
+			matched = append(matched, &job)
+		}
+	}
+
+	return matched, nil
+}
+
+// previousRunActive returns the ID of an active job scheduled before at, empty if there is none
+func previousRunActive(active []*command.Job, at time.Time) string {
+	for _, job := range active {
+		if job.ScheduledAt.Before(at) {
+			return job.ID
+		}
+	}
+	return ""
+}
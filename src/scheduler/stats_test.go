@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+)
+
+func TestJobsForCommandMatchesCommandIDExactly(t *testing.T) {
+	s := newTestScheduler(t, testConfig())
+	now := testNow()
+	du := command.NewJob("du", nil, now.Add(time.Minute))
+	duX := command.NewJob("du_x", nil, now.Add(time.Minute))
+	storeJob(t, du)
+	storeJob(t, duX)
+
+	for commandID, want := range map[string]string{"du": du.ID, "du_x": duX.ID} {
+		jobs, err := s.jobsForCommand(context.Background(), commandID, command.Scheduled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != 1 || jobs[0].ID != want {
+			t.Fatalf("jobsForCommand(%s) returned %d jobs, want only %s", commandID, len(jobs), want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording every span in memory until the test ends
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// endedSpan returns the first ended span with the given name
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("no %s span was recorded", name)
+	return nil
+}
+
+func TestSpansAreRecorded(t *testing.T) {
+	ctx := context.Background()
+	recorder := recordSpans(t)
+	s := newTestScheduler(t, testConfig())
+	countRuns(s)
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.EnqueueAfter(ctx, "work", nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	for _, name := range []string{"ScheduleJobs", "EnqueueAfter", "AssignJobs", "executeJob"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("spans %q do not include %s", names, name)
+		}
+	}
+}
+
+func TestTraceContextCarriesFromCreationToExecution(t *testing.T) {
+	ctx := context.Background()
+	recorder := recordSpans(t)
+	s := newTestScheduler(t, testConfig())
+	countRuns(s)
+
+	job, err := s.EnqueueAfter(ctx, "work", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadJob(t, job.ID).TraceContext == "" {
+		t.Fatal("job was stored without a trace context")
+	}
+	// The executing pod only sees what is stored in Redis
+	if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExecuteAssignedJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	created := endedSpan(t, recorder, "EnqueueAfter").SpanContext()
+	executed := endedSpan(t, recorder, "executeJob")
+	if executed.SpanContext().TraceID() != created.TraceID() {
+		t.Fatalf("execution ran in trace %s, want the creating trace %s", executed.SpanContext().TraceID(), created.TraceID())
+	}
+	if parent := executed.Parent(); parent.SpanID() != created.SpanID() || !parent.IsRemote() {
+		t.Fatalf("execution span has parent %s, want the remote creating span %s", parent.SpanID(), created.SpanID())
+	}
+}
@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/telemetry"
+)
+
+// ErrInvalidJob is returned when a job is requested for an unknown command or with invalid params
+var ErrInvalidJob = errors.New("invalid job")
+
+// ErrJobExists is returned when jobs of the same command are already due at every millisecond an ad-hoc job could move to
+var ErrJobExists = errors.New("job already exists")
+
+// maxAdHocIDAttempts bounds how many milliseconds an ad-hoc job moves forward to get an unused ID
+const maxAdHocIDAttempts = 10
+
+// TriggerJob creates a job for the given command that is due immediately.
+// Nil params run the command with its default parameters, otherwise they are merged over them,
+// and the effective parameters are validated before the job is stored. Labels
+// are added to the command's own labels, overriding them on conflicting keys.
+// It fails with ErrQueueFull when the job set is at MAX_QUEUED_JOBS.
+func (s *Scheduler) TriggerJob(ctx context.Context, commandID string, params []string, labels map[string]string) (*command.Job, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "TriggerJob")
+	defer span.End()
+
+	job, err := s.newJob(commandID, params, labels, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueueJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Triggered job", "job_id", job.ID, "command", commandID, "params", job.Params)
+	return job, nil
+}
+
+// EnqueueAfter creates a job for the given command that is due once delay has
+// passed, e.g. 10*time.Minute to run it in ten minutes. Params are handled as in
+// TriggerJob. It fails with ErrQueueFull when the job set is at MAX_QUEUED_JOBS.
+func (s *Scheduler) EnqueueAfter(ctx context.Context, commandID string, params []string, delay time.Duration) (*command.Job, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "EnqueueAfter")
+	defer span.End()
+
+	if delay < 0 {
+		return nil, fmt.Errorf("%w: delay %s must not be negative", ErrInvalidJob, delay)
+	}
+	job, err := s.newJob(commandID, params, nil, time.Now().Add(delay))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueueJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Enqueued delayed job", "job_id", job.ID, "command", commandID, "params", job.Params,
+		"delay", delay, "scheduled_at", job.ScheduledAt)
+	return job, nil
+}
+
+// enqueueJob stamps the trace context of ctx on a job requested through the
+// API or a library call and stores it once the job set has room for it
+func (s *Scheduler) enqueueJob(ctx context.Context, job *command.Job) error {
+	job.TraceContext = telemetry.Inject(ctx)
+	if err := s.admitJob(ctx, job); err != nil {
+		return err
+	}
+	if err := s.createAdHocJob(ctx, job); err != nil {
+		return err
+	}
+
+	s.jobScheduled(job)
+	return nil
+}
+
+// createAdHocJob stores a job requested outside the schedule. Job IDs have millisecond
+// precision, so a job of the same command may already be due at the same time, e.g. when
+// triggered twice in a row; the job then moves to the next free millisecond.
+func (s *Scheduler) createAdHocJob(ctx context.Context, job *command.Job) error {
+	for attempt := 1; ; attempt++ {
+		created, err := job.CreateInRedis(ctx, s.redisClient.GetClient())
+		if err != nil {
+			return fmt.Errorf("failed to store job %s: %w", job.ID, err)
+		}
+		if created {
+			return nil
+		}
+		if attempt >= maxAdHocIDAttempts {
+			return fmt.Errorf("%w: %s", ErrJobExists, job.ID)
+		}
+		job.Reschedule(job.ScheduledAt.Add(time.Millisecond))
+	}
+}
+
+// newImmediateJob builds a validated job for the given command that is due now, without storing it
+func (s *Scheduler) newImmediateJob(commandID string, params []string, labels map[string]string) (*command.Job, error) {
+	return s.newJob(commandID, params, labels, time.Now())
+}
+
+// newJob builds a validated job for the given command that is due at scheduledAt, without storing it.
+// Unknown commands and invalid params fail with ErrInvalidJob.
+func (s *Scheduler) newJob(commandID string, params []string, labels map[string]string, scheduledAt time.Time) (*command.Job, error) {
+	cmd, exists := s.GetCommand(commandID)
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown command: %s", ErrInvalidJob, commandID)
+	}
+
+	effective, err := command.CoerceCommandParams(cmd, command.MergeParams(cmd.Parameters(), params))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid params for command %s: %w", ErrInvalidJob, commandID, err)
+	}
+	if validator, ok := cmd.(command.Validator); ok {
+		if err := validator.Validate(effective); err != nil {
+			return nil, fmt.Errorf("%w: invalid params for command %s: %w", ErrInvalidJob, commandID, err)
+		}
+	}
+
+	job := command.NewJob(commandID, effective, scheduledAt)
+	job.Labels = command.MergeLabels(cmd, labels)
+	return job, nil
+}
+
+// PinJob pins a pending job to the given pod. The job is unassigned so the
+// next assignment round places it on that pod.
+func (s *Scheduler) PinJob(ctx context.Context, jobID, podID string) (*command.Job, error) {
+	if err := command.ValidateJobID(jobID); err != nil {
+		return nil, err
+	}
+
+	var job command.Job
+	if err := s.redisClient.GetJSON(ctx, fmt.Sprintf(command.JobDetailsKey, jobID), &job); err != nil {
+		return nil, err
+	}
+	if job.ID == "" {
+		return nil, fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.Status != command.Scheduled && job.Status != command.Assigned {
+		return nil, fmt.Errorf("job %s is %s and can no longer be pinned", jobID, job.Status)
+	}
+
+	job.PinnedPod = podID
+	if job.AssignedTo != podID {
+		job.AssignedTo = ""
+		job.Status = command.Scheduled
+	}
+	if err := job.UpdateInRedis(ctx, s.redisClient.GetClient()); err != nil {
+		return nil, fmt.Errorf("failed to store pinned job: %w", err)
+	}
+
+	job.Logger(s.logger).Info("Pinned job to pod", "pinned_pod", podID)
+	return &job, nil
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+)
+
+// everyMinute returns a command scheduled at the start of every minute
+func everyMinute(id string) command.Command {
+	return command.NewFuncCommand(id, "test command", "0 * * * * *", func(ctx context.Context, params []string) (string, error) {
+		return "", nil
+	})
+}
+
+func TestPinnedCronOccurrenceKeepsPin(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	s.RegisterCommand(everyMinute("tick"))
+
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ids := jobSet(t)
+	if len(ids) == 0 {
+		t.Fatal("no occurrences were scheduled")
+	}
+
+	pinned, err := s.PinJob(ctx, ids[0], "other-pod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.PinnedPod != "other-pod" {
+		t.Fatalf("PinJob returned pin %q", pinned.PinnedPod)
+	}
+
+	// The next scheduling pass sees the same occurrence again
+	if err := s.ScheduleJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored := loadJob(t, ids[0]); stored.PinnedPod != "other-pod" {
+		t.Fatalf("pin lost after rescheduling, pinned pod is %q", stored.PinnedPod)
+	}
+}
+
+func TestPinJobRejectsFinishedJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+
+	job := command.NewJob("work", nil, testNow())
+	job.Complete()
+	storeJob(t, job)
+
+	if _, err := s.PinJob(ctx, job.ID, "other-pod"); err == nil {
+		t.Fatal("pinned a finished job")
+	}
+	if _, err := s.PinJob(ctx, "missing_1", "other-pod"); err == nil {
+		t.Fatal("pinned a job that does not exist")
+	}
+}
+
+func TestAssignJobsHonoursPinnedPod(t *testing.T) {
+	tests := []struct {
+		name      string
+		pinnedPod string
+		fallback  bool
+		want      []string // Pods the job may end up on, empty when it must stay unassigned
+	}{
+		{"pinned pod alive", "worker-pod", false, []string{"worker-pod"}},
+		{"pinned pod dead with fallback", "gone-pod", true, []string{testPodID, "worker-pod"}},
+		{"pinned pod dead without fallback", "gone-pod", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.PinnedPodFallback = tt.fallback
+			s := newTestScheduler(t, config)
+			setTestPods(t, leader.PodInfo{ID: testPodID, IsLeader: true}, leader.PodInfo{ID: "worker-pod"})
+			s.RegisterCommand(funcCommand("work", nil))
+
+			// Round-robin alone would pick the test pod, sorting before worker-pod
+			job := command.NewJob("work", nil, testNow())
+			job.PinnedPod = tt.pinnedPod
+			storeJob(t, job)
+			if err := s.AssignJobs(context.Background(), []string{testPodID, "worker-pod"}); err != nil {
+				t.Fatal(err)
+			}
+
+			stored := loadJob(t, job.ID)
+			if len(tt.want) == 0 {
+				if stored.AssignedTo != "" || stored.Status != command.Scheduled {
+					t.Fatalf("job assigned to %q with status %s, want it left unassigned", stored.AssignedTo, stored.Status)
+				}
+				return
+			}
+			if !slices.Contains(tt.want, stored.AssignedTo) || stored.Status != command.Assigned {
+				t.Fatalf("job assigned to %q with status %s, want one of %v", stored.AssignedTo, stored.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueAfterRunsOnceDelayElapsed(t *testing.T) {
+	ctx := context.Background()
+	s := newTestScheduler(t, testConfig())
+	runs := countRuns(s)
+	delay := 300 * time.Millisecond
+
+	before := testNow()
+	job, err := s.EnqueueAfter(ctx, "work", nil, delay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now()
+	if at := loadJob(t, job.ID).ScheduledAt; at.Before(before.Add(delay)) || at.After(after.Add(delay)) {
+		t.Fatalf("job scheduled at %s, want %s from now", at.Format(time.StampMilli), delay)
+	}
+
+	execute := func() {
+		t.Helper()
+		if err := s.AssignJobs(ctx, []string{testPodID}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.ExecuteAssignedJobs(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	execute()
+	if *runs != 0 {
+		t.Fatal("delayed job ran before its delay elapsed")
+	}
+	time.Sleep(time.Until(job.ScheduledAt) + 10*time.Millisecond)
+	execute()
+	if *runs != 1 {
+		t.Fatalf("delayed job ran %d times once due, want 1", *runs)
+	}
+}
@@ -0,0 +1,68 @@
+package schedulerx_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/schedulerx"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+)
+
+// Embed the scheduler in a service, running a Go function every second.
+// The example runs against an in-memory Redis, pass a real one in production.
+func Example() {
+	server, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	defer server.Close()
+
+	config, _, err := utils.LoadConfig()
+	if err != nil {
+		panic(err)
+	}
+	config.HTTPPort = 0
+	config.MaintenanceSchedule = ""
+
+	app := schedulerx.New(config,
+		schedulerx.WithRedisClient(cache.NewClientFromRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))),
+		schedulerx.WithLogger(&utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}),
+	)
+
+	ran := make(chan string, 1)
+	hello := command.NewFuncCommand("hello", "Greets every second", "* * * * * *", func(ctx context.Context, params []string) (string, error) {
+		select {
+		case ran <- "hello from a scheduled job":
+		default:
+		}
+		return "", nil
+	})
+	if err := app.RegisterCommand(hello); err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run(ctx)
+	}()
+
+	select {
+	case message := <-ran:
+		fmt.Println(message)
+	case <-time.After(time.Minute):
+		fmt.Println("no job ran")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		fmt.Println(err)
+	}
+	// Output: hello from a scheduled job
+}
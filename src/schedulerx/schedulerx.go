@@ -0,0 +1,419 @@
+package schedulerx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yashkumarverma/schedulerx/src/api"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/export"
+	"github.com/yashkumarverma/schedulerx/src/leader"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/telemetry"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+)
+
+// SchedulingInterval is how often the scheduling loop runs
+const SchedulingInterval = 5 * time.Second
+
+// telemetryFlushTimeout bounds how long exporting the last spans and metrics may take on exit
+const telemetryFlushTimeout = 5 * time.Second
+
+// Option configures a Schedulerx instance
+type Option func(*Schedulerx)
+
+// WithLogger sets the logger used by all components
+func WithLogger(logger *utils.StandardLogger) Option {
+	return func(s *Schedulerx) {
+		s.logger = logger
+	}
+}
+
+// WithRedisClient uses an existing Redis client instead of connecting from config
+func WithRedisClient(client *cache.Client) Option {
+	return func(s *Schedulerx) {
+		s.redisClient = client
+	}
+}
+
+// WithStatusOutput sets where the pod status line is printed, discarded by default
+func WithStatusOutput(w io.Writer) Option {
+	return func(s *Schedulerx) {
+		s.statusOutput = w
+	}
+}
+
+// WithJobSink exports finished jobs to sink, taking precedence over EXPORT_DIR
+func WithJobSink(sink export.Sink) Option {
+	return func(s *Schedulerx) {
+		s.jobSink = sink
+	}
+}
+
+// Schedulerx wires the pod manager, scheduler and commands so the scheduler
+// can be embedded in another Go service
+type Schedulerx struct {
+	config       *utils.Config
+	logger       *utils.StandardLogger
+	redisClient  *cache.Client
+	statusOutput io.Writer
+	jobSink      export.Sink
+	observers    []scheduler.Observer
+	registry     *command.CommandRegistry
+
+	// mu guards podManager and scheduler, which Run creates while other methods may read them
+	mu         sync.RWMutex
+	podManager *leader.PodManager
+	scheduler  *scheduler.Scheduler
+}
+
+// New creates a new Schedulerx instance with the built-in commands registered
+func New(config *utils.Config, opts ...Option) *Schedulerx {
+	s := &Schedulerx{
+		config:       config,
+		statusOutput: io.Discard,
+		registry:     command.NewCommandRegistry(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.logger == nil {
+		s.logger = utils.NewLogger()
+	}
+
+	// Optional commands that depend on config
+	if config.SMTPHost != "" {
+		if err := s.registry.Register(command.NewEmailCommand(config)); err != nil {
+			s.logger.Error("Failed to register email command", "error", err)
+		}
+	}
+	if config.HealthCheckURL != "" {
+		if err := s.registry.Register(command.NewHealthCheckCommand(config.HealthCheckURL, config.HealthCheckSchedule)); err != nil {
+			s.logger.Error("Failed to register health check command", "error", err)
+		}
+	}
+	if config.WebhookURL != "" {
+		if err := s.registry.Register(command.NewWebhookCommand(config)); err != nil {
+			s.logger.Error("Failed to register webhook command", "error", err)
+		}
+	}
+
+	for _, id := range s.registry.ApplyDefaultParams(config.CommandDefaultParams) {
+		s.logger.Warn("Ignoring default params of unknown or non-overridable command", "command", id)
+	}
+	return s
+}
+
+// RegisterCommand adds a custom command, must be called before Run
+func (s *Schedulerx) RegisterCommand(cmd command.Command) error {
+	return s.registry.Register(cmd)
+}
+
+// UnregisterCommand removes a command so it is not scheduled, must be called before Run
+func (s *Schedulerx) UnregisterCommand(id string) {
+	s.registry.Unregister(id)
+}
+
+// RegisterObserver adds an observer notified of job lifecycle and leadership events, must be called before Run
+func (s *Schedulerx) RegisterObserver(observer scheduler.Observer) {
+	s.observers = append(s.observers, observer)
+}
+
+// Commands returns all commands that will be scheduled, keyed by ID
+func (s *Schedulerx) Commands() map[string]command.Command {
+	return s.registry.GetCommands()
+}
+
+// Run registers the pod and runs the scheduling loop until ctx is cancelled
+func (s *Schedulerx) Run(ctx context.Context) error {
+	if s.redisClient == nil {
+		redisClient, err := cache.NewClient(ctx, s.config)
+		if err != nil {
+			return fmt.Errorf("failed to create Redis client: %w", err)
+		}
+		s.redisClient = redisClient
+	}
+
+	// Track Redis connectivity so readiness reflects outages
+	go s.redisClient.Supervise(ctx, s.logger)
+
+	// Initialize pod manager
+	podManager := leader.NewPodManager(s.redisClient, s.logger, s.config)
+	podManager.SetOutput(s.statusOutput)
+
+	// Followers only learn about promotion through the heartbeat, so subscribe before it starts
+	var leadershipChanges chan bool
+	if s.config.LeaderOnlyScheduling {
+		leadershipChanges = make(chan bool, 1)
+		podManager.OnLeadershipChange(func(isLeader bool) {
+			// Keep only the latest state so the heartbeat never blocks
+			select {
+			case <-leadershipChanges:
+			default:
+			}
+			leadershipChanges <- isLeader
+		})
+	}
+
+	// Create scheduler instance before the heartbeat starts so leadership changes reach observers
+	sched := scheduler.NewScheduler(s.redisClient, s.logger, s.config)
+	for _, observer := range s.observers {
+		sched.RegisterObserver(observer)
+	}
+	podManager.OnLeadershipChange(sched.NotifyLeaderChanged)
+	podManager.SetDeadPodJobReleaser(sched.ReleaseJobsOfDeadPods)
+
+	// Report which binaries the commands need are present, so the leader only assigns jobs this pod can run
+	capabilities, missing := command.ProbeCapabilities(s.requiredCapabilities())
+	if len(missing) > 0 {
+		s.logger.Warn("Binaries required by commands are missing on this pod, their jobs will run on other pods", "missing", missing)
+	}
+	podManager.SetCapabilities(capabilities)
+
+	if err := podManager.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize pod manager: %w", err)
+	}
+
+	// Initialize may change the pod ID, so publish both only once it is final
+	s.mu.Lock()
+	s.podManager = podManager
+	s.scheduler = sched
+	s.mu.Unlock()
+
+	s.logger.Info("Pod manager initialized successfully", "pod_id", podManager.GetPodID())
+
+	// Push traces and metrics to an OpenTelemetry collector when one is configured
+	shutdownTelemetry, err := telemetry.Setup(ctx, s.config, podManager.GetPodID())
+	if err != nil {
+		return fmt.Errorf("failed to set up telemetry: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), telemetryFlushTimeout)
+		defer cancel()
+		if err := shutdownTelemetry(flushCtx); err != nil {
+			s.logger.Error("Failed to flush telemetry", "error", err)
+		}
+	}()
+
+	// Serve the HTTP API
+	if s.config.HTTPPort > 0 {
+		server := api.NewServer(s.redisClient, s.logger, s.config, podManager, sched)
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				s.logger.Error("API server stopped", "error", err)
+			}
+		}()
+	}
+
+	// Keep finished jobs beyond their Redis TTL
+	if s.jobSink == nil && s.config.ExportDir != "" {
+		s.jobSink = export.NewFileSink(s.config.ExportDir)
+	}
+	if s.jobSink != nil {
+		exporter := export.NewExporter(s.jobSink, s.logger, s.config.ExportQueueSize)
+		go exporter.Run(ctx)
+		sched.SetExporter(exporter)
+	}
+
+	// Register all commands with the scheduler
+	if s.config.MaintenanceSchedule != "" {
+		if err := s.registry.Register(scheduler.NewMaintenanceCommand(sched, s.config.MaintenanceSchedule)); err != nil {
+			s.logger.Error("Failed to register maintenance command", "error", err)
+		}
+	}
+	if s.config.StatusReportSchedule != "" {
+		if err := s.registry.Register(scheduler.NewStatusCommand(sched, s.config.StatusReportSchedule)); err != nil {
+			s.logger.Error("Failed to register status command", "error", err)
+		}
+	}
+	commandIDs := make([]string, 0, len(s.Commands()))
+	for cmdID, cmd := range s.Commands() {
+		sched.RegisterCommand(cmd)
+		commandIDs = append(commandIDs, cmdID)
+		s.logger.Info("Registered command with scheduler", "command", cmdID)
+	}
+	slices.Sort(commandIDs)
+
+	// Jobs scored in seconds by earlier versions would be assigned ahead of due jobs
+	if _, err := sched.MigrateJobScores(ctx); err != nil {
+		s.logger.Error("Failed to migrate job scores", "error", err)
+	}
+
+	// Clean up stale job entries once if this pod starts as leader
+	isLeader := false
+	leaderID, err := podManager.GetLeader(ctx)
+	if err != nil {
+		s.logger.Error("Failed to determine leader", "error", err)
+	} else if leaderID == podManager.GetPodID() {
+		isLeader = true
+		if _, err := sched.ReconcileJobs(ctx); err != nil {
+			s.logger.Error("Failed to reconcile jobs", "error", err)
+		}
+	}
+
+	// Startup banner with the effective configuration, secrets redacted
+	s.logger.Info("Schedulerx started",
+		"pod_id", podManager.GetPodID(),
+		"leader", isLeader,
+		"commands", commandIDs,
+		"config", s.config.Redacted(),
+	)
+
+	// Assignment and execution run on every pod
+	sched.Start(ctx)
+
+	if s.config.LeaderOnlyScheduling {
+		return s.runLeaderOnlyScheduling(ctx, sched, leadershipChanges)
+	}
+	s.runSchedulingLoop(ctx, sched)
+	return nil
+}
+
+// runSchedulingLoop calls ScheduleJobs every SchedulingInterval until ctx is cancelled.
+// On promotion it runs a pass right away, followed by an assignment pass.
+func (s *Schedulerx) runSchedulingLoop(ctx context.Context, sched *scheduler.Scheduler) {
+	ticker := time.NewTicker(SchedulingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := sched.ScheduleJobs(ctx); err != nil {
+				s.logger.Error("Failed to schedule jobs", "error", err)
+			}
+		case <-sched.ScheduleNow():
+			// Promotion, schedule and assign right away instead of waiting for the next tick
+			if err := sched.ScheduleJobs(ctx); err != nil {
+				s.logger.Error("Failed to schedule jobs", "error", err)
+			}
+			sched.RequestAssignment()
+			ticker.Reset(SchedulingInterval)
+		}
+	}
+}
+
+// runLeaderOnlyScheduling runs the scheduling loop only while this pod is leader,
+// starting and stopping it as leadership changes
+func (s *Schedulerx) runLeaderOnlyScheduling(ctx context.Context, sched *scheduler.Scheduler, changes <-chan bool) error {
+	var stop func()
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case isLeader := <-changes:
+			if isLeader && stop == nil {
+				loopCtx, cancel := context.WithCancel(ctx)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					s.runSchedulingLoop(loopCtx, sched)
+				}()
+				// Stopping waits for a pass in progress, so loops never overlap
+				stop = func() {
+					cancel()
+					<-done
+				}
+				s.logger.Info("Became leader, starting scheduling loop")
+			} else if !isLeader && stop != nil {
+				stop()
+				stop = nil
+				s.logger.Info("Lost leadership, stopping scheduling loop")
+			}
+		}
+	}
+}
+
+// running returns the pod manager and scheduler, nil until Run has created them
+func (s *Schedulerx) running() (*leader.PodManager, *scheduler.Scheduler) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.podManager, s.scheduler
+}
+
+// DrainCluster stops scheduling, assignment and job starts on every pod and waits
+// until running jobs have finished. Must be called after Run has started.
+func (s *Schedulerx) DrainCluster(ctx context.Context) error {
+	_, sched := s.running()
+	if sched == nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+	return sched.DrainCluster(ctx)
+}
+
+// UndrainCluster resumes normal operation after DrainCluster
+func (s *Schedulerx) UndrainCluster(ctx context.Context) error {
+	_, sched := s.running()
+	if sched == nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+	return sched.UndrainCluster(ctx)
+}
+
+// PausePod stops a pod from starting jobs without draining it. It keeps being assigned
+// jobs and holds them until ResumePod, running jobs finish normally. Must be called after Run has started.
+func (s *Schedulerx) PausePod(ctx context.Context, podID string) error {
+	podManager, _ := s.running()
+	if podManager == nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+	_, err := podManager.SetPodPaused(ctx, podID, true)
+	return err
+}
+
+// ResumePod lets a pod paused with PausePod start its jobs again
+func (s *Schedulerx) ResumePod(ctx context.Context, podID string) error {
+	podManager, _ := s.running()
+	if podManager == nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+	_, err := podManager.SetPodPaused(ctx, podID, false)
+	return err
+}
+
+// CancelJobsForCommand cancels the scheduled and assigned jobs of a command, e.g. when
+// decommissioning it, and returns how many were cancelled. Running jobs finish normally.
+// Unregister the command first, otherwise the next scheduling pass enqueues it again.
+func (s *Schedulerx) CancelJobsForCommand(ctx context.Context, commandID string) (int, error) {
+	_, sched := s.running()
+	if sched == nil {
+		return 0, fmt.Errorf("scheduler is not running")
+	}
+	return sched.CancelJobsForCommand(ctx, commandID)
+}
+
+// Shutdown waits up to SHUTDOWN_GRACE_SECONDS for this pod's running jobs to
+// finish and then unassigns its remaining jobs. Call it after Run's context is cancelled.
+func (s *Schedulerx) Shutdown(ctx context.Context) error {
+	podManager, sched := s.running()
+	if podManager == nil || sched == nil {
+		return nil
+	}
+
+	grace := time.Duration(s.config.ShutdownGraceSeconds) * time.Second
+	if err := sched.DrainPod(ctx, podManager.GetPodID(), grace); err != nil {
+		return fmt.Errorf("failed to drain pod: %w", err)
+	}
+	return nil
+}
+
+// requiredCapabilities returns the capabilities needed by any registered command
+func (s *Schedulerx) requiredCapabilities() []string {
+	var capabilities []string
+	for _, cmd := range s.Commands() {
+		capabilities = append(capabilities, command.RequiredCapabilities(cmd, s.config.CommandRequiredCapabilities)...)
+	}
+	return capabilities
+}
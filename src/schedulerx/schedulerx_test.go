@@ -0,0 +1,297 @@
+package schedulerx
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/command"
+	"github.com/yashkumarverma/schedulerx/src/scheduler"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"github.com/yashkumarverma/schedulerx/src/utils/cache"
+	"go.uber.org/zap"
+)
+
+// newTestSchedulerx returns an instance with every config variable at its default and logging discarded
+func newTestSchedulerx(t *testing.T) *Schedulerx {
+	t.Helper()
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	return New(config, WithLogger(&utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}))
+}
+
+func TestRegisterCustomCommand(t *testing.T) {
+	s := newTestSchedulerx(t)
+	greet := command.NewFuncCommand("greet", "Greets", "", func(ctx context.Context, params []string) (string, error) {
+		return "hello", nil
+	})
+
+	if err := s.RegisterCommand(greet); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Commands()["greet"]; !ok {
+		t.Fatal("custom command is not scheduled")
+	}
+	if err := s.RegisterCommand(greet); err == nil {
+		t.Fatal("registering the same ID twice succeeded")
+	}
+
+	s.UnregisterCommand("greet")
+	s.UnregisterCommand("echo")
+	for _, id := range []string{"greet", "echo"} {
+		if _, ok := s.Commands()[id]; ok {
+			t.Fatalf("unregistered command %s is still scheduled", id)
+		}
+	}
+}
+
+// schedulePassTaken requests an immediate scheduling pass and reports whether a
+// scheduling loop picked it up within a short wait, leaving no request behind
+func schedulePassTaken(sched *scheduler.Scheduler) bool {
+	sched.NotifyLeaderChanged(true)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if len(sched.ScheduleNow()) == 0 {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	<-sched.ScheduleNow()
+	return false
+}
+
+func TestLeaderOnlySchedulingSkipsFollowers(t *testing.T) {
+	s := newTestSchedulerx(t)
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	sched := scheduler.NewScheduler(cache.NewClientFromRedis(rdb), s.logger, s.config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan bool)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.runLeaderOnlyScheduling(ctx, sched, changes)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if schedulePassTaken(sched) {
+		t.Fatal("follower ran a scheduling pass")
+	}
+
+	changes <- true
+	if !schedulePassTaken(sched) {
+		t.Fatal("scheduling loop did not start on promotion")
+	}
+
+	changes <- false
+	changes <- false // Handled once the loop was stopped by the first
+	if schedulePassTaken(sched) {
+		t.Fatal("scheduling loop kept running after losing leadership")
+	}
+}
+
+func TestCommandDefaultParamsOverrideBuiltins(t *testing.T) {
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	config.CommandDefaultParams = map[string][]string{
+		"du":      {"/var/log"},
+		"ping":    {"example.org"},
+		"missing": {"ignored"},
+	}
+	s := New(config, WithLogger(&utils.StandardLogger{SugaredLogger: zap.NewNop().Sugar()}))
+
+	want := map[string][]string{
+		"du":   {"/var/log"},
+		"ping": {"example.org", "4", "1.0"},
+		"ls":   {"."},
+	}
+	for id, params := range want {
+		cmd, ok := s.Commands()[id]
+		if !ok {
+			t.Fatalf("built-in command %s is not registered", id)
+		}
+		if _, scheduled, _ := cmd.Schedule(); !slices.Equal(scheduled, params) {
+			t.Errorf("%s is scheduled with %q, want %q", id, scheduled, params)
+		}
+		if got := cmd.Parameters(); !slices.Equal(got, params) {
+			t.Errorf("%s defaults to %q, want %q", id, got, params)
+		}
+	}
+}
+
+func TestSchedulingLoopRunsPassOnPromotion(t *testing.T) {
+	s := newTestSchedulerx(t)
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	sched := scheduler.NewScheduler(cache.NewClientFromRedis(rdb), s.logger, s.config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.runSchedulingLoop(ctx, sched)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// SchedulingInterval is far longer than the wait, so only the promotion can start a pass
+	if !schedulePassTaken(sched) {
+		t.Fatal("promotion did not start a scheduling pass before the next tick")
+	}
+}
+
+// inChildProcess reports whether the test runs in a child process of its own, otherwise it
+// runs the test in one and waits for it. Run sets up the process-wide pod manager, so tests
+// calling it go through a child process to leave the pod manager to Example.
+func inChildProcess(t *testing.T) bool {
+	t.Helper()
+	if os.Getenv("SCHEDULERX_TEST_RUN_CHILD") != "" {
+		return true
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$")
+	cmd.Env = append(os.Environ(), "SCHEDULERX_TEST_RUN_CHILD=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, output)
+	}
+	return false
+}
+
+func TestShutdownConcurrentWithRun(t *testing.T) {
+	if !inChildProcess(t) {
+		return
+	}
+
+	s := newTestSchedulerx(t)
+	s.config.HTTPPort = 0
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	s.redisClient = cache.NewClientFromRedis(rdb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	// Shutdown and the admin methods read what Run is creating, -race reports unguarded access
+	for i := 0; i < 20; i++ {
+		if err := s.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		s.PausePod(context.Background(), "missing-pod")
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShutdownAfterCancelledRunFinishesRunningJob(t *testing.T) {
+	if !inChildProcess(t) {
+		return
+	}
+
+	s := newTestSchedulerx(t)
+	s.config.HTTPPort = 0
+	s.config.ShutdownGraceSeconds = 30
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	s.redisClient = cache.NewClientFromRedis(rdb)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := command.NewFuncCommand("slow", "Runs until released", "* * * * * *", func(ctx context.Context, params []string) (string, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	if err := s.RegisterCommand(slow); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+	select {
+	case <-started:
+	case <-time.After(30 * time.Second):
+		t.Fatal("no job started")
+	}
+
+	// Shut down the way main does, cancelling Run first
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(100*time.Millisecond, func() { close(release) })
+	start := time.Now()
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 10*time.Second {
+		t.Fatalf("shutdown took %s, want it to return once the running job finished", elapsed)
+	}
+
+	succeeded := 0
+	for _, key := range server.Keys() {
+		if strings.HasPrefix(key, "schedulerx:job_lock:") {
+			t.Errorf("job lock %s is still held after shutdown", key)
+		}
+		if !strings.HasPrefix(key, "scheduler:job:") {
+			continue
+		}
+		data, err := server.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var job command.Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			t.Fatal(err)
+		}
+		switch job.Status {
+		case command.Running:
+			t.Errorf("job %s is still running after shutdown", job.ID)
+		case command.Success:
+			// Built-in commands run alongside, only the blocking command was running at shutdown
+			if job.CommandID == "slow" {
+				succeeded++
+			}
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d slow jobs succeeded, want the job running at shutdown to complete", succeeded)
+	}
+}
@@ -0,0 +1,134 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yashkumarverma/schedulerx/src/metrics"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans and metrics emitted by schedulerx
+const instrumentationName = "github.com/yashkumarverma/schedulerx"
+
+// serviceName is reported as service.name unless OTEL_SERVICE_NAME overrides it
+const serviceName = "schedulerx"
+
+// Tracer returns the tracer for schedulerx spans. Spans are dropped until Setup
+// installs an exporter, so instrumented code does not need to check the config.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup exports traces and the metrics of the metrics package to the OTLP collector at
+// OTEL_EXPORTER_OTLP_ENDPOINT. The exporters read the other OTEL_EXPORTER_OTLP_* variables
+// themselves. Nothing is set up without an endpoint. The returned function flushes and
+// stops the exporters.
+func Setup(ctx context.Context, config *utils.Config, podID string) (shutdown func(context.Context) error, err error) {
+	if config.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceInstanceID(podID),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+
+	metricExporter, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to create metric exporter: %w", err), tracerProvider.Shutdown(ctx))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	if err := observeMetrics(meterProvider.Meter(instrumentationName)); err != nil {
+		return nil, errors.Join(err, tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// observeMetrics mirrors every registered metric as an observable OTel instrument
+func observeMetrics(meter metric.Meter) error {
+	instruments := make(map[string]metric.Float64Observable)
+	observables := make([]metric.Observable, 0)
+	for _, family := range metrics.Collect() {
+		var instrument metric.Float64Observable
+		var err error
+		if family.Counter {
+			instrument, err = meter.Float64ObservableCounter(family.Name, metric.WithDescription(family.Help))
+		} else {
+			instrument, err = meter.Float64ObservableGauge(family.Name, metric.WithDescription(family.Help))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create instrument %s: %w", family.Name, err)
+		}
+		instruments[family.Name] = instrument
+		observables = append(observables, instrument)
+	}
+
+	_, err := meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		for _, family := range metrics.Collect() {
+			instrument, ok := instruments[family.Name]
+			if !ok {
+				continue
+			}
+			for labelValue, value := range family.Values {
+				if family.Label == "" {
+					observer.ObserveFloat64(instrument, value)
+					continue
+				}
+				observer.ObserveFloat64(instrument, value, metric.WithAttributes(attribute.String(family.Label, labelValue)))
+			}
+		}
+		return nil
+	}, observables...)
+	if err != nil {
+		return fmt.Errorf("failed to register metrics callback: %w", err)
+	}
+	return nil
+}
+
+// Inject encodes the span context of ctx as a W3C traceparent, empty when ctx carries no span
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// Extract returns the span context encoded in a W3C traceparent, invalid when traceparent is empty or malformed
+func Extract(traceparent string) trace.SpanContext {
+	carrier := propagation.MapCarrier{"traceparent": traceparent}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
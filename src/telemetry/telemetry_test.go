@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestInjectExtractRoundTrip(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	ctx, span := provider.Tracer("test").Start(context.Background(), "create")
+	defer span.End()
+
+	traceparent := Inject(ctx)
+	if traceparent == "" {
+		t.Fatal("span context was not encoded")
+	}
+	if extracted := Extract(traceparent); extracted.TraceID() != span.SpanContext().TraceID() || extracted.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("traceparent %q decoded to %s/%s, want %s/%s", traceparent,
+			extracted.TraceID(), extracted.SpanID(), span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	}
+
+	if Inject(context.Background()) != "" {
+		t.Fatal("a context without a span encoded a traceparent")
+	}
+	for _, traceparent := range []string{"", "not-a-traceparent"} {
+		if Extract(traceparent).IsValid() {
+			t.Errorf("Extract(%q) returned a valid span context", traceparent)
+		}
+	}
+}
@@ -2,14 +2,44 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/yashkumarverma/schedulerx/src/utils"
 )
 
+const (
+	// minTimeout is the smallest dial/read/write timeout accepted from config
+	minTimeout = 100 * time.Millisecond
+
+	// healthCheckInterval is how often a healthy connection is pinged
+	healthCheckInterval = 5 * time.Second
+
+	// maxHealthCheckBackoff caps the delay between pings while Redis is unreachable
+	maxHealthCheckBackoff = 30 * time.Second
+)
+
+var (
+	// ErrUnavailable is returned while Redis is known to be unreachable, callers may retry later
+	ErrUnavailable = errors.New("redis unavailable")
+
+	// ErrAuthentication is returned when Redis rejects the configured credentials, retrying does not help
+	ErrAuthentication = errors.New("redis authentication failed, check CACHE_USERNAME and CACHE_PASSWORD")
+
+	// ErrPermission is returned when the configured Redis user may not run a command or access a key
+	ErrPermission = errors.New("redis permission denied, grant the CACHE_USERNAME user access to the commands and keys schedulerx uses")
+)
+
+// authErrorPrefixes start the messages of Redis errors caused by missing or wrong credentials.
+// "invalid password" and "AUTH" are sent by servers older than Redis 6, behind an "ERR " prefix.
+var authErrorPrefixes = []string{"NOAUTH", "WRONGPASS", "invalid password", "AUTH"}
+
 type Client struct {
-	client *redis.Client
+	client  *redis.Client
+	healthy atomic.Bool
 }
 
 func (c *Client) GetClient() *redis.Client {
@@ -21,21 +51,172 @@ func (c *Client) Ping(ctx context.Context) error {
 }
 
 func NewClient(ctx context.Context, config *utils.Config) (*Client, error) {
-	addr := fmt.Sprintf("%s:%s", config.CacheClusterURL, "6379")
+	options, err := newOptions(config)
+	if err != nil {
+		return nil, err
+	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: config.CachePassword,
-		Username: config.CacheUsername,
-		DB:       0,
-	})
+	rdb := redis.NewClient(options)
 
 	// Test the connection
 	if err := rdb.Ping(ctx).Err(); err != nil {
+		if classified, ok := classifyError(err); ok {
+			return nil, classified
+		}
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	return &Client{
+	c := &Client{
+		client: rdb,
+	}
+	c.healthy.Store(true)
+	rdb.AddHook(errorHook{client: c})
+	return c, nil
+}
+
+// NewClientFromRedis wraps an already connected go-redis client, e.g. one built with
+// options NewClient does not expose. The connection is assumed healthy until Supervise says otherwise.
+func NewClientFromRedis(rdb *redis.Client) *Client {
+	c := &Client{
 		client: rdb,
+	}
+	c.healthy.Store(true)
+	rdb.AddHook(errorHook{client: c})
+	return c
+}
+
+// Healthy reports whether the last connectivity check succeeded
+func (c *Client) Healthy() bool {
+	return c.healthy.Load()
+}
+
+// Supervise pings Redis until ctx is cancelled, tracking connectivity.
+// While Redis is unreachable it backs off exponentially between pings; the
+// client redials on the next successful ping, so commands recover on their own.
+func (c *Client) Supervise(ctx context.Context, logger *utils.StandardLogger) {
+	delay := healthCheckInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if err := c.Ping(ctx); err != nil {
+			if c.healthy.CompareAndSwap(true, false) {
+				if classified, ok := classifyError(err); ok {
+					err = classified
+				}
+				logger.Error("Lost connection to Redis", "error", err)
+			}
+			delay = min(delay*2, maxHealthCheckBackoff)
+			continue
+		}
+
+		if c.healthy.CompareAndSwap(false, true) {
+			logger.Info("Reconnected to Redis")
+		}
+		delay = healthCheckInterval
+	}
+}
+
+// wrapError marks authentication and permission errors as ErrAuthentication and
+// ErrPermission, and other errors raised while Redis is unreachable as ErrUnavailable
+func (c *Client) wrapError(err error) error {
+	if classified, ok := classifyError(err); ok {
+		return classified
+	}
+	if err == nil || c.Healthy() || errors.Is(err, ErrUnavailable) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
+
+// wrapCmdError wraps the error of cmd with wrapError. redis.Nil only reports a missing key and is left alone.
+func (c *Client) wrapCmdError(cmd redis.Cmder, err error) error {
+	if err == nil || err == redis.Nil {
+		return err
+	}
+	wrapped := c.wrapError(err)
+	cmd.SetErr(wrapped)
+	return wrapped
+}
+
+// errorHook applies wrapError to every command, so callers using GetClient directly
+// see ErrUnavailable, ErrAuthentication and ErrPermission like the helper funcs do
+type errorHook struct {
+	client *Client
+}
+
+func (h errorHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h errorHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return h.client.wrapCmdError(cmd, next(ctx, cmd))
+	}
+}
+
+func (h errorHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			h.client.wrapCmdError(cmd, cmd.Err())
+		}
+		if err == nil || err == redis.Nil {
+			return err
+		}
+		return h.client.wrapError(err)
+	}
+}
+
+// classifyError wraps Redis errors caused by wrong credentials in ErrAuthentication and
+// errors caused by ACL restrictions in ErrPermission. It reports false for other errors.
+func classifyError(err error) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if errors.Is(err, ErrAuthentication) || errors.Is(err, ErrPermission) {
+		return err, true
+	}
+	for _, prefix := range authErrorPrefixes {
+		if redis.HasErrorPrefix(err, prefix) {
+			return fmt.Errorf("%w: %w", ErrAuthentication, err), true
+		}
+	}
+	if redis.HasErrorPrefix(err, "NOPERM") {
+		return fmt.Errorf("%w: %w", ErrPermission, err), true
+	}
+	return nil, false
+}
+
+// newOptions builds the Redis client options from config, validating pool size and timeouts
+func newOptions(config *utils.Config) (*redis.Options, error) {
+	if config.CachePoolSize < 0 {
+		return nil, fmt.Errorf("invalid CACHE_POOL_SIZE %d: must not be negative", config.CachePoolSize)
+	}
+
+	timeouts := map[string]int{
+		"CACHE_DIAL_TIMEOUT_MS":  config.CacheDialTimeoutMs,
+		"CACHE_READ_TIMEOUT_MS":  config.CacheReadTimeoutMs,
+		"CACHE_WRITE_TIMEOUT_MS": config.CacheWriteTimeoutMs,
+	}
+	for name, ms := range timeouts {
+		timeout := time.Duration(ms) * time.Millisecond
+		if ms != 0 && timeout < minTimeout {
+			return nil, fmt.Errorf("invalid %s %d: must be 0 or at least %s", name, ms, minTimeout)
+		}
+	}
+
+	return &redis.Options{
+		Addr:         fmt.Sprintf("%s:%s", config.CacheClusterURL, "6379"),
+		Password:     config.CachePassword,
+		Username:     config.CacheUsername,
+		DB:           0,
+		PoolSize:     config.CachePoolSize,
+		DialTimeout:  time.Duration(config.CacheDialTimeoutMs) * time.Millisecond,
+		ReadTimeout:  time.Duration(config.CacheReadTimeoutMs) * time.Millisecond,
+		WriteTimeout: time.Duration(config.CacheWriteTimeoutMs) * time.Millisecond,
 	}, nil
 }
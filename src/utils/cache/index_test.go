@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/caarlos0/env/v11"
+	"github.com/redis/go-redis/v9"
+	"github.com/yashkumarverma/schedulerx/src/utils"
+)
+
+// newTestClient returns a client connected to a fresh in-memory Redis
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return NewClientFromRedis(rdb), server
+}
+
+func TestDroppedConnectionIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestClient(t)
+	server.Close()
+	c.healthy.Store(false)
+
+	if err := c.GetClient().Set(ctx, "key", "value", 0).Err(); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("direct command returned %v, want %v", err, ErrUnavailable)
+	}
+
+	pipe := c.GetClient().TxPipeline()
+	pipe.Set(ctx, "key", "value", 0)
+	if _, err := pipe.Exec(ctx); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("pipeline returned %v, want %v", err, ErrUnavailable)
+	}
+
+	err := c.Set(ctx, "key", "value")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("helper returned %v, want %v", err, ErrUnavailable)
+	}
+	if strings.Count(err.Error(), ErrUnavailable.Error()) != 1 {
+		t.Fatalf("helper error %q is wrapped more than once", err)
+	}
+}
+
+func TestHealthyClientErrorsAreNotUnavailable(t *testing.T) {
+	c, server := newTestClient(t)
+	server.Close()
+
+	if err := c.GetClient().Ping(context.Background()).Err(); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("ping returned %v, want a plain error while the client is still considered healthy", err)
+	}
+}
+
+func TestMissingKeyIsNotWrapped(t *testing.T) {
+	c, _ := newTestClient(t)
+	c.healthy.Store(false)
+
+	if err := c.GetClient().Get(context.Background(), "missing").Err(); err != redis.Nil {
+		t.Fatalf("missing key returned %v, want redis.Nil", err)
+	}
+	if value, err := c.Get(context.Background(), "missing"); value != nil || err != nil {
+		t.Fatalf("helper returned %v, %v for a missing key, want nil, nil", value, err)
+	}
+}
+
+func TestDirectCommandsClassifyAuthErrors(t *testing.T) {
+	c, server := newTestClient(t)
+	server.RequireAuth("secret")
+
+	err := c.GetClient().Get(context.Background(), "key").Err()
+	if !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("unauthenticated command returned %v, want %v", err, ErrAuthentication)
+	}
+	if strings.Count(err.Error(), ErrAuthentication.Error()) != 1 {
+		t.Fatalf("error %q is wrapped more than once", err)
+	}
+}
+
+// redisError is an error reply as sent by a Redis server
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func (e redisError) RedisError() {}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{redisError("NOAUTH Authentication required."), ErrAuthentication},
+		{redisError("WRONGPASS invalid username-password pair or user is disabled."), ErrAuthentication},
+		{redisError("ERR invalid password"), ErrAuthentication},
+		{redisError("ERR AUTH <password> called without any password configured for the default user"), ErrAuthentication},
+		{redisError("NOPERM this user has no permissions to run the 'flushall' command"), ErrPermission},
+		{redisError("ERR unknown command 'FOO'"), nil},
+		{redisError("WRONGTYPE Operation against a key holding the wrong kind of value"), nil},
+		{errors.New("NOAUTH in a plain error"), nil},
+	}
+	for _, tt := range tests {
+		classified, ok := classifyError(tt.err)
+		if ok != (tt.want != nil) || (tt.want != nil && (!errors.Is(classified, tt.want) || !errors.Is(classified, tt.err))) {
+			t.Errorf("classifyError(%q) = %v, %v, want it wrapped in %v", tt.err, classified, ok, tt.want)
+		}
+	}
+}
+
+func TestWrongPasswordIsAuthenticationError(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr(), Password: "wrong", MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	c := NewClientFromRedis(rdb)
+
+	err := c.Set(context.Background(), "key", "value")
+	if !errors.Is(err, ErrAuthentication) || !strings.Contains(err.Error(), "check CACHE_USERNAME and CACHE_PASSWORD") {
+		t.Fatalf("wrong password returned %v, want %v", err, ErrAuthentication)
+	}
+	if errors.Is(err, ErrUnavailable) {
+		t.Fatalf("wrong password returned %v, which callers would retry as unavailable", err)
+	}
+}
+
+// configFrom parses the config from the given environment only
+func configFrom(t *testing.T, environment map[string]string) *utils.Config {
+	t.Helper()
+	config := &utils.Config{}
+	if err := env.ParseWithOptions(config, env.Options{Environment: environment}); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestNewOptionsFromConfig(t *testing.T) {
+	options, err := newOptions(configFrom(t, map[string]string{
+		"CACHE_CLUSTER_URL":      "redis.internal",
+		"CACHE_POOL_SIZE":        "25",
+		"CACHE_DIAL_TIMEOUT_MS":  "1500",
+		"CACHE_READ_TIMEOUT_MS":  "750",
+		"CACHE_WRITE_TIMEOUT_MS": "0",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.Addr != "redis.internal:6379" || options.PoolSize != 25 {
+		t.Fatalf("options address %s pool size %d, want redis.internal:6379 and 25", options.Addr, options.PoolSize)
+	}
+	if options.DialTimeout != 1500*time.Millisecond || options.ReadTimeout != 750*time.Millisecond || options.WriteTimeout != 0 {
+		t.Fatalf("options timeouts dial %s read %s write %s, want 1.5s 750ms and the client default",
+			options.DialTimeout, options.ReadTimeout, options.WriteTimeout)
+	}
+}
+
+func TestNewOptionsDefaults(t *testing.T) {
+	options, err := newOptions(configFrom(t, map[string]string{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.PoolSize != 0 || options.DialTimeout != 5*time.Second || options.ReadTimeout != 3*time.Second || options.WriteTimeout != 3*time.Second {
+		t.Fatalf("default options pool size %d dial %s read %s write %s, want 0 5s 3s 3s",
+			options.PoolSize, options.DialTimeout, options.ReadTimeout, options.WriteTimeout)
+	}
+}
+
+func TestNewOptionsRejectsBelowMinimums(t *testing.T) {
+	for name, value := range map[string]string{
+		"CACHE_POOL_SIZE":        "-1",
+		"CACHE_DIAL_TIMEOUT_MS":  "50",
+		"CACHE_READ_TIMEOUT_MS":  "1",
+		"CACHE_WRITE_TIMEOUT_MS": "-5",
+	} {
+		if _, err := newOptions(configFrom(t, map[string]string{name: value})); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s=%s gave error %v, want it rejected", name, value, err)
+		}
+	}
+}
@@ -2,6 +2,11 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -13,24 +18,263 @@ type Config struct {
 	CachePassword   string `env:"CACHE_PASSWORD" envDefault:""`
 	CacheUsername   string `env:"CACHE_USERNAME" envDefault:""`
 	CacheTLSDomain  string `env:"CACHE_TLS_DOMAIN" envDefault:""`
-	PodID           string `env:"POD_ID" envDefault:""`
-	NextJobCount    int    `env:"NEXT_JOB_COUNT" envDefault:"1000"`
+
+	// Redis connection pool and timeouts, 0 keeps the client defaults
+	CachePoolSize       int `env:"CACHE_POOL_SIZE" envDefault:"0"`
+	CacheDialTimeoutMs  int `env:"CACHE_DIAL_TIMEOUT_MS" envDefault:"5000"`
+	CacheReadTimeoutMs  int `env:"CACHE_READ_TIMEOUT_MS" envDefault:"3000"`
+	CacheWriteTimeoutMs int `env:"CACHE_WRITE_TIMEOUT_MS" envDefault:"3000"`
+
+	HTTPPort int `env:"HTTP_PORT" envDefault:"8080"` // Port of the HTTP API, 0 disables it
+	// EnableDebugEndpoints allows destructive endpoints such as POST /debug/reset, never enable in production
+	EnableDebugEndpoints bool `env:"ENABLE_DEBUG_ENDPOINTS" envDefault:"false"`
+
+	PodID        string `env:"POD_ID" envDefault:""`
+	NextJobCount int    `env:"NEXT_JOB_COUNT" envDefault:"1000"`
+	PodCapacity  int    `env:"POD_CAPACITY" envDefault:"0"` // Max jobs assigned to this pod at once, 0 means unlimited
+
+	// PodAddress is the host:port other pods reach this pod's HTTP API at, followers forward admin requests to the leader's.
+	// Defaults to the hostname and HTTP_PORT.
+	PodAddress string `env:"POD_ADDRESS" envDefault:""`
+
+	// LeaderOnlyScheduling runs the scheduling loop only on the leader, followers do not tick at all
+	LeaderOnlyScheduling bool `env:"LEADER_ONLY_SCHEDULING" envDefault:"false"`
+
+	// RefuseDuplicatePodID fails startup when a live pod already uses POD_ID, otherwise a random suffix is appended
+	RefuseDuplicatePodID bool `env:"REFUSE_DUPLICATE_POD_ID" envDefault:"false"`
+
+	// PodRegistryWarnBytes logs a warning once the schedulerx:pods registry grows beyond this size, 0 disables the warning
+	PodRegistryWarnBytes int `env:"POD_REGISTRY_WARN_BYTES" envDefault:"262144"`
+
+	// PodTTL is how long a pod may miss heartbeats before it gets no new jobs and its jobs are reassigned
+	PodTTL time.Duration `env:"POD_TTL" envDefault:"5s"`
+	// LeadershipStaleness is how long a pod may miss heartbeats before it loses leadership eligibility and
+	// is removed from the registry, it is never shorter than PodTTL so a brief pause does not flap leadership
+	LeadershipStaleness time.Duration `env:"LEADERSHIP_STALENESS" envDefault:"15s"`
+
+	// PodHealthCheckInterval is how often the leader returns the jobs of unresponsive pods to the pool, 0 disables it
+	PodHealthCheckInterval time.Duration `env:"POD_HEALTH_CHECK_INTERVAL" envDefault:"30s"`
+
+	// HeartbeatFailureThreshold is the number of consecutive failed heartbeats after which the pod stops executing jobs
+	HeartbeatFailureThreshold int `env:"HEARTBEAT_FAILURE_THRESHOLD" envDefault:"3"`
+
+	// SchedulingLookahead is how far ahead jobs are materialized on each scheduling tick
+	SchedulingLookahead time.Duration `env:"SCHEDULING_LOOKAHEAD" envDefault:"5m"`
+	// SchedulingLookaheadOccurrences caps the occurrences enqueued per command within the lookahead, 0 means no cap
+	SchedulingLookaheadOccurrences int `env:"SCHEDULING_LOOKAHEAD_OCCURRENCES" envDefault:"0"`
+
+	// AssignmentHorizon limits assignment to jobs scheduled no later than this far from now
+	AssignmentHorizon time.Duration `env:"ASSIGNMENT_HORIZON" envDefault:"1m"`
+
+	// AssignmentStrategy names the registered strategy that picks a pod for each job
+	AssignmentStrategy string `env:"ASSIGNMENT_STRATEGY" envDefault:"round-robin"`
+
+	// PinnedPodFallback assigns pinned jobs round-robin while their pod is unavailable instead of leaving them unassigned
+	PinnedPodFallback bool `env:"PINNED_POD_FALLBACK" envDefault:"false"`
+
+	// SchedulingHighWaterMark pauses enqueuing new jobs once this many jobs are scheduled, assigned or running, 0 disables backpressure
+	SchedulingHighWaterMark int `env:"SCHEDULING_HIGH_WATER_MARK" envDefault:"0"`
+	// SchedulingLowWaterMark resumes enqueuing once pending jobs drain below it, defaults to half the high-water mark
+	SchedulingLowWaterMark int `env:"SCHEDULING_LOW_WATER_MARK" envDefault:"0"`
+
+	// MaxQueuedJobs caps the jobs in the job sorted set, new jobs beyond it are rejected, 0 means no cap
+	MaxQueuedJobs int `env:"MAX_QUEUED_JOBS" envDefault:"0"`
+
+	// NotifyOnChangeCommands lists commands whose output is compared with the previous run, notifying only on change
+	NotifyOnChangeCommands []string `env:"NOTIFY_ON_CHANGE_COMMANDS" envSeparator:","`
+	// ChangeWebhookURL receives a POST for every output change, changes are only logged when empty
+	ChangeWebhookURL string `env:"CHANGE_WEBHOOK_URL" envDefault:""`
+
+	// SkipIfStillRunningCommands lists commands whose next occurrence is not enqueued while an earlier run is assigned or running
+	SkipIfStillRunningCommands []string `env:"SKIP_IF_STILL_RUNNING_COMMANDS" envSeparator:","`
+
+	// MaxChainDepth bounds how many follow-up jobs OnSuccess/OnFailure chains may enqueue in a row
+	MaxChainDepth int `env:"MAX_CHAIN_DEPTH" envDefault:"5"`
+
+	// MaintenanceSchedule is the cron schedule of the built-in maintenance command, empty disables it
+	MaintenanceSchedule string `env:"MAINTENANCE_SCHEDULE" envDefault:"0 0 * * * *"`
+
+	// StatusReportSchedule is the cron schedule of the built-in status command logging a consistency report, empty disables it
+	StatusReportSchedule string `env:"STATUS_REPORT_SCHEDULE" envDefault:""`
+
+	// CompletedJobRetention keeps finished jobs queryable by finish time in schedulerx:completed for this long, 0 disables it
+	CompletedJobRetention time.Duration `env:"COMPLETED_JOB_RETENTION" envDefault:"0"`
+
+	// OTLPEndpoint enables pushing traces and metrics to an OpenTelemetry collector, empty disables it.
+	// The other OTEL_EXPORTER_OTLP_* variables are read by the exporters directly.
+	OTLPEndpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" envDefault:""`
+
+	// ExportDir receives a JSON file per finished job, partitioned by day, empty disables exporting
+	ExportDir string `env:"EXPORT_DIR" envDefault:""`
+	// ExportQueueSize bounds how many finished jobs may wait for export before new ones are dropped
+	ExportQueueSize int `env:"EXPORT_QUEUE_SIZE" envDefault:"1000"`
+
+	// JobHistoryCap is the number of entries maintenance keeps per command history list, 0 disables trimming
+	JobHistoryCap int `env:"JOB_HISTORY_CAP" envDefault:"100"`
+
+	// ShutdownGraceSeconds is how long shutdown waits for running jobs before unassigning them
+	ShutdownGraceSeconds int `env:"SHUTDOWN_GRACE_SECONDS" envDefault:"30"`
+
+	// DefaultCommandTimeoutSeconds bounds each job's execution unless its command sets its own timeout, 0 disables it
+	DefaultCommandTimeoutSeconds int `env:"DEFAULT_COMMAND_TIMEOUT_SECONDS" envDefault:"0"`
+
+	// QuarantineCorruptJobs keeps the raw data of undecodable jobs in the schedulerx:corrupt hash when they are discarded
+	QuarantineCorruptJobs bool `env:"QUARANTINE_CORRUPT_JOBS" envDefault:"true"`
+
+	// MaxJobAge cancels jobs scheduled longer ago than this instead of running them, 0 disables the check
+	MaxJobAge time.Duration `env:"MAX_JOB_AGE" envDefault:"0"`
+
+	// SeparateJobOutput stores the output of finished jobs under scheduler:job_output:<job id> instead of in
+	// their details, so status reads and scans do not fetch it. GET /jobs/{id} still includes it.
+	SeparateJobOutput bool `env:"SEPARATE_JOB_OUTPUT" envDefault:"false"`
+
+	// OverdueTolerance is how long after its scheduled time a job may start and still count as on time.
+	// The default covers the usual lag of the scheduling, assignment and execution tickers.
+	OverdueTolerance time.Duration `env:"OVERDUE_TOLERANCE" envDefault:"15s"`
+
+	// SMTP settings for the email command, which is only registered when SMTPHost is set
+	SMTPHost      string `env:"SMTP_HOST" envDefault:""`
+	SMTPPort      int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername  string `env:"SMTP_USERNAME" envDefault:""`
+	SMTPPassword  string `env:"SMTP_PASSWORD" envDefault:""`
+	SMTPFrom      string `env:"SMTP_FROM" envDefault:""`
+	EmailTo       string `env:"EMAIL_TO" envDefault:""` // Comma separated default recipients
+	EmailSchedule string `env:"EMAIL_SCHEDULE" envDefault:"0 0 8 * * *"`
+
+	// HealthCheckURL is probed by the health check command, which is only registered when it is set
+	HealthCheckURL      string `env:"HEALTHCHECK_URL" envDefault:""`
+	HealthCheckSchedule string `env:"HEALTHCHECK_SCHEDULE" envDefault:"0 * * * * *"`
+
+	// WebhookURL receives the webhook command's POST, the command is only registered when it is set
+	WebhookURL          string        `env:"WEBHOOK_URL" envDefault:""`
+	WebhookPayload      string        `env:"WEBHOOK_PAYLOAD" envDefault:"{}"`
+	WebhookSchedule     string        `env:"WEBHOOK_SCHEDULE" envDefault:""` // Empty only runs the webhook when triggered
+	WebhookMaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	WebhookRetryBackoff time.Duration `env:"WEBHOOK_RETRY_BACKOFF" envDefault:"1s"` // Delay before the first retry, doubled for each further one
+
+	// CommandParallelism enqueues this many shards per occurrence of a command, e.g. "du:4"
+	CommandParallelism map[string]int `env:"COMMAND_PARALLELISM"`
+
+	// CommandDefaultParams replaces the default parameters of commands, read from CMD_DEFAULT_PARAMS_<command id>
+	// variables holding comma separated values, e.g. CMD_DEFAULT_PARAMS_du=/var/log
+	CommandDefaultParams map[string][]string `env:"-"`
+
+	// CommandResultCacheTTL caches successful output per command and params for this long, e.g. "du:10m"
+	CommandResultCacheTTL map[string]time.Duration `env:"COMMAND_RESULT_CACHE_TTL"`
+
+	// OutputRedactPatterns are regular expressions, one per line, masked with *** in every command's output and errors
+	OutputRedactPatterns []string `env:"OUTPUT_REDACT_PATTERNS" envSeparator:"\n"`
+	// CommandRedactPatterns adds patterns per command, read from CMD_REDACT_PATTERNS_<command id> variables holding one per line
+	CommandRedactPatterns map[string][]string `env:"-"`
+
+	// CommandSuccessExitCodes lists the exit codes treated as success per command, read from CMD_SUCCESS_EXIT_CODES_<command id>
+	// variables holding comma separated codes, e.g. CMD_SUCCESS_EXIT_CODES_shell=0,1. Commands not listed succeed only with 0.
+	CommandSuccessExitCodes map[string][]int `env:"-"`
+
+	// CommandRequiredCapabilities replaces the capabilities a pod needs to run a command, read from
+	// CMD_REQUIRED_CAPABILITIES_<command id> variables holding comma separated binary names, e.g. CMD_REQUIRED_CAPABILITIES_shell=sh,pg_dump
+	CommandRequiredCapabilities map[string][]string `env:"-"`
+
+	// CommandCooldown is the minimum time between the starts of two jobs of a command, e.g. "backup:10m".
+	// Jobs due sooner, whether scheduled, triggered or backfilled, are deferred until the cooldown ends.
+	CommandCooldown map[string]time.Duration `env:"COMMAND_COOLDOWN"`
+
+	// CommandLogLevels sets the level routine successes are logged at per command, e.g. "echo:debug,du:warn"
+	CommandLogLevels map[string]string `env:"COMMAND_LOG_LEVELS"`
 }
 
 var appConfig *Config
 
+// redacted replaces secrets in Config.Redacted
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy of the config safe to log, with passwords and webhook URLs
+// replaced, as webhook URLs often embed a token
+func (c Config) Redacted() Config {
+	for _, secret := range []*string{&c.CachePassword, &c.SMTPPassword, &c.WebhookURL, &c.ChangeWebhookURL} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return c
+}
+
+// LoadConfig reads .env (when present) and the environment into a new Config.
+// It does not log, non-fatal problems are returned as warnings for the caller to report.
+func LoadConfig() (*Config, []string, error) {
+	var warnings []string
+	if err := godotenv.Load(".env"); err != nil {
+		warnings = append(warnings, "Unable to load .env file. Continuing without loading it...")
+	}
+
+	config := &Config{}
+	if err := env.Parse(config); err != nil {
+		return nil, warnings, fmt.Errorf("failed to parse config: %w", err)
+	}
+	config.CommandDefaultParams = loadPerCommandLists(os.Environ(), commandDefaultParamsPrefix, ",")
+	config.CommandRedactPatterns = loadPerCommandLists(os.Environ(), commandRedactPatternsPrefix, "\n")
+	config.CommandRequiredCapabilities = loadPerCommandLists(os.Environ(), commandRequiredCapabilitiesPrefix, ",")
+
+	exitCodes, err := parseExitCodes(loadPerCommandLists(os.Environ(), commandSuccessExitCodesPrefix, ","))
+	if err != nil {
+		return nil, warnings, fmt.Errorf("failed to parse config: %w", err)
+	}
+	config.CommandSuccessExitCodes = exitCodes
+	return config, warnings, nil
+}
+
+// Prefixes of the per-command variables, followed by the command ID
+const (
+	commandDefaultParamsPrefix        = "CMD_DEFAULT_PARAMS_"
+	commandRedactPatternsPrefix       = "CMD_REDACT_PATTERNS_"
+	commandSuccessExitCodesPrefix     = "CMD_SUCCESS_EXIT_CODES_"
+	commandRequiredCapabilitiesPrefix = "CMD_REQUIRED_CAPABILITIES_"
+)
+
+// loadPerCommandLists collects <prefix><command id> variables from environ, splitting their values on separator
+func loadPerCommandLists(environ []string, prefix, separator string) map[string][]string {
+	lists := make(map[string][]string)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		commandID, ok := strings.CutPrefix(key, prefix)
+		if !ok || commandID == "" {
+			continue
+		}
+		lists[commandID] = strings.Split(value, separator)
+	}
+	return lists
+}
+
+// parseExitCodes converts per-command lists of exit codes to integers
+func parseExitCodes(lists map[string][]string) (map[string][]int, error) {
+	codes := make(map[string][]int, len(lists))
+	for commandID, values := range lists {
+		for _, value := range values {
+			code, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid exit code %q for command %s: %w", value, commandID, err)
+			}
+			codes[commandID] = append(codes[commandID], code)
+		}
+	}
+	return codes, nil
+}
+
+// GetConfig returns the process wide config, loading it on first use. Warnings
+// are logged through the logger attached to ctx, if any, and it panics if the
+// environment cannot be parsed. Prefer LoadConfig where errors can be handled.
 func GetConfig(ctx context.Context) *Config {
 	if appConfig != nil {
 		return appConfig
 	}
 
-	err := godotenv.Load(".env")
-	if err != nil {
-		GetAppLogger(ctx).Warnf("Unable to load .env file. Continuing without loading it...")
+	config, warnings, err := LoadConfig()
+	for _, warning := range warnings {
+		LoggerFromCtx(ctx).Warn(warning)
 	}
-	appConfig = &Config{}
-	if err = env.Parse(appConfig); err != nil {
+	if err != nil {
 		panic(err)
 	}
+	appConfig = config
 	return appConfig
 }
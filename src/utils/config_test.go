@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// chdirTemp moves the test into an empty directory, so no .env file is found
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func TestLoadConfigDoesNotCreateLogger(t *testing.T) {
+	chdirTemp(t)
+	before := appLogger
+
+	config, warnings, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config == nil || len(warnings) != 1 {
+		t.Fatalf("LoadConfig without .env = %v, %q, want a config and the missing file as a warning", config, warnings)
+	}
+	if appLogger != before {
+		t.Fatal("LoadConfig created the global logger")
+	}
+}
+
+func TestGetConfigLogsWarningsToContextLogger(t *testing.T) {
+	chdirTemp(t)
+	previous := appConfig
+	appConfig = nil
+	t.Cleanup(func() { appConfig = previous })
+	before := appLogger
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ctx := LoggerWithCtx(context.Background(), &StandardLogger{SugaredLogger: zap.New(core).Sugar()})
+	if GetConfig(ctx) == nil {
+		t.Fatal("GetConfig returned no config")
+	}
+	if logs.FilterMessageSnippet("Unable to load .env file").Len() != 1 {
+		t.Fatalf("missing .env was not logged to the logger of ctx, logged %v", logs.All())
+	}
+	if appLogger != before {
+		t.Fatal("GetConfig created the global logger")
+	}
+}
+
+func TestLoadConfigReadsCommandDefaultParams(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("CMD_DEFAULT_PARAMS_du", "/var/log")
+	t.Setenv("CMD_DEFAULT_PARAMS_ping", "example.org,2")
+
+	config, _, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"du": {"/var/log"}, "ping": {"example.org", "2"}}
+	for id, params := range want {
+		if got := config.CommandDefaultParams[id]; !slices.Equal(got, params) {
+			t.Errorf("default params of %s = %q, want %q", id, got, params)
+		}
+	}
+}
+
+func TestRedactedHidesPasswordsInLogs(t *testing.T) {
+	config := Config{
+		CacheClusterURL:  "redis.internal",
+		CachePassword:    "cache-secret",
+		SMTPPassword:     "smtp-secret",
+		WebhookURL:       "https://hooks.example.com/webhook-secret",
+		ChangeWebhookURL: "https://hooks.example.com/change-secret",
+	}
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := &StandardLogger{SugaredLogger: zap.New(core).Sugar()}
+	logger.Info("Schedulerx started", "config", config.Redacted())
+
+	output := logs.All()[0].Message
+	for _, secret := range []string{"cache-secret", "smtp-secret", "webhook-secret", "change-secret"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("startup log leaks %s: %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, "redis.internal") || strings.Count(output, "[REDACTED]") != 4 {
+		t.Fatalf("startup log = %s, want the config with passwords and webhook URLs redacted", output)
+	}
+	if config.CachePassword != "cache-secret" {
+		t.Fatal("Redacted modified the config it was called on")
+	}
+	empty := (Config{}).Redacted()
+	if empty.CachePassword != "" || empty.SMTPPassword != "" || empty.WebhookURL != "" || empty.ChangeWebhookURL != "" {
+		t.Fatal("unset secrets were reported as set")
+	}
+}
+
+func TestLoadConfigReadsSuccessExitCodes(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("CMD_SUCCESS_EXIT_CODES_grep", "0, 1")
+
+	config, _, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.CommandSuccessExitCodes["grep"]; !slices.Equal(got, []int{0, 1}) {
+		t.Fatalf("success exit codes of grep = %v, want [0 1]", got)
+	}
+
+	t.Setenv("CMD_SUCCESS_EXIT_CODES_grep", "0,one")
+	if _, _, err := LoadConfig(); err == nil {
+		t.Fatal("invalid exit code was accepted")
+	}
+}
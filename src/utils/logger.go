@@ -28,7 +28,21 @@ func IntegerLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 var appLogger *StandardLogger
 
 // NewLogger creates a new application logger.
+// If the configured logger cannot be built, it falls back to a JSON logger
+// on stdout at INFO level instead of taking down the process.
 func NewLogger() *StandardLogger {
+	logger, err := NewLoggerE()
+	if err != nil {
+		log.Println(
+			fmt.Errorf("failed to build logger, falling back to defaults: %w", err),
+		)
+		return newFallbackLogger()
+	}
+	return logger
+}
+
+// NewLoggerE creates a new application logger, returning an error if it cannot be built.
+func NewLoggerE() (*StandardLogger, error) {
 	var cfg zap.Config
 	outputLevel := zap.InfoLevel
 	levelEnv := os.Getenv("LOG_LEVEL")
@@ -38,8 +52,9 @@ func NewLogger() *StandardLogger {
 			log.Println(
 				fmt.Errorf("invalid level, defaulting to INFO: %w", err),
 			)
+		} else {
+			outputLevel = levelFromEnv
 		}
-		outputLevel = levelFromEnv
 	}
 	var DgnEnv = os.Getenv("DGN")
 	if DgnEnv != "local" {
@@ -56,9 +71,18 @@ func NewLogger() *StandardLogger {
 	}
 	logger, err := cfg.Build()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to build logger: %w", err)
 	}
-	return &StandardLogger{SugaredLogger: logger.Sugar()}
+	return &StandardLogger{SugaredLogger: logger.Sugar()}, nil
+}
+
+// newFallbackLogger builds a logger that cannot fail, used when the configured one can't be built.
+func newFallbackLogger() *StandardLogger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.TimeKey = "time"
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.Lock(os.Stdout), zap.InfoLevel)
+	return &StandardLogger{SugaredLogger: zap.New(core).Sugar()}
 }
 
 func GetAppLogger(ctx context.Context) *StandardLogger {
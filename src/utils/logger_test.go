@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// enabledLevel returns the lowest level the logger writes
+func enabledLevel(logger *StandardLogger) zapcore.Level {
+	return zapcore.LevelOf(logger.Desugar().Core())
+}
+
+func TestNewLoggerEWithInvalidLevels(t *testing.T) {
+	for _, level := range []string{"verbose", "5", "INFO!", " "} {
+		t.Run(level, func(t *testing.T) {
+			t.Setenv("DGN", "")
+			t.Setenv("LOG_LEVEL", level)
+			logger, err := NewLoggerE()
+			if err != nil {
+				t.Fatalf("invalid LOG_LEVEL %q failed the logger: %v", level, err)
+			}
+			if got := enabledLevel(logger); got != zapcore.InfoLevel {
+				t.Fatalf("logger level %s, want %s", got, zapcore.InfoLevel)
+			}
+		})
+	}
+}
+
+func TestNewLoggerEWithValidLevel(t *testing.T) {
+	t.Setenv("DGN", "")
+	t.Setenv("LOG_LEVEL", "warn")
+	logger, err := NewLoggerE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := enabledLevel(logger); got != zapcore.WarnLevel {
+		t.Fatalf("logger level %s, want %s", got, zapcore.WarnLevel)
+	}
+}
+
+func TestFallbackLoggerLogsAtInfo(t *testing.T) {
+	if got := enabledLevel(newFallbackLogger()); got != zapcore.InfoLevel {
+		t.Fatalf("fallback logger level %s, want %s", got, zapcore.InfoLevel)
+	}
+}
+
+func TestGarbageLogLevelOperatesAtInfo(t *testing.T) {
+	t.Setenv("DGN", "")
+	t.Setenv("LOG_LEVEL", "garbage")
+	core := NewLogger().Desugar().Core()
+
+	if !core.Enabled(zapcore.InfoLevel) {
+		t.Fatal("INFO is not logged with LOG_LEVEL=garbage")
+	}
+	if core.Enabled(zapcore.DebugLevel) {
+		t.Fatal("DEBUG is logged with LOG_LEVEL=garbage, want INFO and above only")
+	}
+}